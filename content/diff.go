@@ -0,0 +1,296 @@
+package content
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+)
+
+// Format identifies the kind of content file SniffFormat recognised.
+type Format uint8
+
+// Content file formats.
+const (
+	FormatUnknown Format = iota
+	FormatQuest
+	FormatMapBin
+	FormatMonsterBin
+)
+
+// String returns the lower-case name used in changelogs ("quest", "map",
+// "monster" or "unknown").
+func (f Format) String() string {
+	switch f {
+	case FormatQuest:
+		return "quest"
+	case FormatMapBin:
+		return "map"
+	case FormatMonsterBin:
+		return "monster"
+	default:
+		return "unknown"
+	}
+}
+
+// Encoded record sizes of the count-prefixed bin formats.
+var (
+	mapBinItemSize     = binary.Size(mapbin.MapBinItem{})
+	monsterBinItemSize = binary.Size(monsterbin.MonsterBinItem{})
+)
+
+// SniffFormat reports which content format data holds. A quest file is
+// recognised by parsing it with questfile.Read; a map or monster bin by its
+// entry count matching the file length exactly for that format's record size.
+// Empty bins (count 0) are ambiguous and reported as FormatUnknown.
+func SniffFormat(data []byte) Format {
+	if _, err := questfile.Read(bytes.NewReader(data)); err == nil {
+		return FormatQuest
+	}
+
+	if len(data) < 4 {
+		return FormatUnknown
+	}
+
+	count := int64(binary.LittleEndian.Uint32(data))
+	body := int64(len(data) - 4)
+	switch {
+	case count == 0:
+		return FormatUnknown
+	case count*int64(mapBinItemSize) == body:
+		return FormatMapBin
+	case count*int64(monsterBinItemSize) == body:
+		return FormatMonsterBin
+	default:
+		return FormatUnknown
+	}
+}
+
+// ChangeKind says how an entry differs between two content sets.
+type ChangeKind uint8
+
+// Change kinds.
+const (
+	Added ChangeKind = iota + 1
+	Removed
+	Changed
+)
+
+// String returns "added", "removed" or "changed".
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Change describes one added, removed or changed entry. Fields lists the
+// top-level fields that differ for a Changed entry, in declaration order;
+// quest header fields are prefixed "Header." and objectives are reported as
+// "Objectives[i]".
+type Change struct {
+	Kind   ChangeKind
+	Format Format
+	ID     uint32
+	Fields []string
+}
+
+// String formats the change as a single changelog line, for example
+// "changed quest 12: Header.EXP, Objectives[0]".
+func (c Change) String() string {
+	s := fmt.Sprintf("%s %s %d", c.Kind, c.Format, c.ID)
+	if len(c.Fields) > 0 {
+		s += ": " + strings.Join(c.Fields, ", ")
+	}
+
+	return s
+}
+
+// Changelog lists every difference between two content directories, sorted by
+// format (quests, maps, monsters) and then by ID.
+type Changelog struct {
+	Changes []Change
+}
+
+// String renders the changelog one change per line.
+func (c Changelog) String() string {
+	var b strings.Builder
+	for _, change := range c.Changes {
+		b.WriteString(change.String())
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// DiffDirs compares the content in two directories and reports quests (keyed
+// by QuestID), maps and monsters (keyed by ID) that were added, removed or
+// changed. Every regular file directly inside each directory is classified
+// with SniffFormat; files of unknown format and subdirectories are ignored.
+// When several files define the same ID, the one whose name sorts last wins.
+func DiffDirs(oldPath, newPath string) (Changelog, error) {
+	oldSet, err := loadDir(oldPath)
+	if err != nil {
+		return Changelog{}, err
+	}
+
+	newSet, err := loadDir(newPath)
+	if err != nil {
+		return Changelog{}, err
+	}
+
+	var changelog Changelog
+	changelog.Changes = append(changelog.Changes, diffMaps(FormatQuest, oldSet.quests, newSet.quests, questFields)...)
+	changelog.Changes = append(changelog.Changes, diffMaps(FormatMapBin, oldSet.maps, newSet.maps, structFields[mapbin.MapBinItem])...)
+	changelog.Changes = append(changelog.Changes, diffMaps(FormatMonsterBin, oldSet.monsters, newSet.monsters, structFields[monsterbin.MonsterBinItem])...)
+	return changelog, nil
+}
+
+// contentSet is the content found in one directory, keyed by ID.
+type contentSet struct {
+	quests   map[uint32]questfile.QuestFile
+	maps     map[uint32]mapbin.MapBinItem
+	monsters map[uint32]monsterbin.MonsterBinItem
+}
+
+func loadDir(path string) (contentSet, error) {
+	set := contentSet{
+		quests:   make(map[uint32]questfile.QuestFile),
+		maps:     make(map[uint32]mapbin.MapBinItem),
+		monsters: make(map[uint32]monsterbin.MonsterBinItem),
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return contentSet{}, err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return contentSet{}, err
+		}
+
+		if err := set.add(data); err != nil {
+			return contentSet{}, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+
+	return set, nil
+}
+
+// add decodes data according to its sniffed format and merges it into s.
+func (s *contentSet) add(data []byte) error {
+	r := bytes.NewReader(data)
+	switch SniffFormat(data) {
+	case FormatQuest:
+		q, err := questfile.Read(r)
+		if err != nil {
+			return err
+		}
+
+		s.quests[uint32(q.Header.QuestID())] = q
+	case FormatMapBin:
+		items, err := mapbin.Read(r)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			s.maps[item.ID] = item
+		}
+	case FormatMonsterBin:
+		items, err := monsterbin.Read(r)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			s.monsters[item.ID] = item
+		}
+	}
+
+	return nil
+}
+
+// diffMaps compares two ID-keyed sets and returns their changes sorted by ID.
+func diffMaps[T any](format Format, before, after map[uint32]T, fields func(a, b T) []string) []Change {
+	var changes []Change
+	for id, o := range before {
+		n, ok := after[id]
+		if !ok {
+			changes = append(changes, Change{Kind: Removed, Format: format, ID: id})
+			continue
+		}
+
+		if diff := fields(o, n); len(diff) > 0 {
+			changes = append(changes, Change{Kind: Changed, Format: format, ID: id, Fields: diff})
+		}
+	}
+
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			changes = append(changes, Change{Kind: Added, Format: format, ID: id})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return changes
+}
+
+// structFields returns the names of the top-level fields of a and b that
+// differ, in declaration order.
+func structFields[T any](a, b T) []string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	var fields []string
+	for i := range va.NumField() {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			fields = append(fields, va.Type().Field(i).Name)
+		}
+	}
+
+	return fields
+}
+
+// questFields lists the differing header fields, objectives and continuation
+// of two quests.
+func questFields(a, b questfile.QuestFile) []string {
+	var fields []string
+	for _, f := range structFields(a.Header, b.Header) {
+		fields = append(fields, "Header."+f)
+	}
+
+	for i := range a.Objectives {
+		oa, ob := a.Objectives[i], b.Objectives[i]
+		if oa.Block != ob.Block || !bytes.Equal(oa.Name, ob.Name) {
+			fields = append(fields, fmt.Sprintf("Objectives[%d]", i))
+		}
+	}
+
+	if a.Continuation != b.Continuation {
+		fields = append(fields, "Continuation")
+	}
+
+	return fields
+}
@@ -0,0 +1,91 @@
+package content
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+)
+
+func writeFile(t *testing.T, dir, name string, write func(*bytes.Buffer) error) {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, write(&buf))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o644))
+}
+
+func writeQuest(t *testing.T, dir, name string, q questfile.QuestFile) {
+	writeFile(t, dir, name, func(b *bytes.Buffer) error { return questfile.Write(b, q) })
+}
+
+func TestSniffFormat(t *testing.T) {
+	pack := testPack()
+	var quest, maps, monsters bytes.Buffer
+	require.NoError(t, questfile.Write(&quest, pack.Quests[0]))
+	require.NoError(t, mapbin.Write(&maps, pack.Map))
+	require.NoError(t, monsterbin.Write(&monsters, pack.Monsters))
+
+	assert.Equal(t, FormatQuest, SniffFormat(quest.Bytes()))
+	assert.Equal(t, FormatMapBin, SniffFormat(maps.Bytes()))
+	assert.Equal(t, FormatMonsterBin, SniffFormat(monsters.Bytes()))
+	assert.Equal(t, FormatUnknown, SniffFormat([]byte("hello, world")))
+	assert.Equal(t, FormatUnknown, SniffFormat([]byte{0, 0, 0, 0}))
+	assert.Equal(t, FormatUnknown, SniffFormat(nil))
+}
+
+func TestDiffDirs(t *testing.T) {
+	oldDir, newDir := t.TempDir(), t.TempDir()
+
+	// Quests: 10 unchanged, 11 changed, 12 removed, 13 added.
+	q11 := testQuest(11)
+	writeQuest(t, oldDir, "10.dat", testQuest(10))
+	writeQuest(t, oldDir, "11.dat", q11)
+	writeQuest(t, oldDir, "12.dat", testQuest(12))
+	q11.Header.EXP = 500
+	q11.Objectives[3].Block[questfile.OffMapID] = 9
+	writeQuest(t, newDir, "10.dat", testQuest(10))
+	writeQuest(t, newDir, "11.dat", q11)
+	writeQuest(t, newDir, "13.dat", testQuest(13))
+
+	// Maps: 1 renamed, 2 added.
+	var m1, m2 mapbin.MapBinItem
+	m1.ID, m2.ID = 1, 2
+	copy(m1.Name[:], "Temoz")
+	writeFile(t, oldDir, "map.bin", func(b *bytes.Buffer) error { return mapbin.Write(b, mapbin.MapBin{m1}) })
+	copy(m1.Name[:], "Quanato")
+	writeFile(t, newDir, "map.bin", func(b *bytes.Buffer) error { return mapbin.Write(b, mapbin.MapBin{m1, m2}) })
+
+	// Monsters: 5 removed.
+	writeFile(t, oldDir, "monster.bin", func(b *bytes.Buffer) error {
+		return monsterbin.Write(b, monsterbin.MonsterBin{{ID: 5}})
+	})
+
+	// Unknown files and subdirectories are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(newDir, "README"), []byte("notes"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(newDir, "sub"), 0o755))
+
+	changelog, err := DiffDirs(oldDir, newDir)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Kind: Changed, Format: FormatQuest, ID: 11, Fields: []string{"Header.EXP", "Objectives[3]"}},
+		{Kind: Removed, Format: FormatQuest, ID: 12},
+		{Kind: Added, Format: FormatQuest, ID: 13},
+		{Kind: Changed, Format: FormatMapBin, ID: 1, Fields: []string{"Name"}},
+		{Kind: Added, Format: FormatMapBin, ID: 2},
+		{Kind: Removed, Format: FormatMonsterBin, ID: 5},
+	}, changelog.Changes)
+	assert.Equal(t, "changed quest 11: Header.EXP, Objectives[3]", changelog.Changes[0].String())
+	assert.Contains(t, changelog.String(), "removed monster 5\n")
+}
+
+func TestDiffDirs_MissingDir(t *testing.T) {
+	_, err := DiffDirs(filepath.Join(t.TempDir(), "missing"), t.TempDir())
+	assert.Error(t, err)
+}
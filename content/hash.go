@@ -0,0 +1,100 @@
+package content
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+)
+
+// HashDir returns a SHA-256 digest of the content in the directory at path,
+// suitable as a build cache key. Every regular file directly inside the
+// directory is classified with SniffFormat; files of unknown format and
+// subdirectories are ignored, as in DiffDirs. Each recognised file is decoded
+// and re-encoded with its package's Write function so that only the content
+// it carries is hashed, and its per-file SHA-256 is folded into the digest
+// together with its name and format, in byte order of file name. The result
+// therefore does not depend on directory listing order, file timestamps or
+// the operating system.
+func HashDir(path string) ([32]byte, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	slices.Sort(names)
+
+	digest := sha256.New()
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			return [32]byte{}, err
+		}
+
+		format := SniffFormat(data)
+		if format == FormatUnknown {
+			continue
+		}
+
+		canonical, err := canonicalize(format, data)
+		if err != nil {
+			return [32]byte{}, fmt.Errorf("%s: %w", name, err)
+		}
+
+		sum := sha256.Sum256(canonical)
+		digest.Write([]byte(name))
+		digest.Write([]byte{0, byte(format)})
+		digest.Write(sum[:])
+	}
+
+	var out [32]byte
+	digest.Sum(out[:0])
+	return out, nil
+}
+
+// canonicalize decodes data as format and returns it re-encoded.
+func canonicalize(format Format, data []byte) ([]byte, error) {
+	r := bytes.NewReader(data)
+	var buf bytes.Buffer
+	switch format {
+	case FormatQuest:
+		q, err := questfile.Read(r)
+		if err != nil {
+			return nil, err
+		}
+
+		err = questfile.Write(&buf, q)
+		return buf.Bytes(), err
+	case FormatMapBin:
+		items, err := mapbin.Read(r)
+		if err != nil {
+			return nil, err
+		}
+
+		err = mapbin.Write(&buf, items)
+		return buf.Bytes(), err
+	case FormatMonsterBin:
+		items, err := monsterbin.Read(r)
+		if err != nil {
+			return nil, err
+		}
+
+		err = monsterbin.Write(&buf, items)
+		return buf.Bytes(), err
+	default:
+		return data, nil
+	}
+}
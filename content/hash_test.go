@@ -0,0 +1,73 @@
+package content
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+)
+
+func writeContentDir(t *testing.T, dir string) {
+	t.Helper()
+	pack := testPack()
+	writeQuest(t, dir, "10.dat", pack.Quests[0])
+	writeFile(t, dir, "map.bin", func(b *bytes.Buffer) error { return mapbin.Write(b, pack.Map) })
+	writeFile(t, dir, "monster.bin", func(b *bytes.Buffer) error { return monsterbin.Write(b, pack.Monsters) })
+}
+
+func TestHashDir_Reproducible(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	writeContentDir(t, a)
+	writeContentDir(t, b)
+
+	// Unknown files and subdirectories do not contribute.
+	require.NoError(t, os.WriteFile(filepath.Join(b, "README.txt"), []byte("notes"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(b, "sub"), 0o755))
+
+	hashA, err := HashDir(a)
+	require.NoError(t, err)
+	hashB, err := HashDir(b)
+	require.NoError(t, err)
+	assert.Equal(t, hashA, hashB)
+	assert.NotEqual(t, [32]byte{}, hashA)
+}
+
+func TestHashDir_DetectsChanges(t *testing.T) {
+	dir := t.TempDir()
+	writeContentDir(t, dir)
+	before, err := HashDir(dir)
+	require.NoError(t, err)
+
+	pack := testPack()
+	pack.Quests[0].Header.EXP++
+	writeQuest(t, dir, "10.dat", pack.Quests[0])
+	changed, err := HashDir(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, changed)
+
+	// Renaming a file changes the digest too.
+	writeContentDir(t, dir)
+	require.NoError(t, os.Rename(filepath.Join(dir, "map.bin"), filepath.Join(dir, "map2.bin")))
+	renamed, err := HashDir(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, renamed)
+}
+
+func TestHashDir_Empty(t *testing.T) {
+	a, err := HashDir(t.TempDir())
+	require.NoError(t, err)
+	b, err := HashDir(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+}
+
+func TestHashDir_MissingDir(t *testing.T) {
+	_, err := HashDir(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
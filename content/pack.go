@@ -0,0 +1,230 @@
+// Package content bundles the individual A3 data files into a single content
+// pack: a small header, a manifest listing every entry with its length and
+// CRC-32, and the entry payloads in manifest order. All multi-byte values are
+// little-endian.
+package content
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
+
+	"github.com/cyberinferno/go-utils/utils"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+)
+
+// Format constants.
+const (
+	PackVersion   = 1
+	EntryNameSize = 0x20
+)
+
+// PackMagic identifies a content pack; it is the first four bytes of the file.
+var PackMagic = [4]byte{'A', '3', 'P', 'K'}
+
+// Entry names used in the manifest. Quest entries are named QuestEntryPrefix
+// followed by the quest's index in ContentPack.Quests.
+const (
+	MapBinEntry      = "map.bin"
+	MonsterBinEntry  = "monster.bin"
+	QuestEntryPrefix = "quest/"
+)
+
+// Sentinel errors.
+var (
+	// ErrBadMagic is returned when the stream does not start with PackMagic.
+	ErrBadMagic = errors.New("content: not a content pack")
+
+	// ErrUnsupportedVersion is returned when the pack version is not
+	// PackVersion.
+	ErrUnsupportedVersion = errors.New("content: unsupported pack version")
+
+	// ErrChecksumMismatch is returned when an entry's payload does not match
+	// the CRC-32 recorded in the manifest.
+	ErrChecksumMismatch = errors.New("content: checksum mismatch")
+
+	// ErrUnknownEntry is returned when the manifest names an entry ReadPack
+	// does not know how to decode.
+	ErrUnknownEntry = errors.New("content: unknown entry")
+)
+
+// EntryError annotates an error with the name of the pack entry it applies
+// to. Use errors.Is on it to test for the underlying error.
+type EntryError struct {
+	Entry string
+	Err   error
+}
+
+func (e *EntryError) Error() string {
+	return fmt.Sprintf("entry %q: %v", e.Entry, e.Err)
+}
+
+func (e *EntryError) Unwrap() error {
+	return e.Err
+}
+
+// ContentPack is the in-memory form of a content pack.
+type ContentPack struct {
+	Map      mapbin.MapBin
+	Monsters monsterbin.MonsterBin
+	Quests   []questfile.QuestFile
+}
+
+// packHeader is the fixed header at the start of a pack.
+type packHeader struct {
+	Magic      [4]byte
+	Version    uint32
+	EntryCount uint32
+}
+
+// manifestEntry describes one payload in the pack.
+type manifestEntry struct {
+	Name   [EntryNameSize]byte
+	Length uint32
+	CRC32  uint32
+}
+
+// WritePack writes pack to w: header, manifest, then each entry payload
+// encoded with its package's Write function. The map bin and monster bin are
+// always written, followed by one entry per quest in slice order.
+func WritePack(w io.Writer, pack ContentPack) error {
+	names := []string{MapBinEntry, MonsterBinEntry}
+	payloads := make([][]byte, 0, 2+len(pack.Quests))
+
+	var buf bytes.Buffer
+	if err := mapbin.Write(&buf, pack.Map); err != nil {
+		return &EntryError{Entry: MapBinEntry, Err: err}
+	}
+	payloads = append(payloads, bytes.Clone(buf.Bytes()))
+
+	buf.Reset()
+	if err := monsterbin.Write(&buf, pack.Monsters); err != nil {
+		return &EntryError{Entry: MonsterBinEntry, Err: err}
+	}
+	payloads = append(payloads, bytes.Clone(buf.Bytes()))
+
+	for i := range pack.Quests {
+		name := fmt.Sprintf("%s%d", QuestEntryPrefix, i)
+		buf.Reset()
+		if err := questfile.Write(&buf, pack.Quests[i]); err != nil {
+			return &EntryError{Entry: name, Err: err}
+		}
+
+		names = append(names, name)
+		payloads = append(payloads, bytes.Clone(buf.Bytes()))
+	}
+
+	header := packHeader{Magic: PackMagic, Version: PackVersion, EntryCount: uint32(len(payloads))}
+	if err := binary.Write(w, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+
+	for i, p := range payloads {
+		entry := manifestEntry{Length: uint32(len(p)), CRC32: crc32.ChecksumIEEE(p)}
+		copy(entry.Name[:], utils.MakeFixedLengthStringBytes(names[i], EntryNameSize))
+		if err := binary.Write(w, binary.LittleEndian, &entry); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range payloads {
+		if _, err := w.Write(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadPack reads a content pack from r and verifies every entry against its
+// manifest CRC-32 before decoding it.
+//
+// Error conditions:
+//   - ErrBadMagic / ErrUnsupportedVersion – the header is not a supported pack
+//   - io.ErrUnexpectedEOF – the pack is truncated
+//   - *EntryError wrapping ErrChecksumMismatch – an entry's payload is corrupt
+//   - *EntryError wrapping ErrUnknownEntry – the manifest names an unknown entry
+//   - *EntryError wrapping a decode error – a payload failed to parse
+func ReadPack(r io.Reader) (ContentPack, error) {
+	var header packHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return ContentPack{}, unexpectedEOF(err)
+	}
+
+	if header.Magic != PackMagic {
+		return ContentPack{}, ErrBadMagic
+	}
+
+	if header.Version != PackVersion {
+		return ContentPack{}, ErrUnsupportedVersion
+	}
+
+	var manifest []manifestEntry
+	for range header.EntryCount {
+		var entry manifestEntry
+		if err := binary.Read(r, binary.LittleEndian, &entry); err != nil {
+			return ContentPack{}, unexpectedEOF(err)
+		}
+
+		manifest = append(manifest, entry)
+	}
+
+	var pack ContentPack
+	for _, entry := range manifest {
+		name := utils.ReadStringFromBytes(entry.Name[:])
+
+		// CopyN grows the buffer as data arrives, so a corrupt length cannot
+		// force a huge allocation up front.
+		var payload bytes.Buffer
+		if _, err := io.CopyN(&payload, r, int64(entry.Length)); err != nil {
+			return ContentPack{}, unexpectedEOF(err)
+		}
+
+		if crc32.ChecksumIEEE(payload.Bytes()) != entry.CRC32 {
+			return ContentPack{}, &EntryError{Entry: name, Err: ErrChecksumMismatch}
+		}
+
+		if err := pack.decodeEntry(name, &payload); err != nil {
+			return ContentPack{}, &EntryError{Entry: name, Err: err}
+		}
+	}
+
+	return pack, nil
+}
+
+// decodeEntry decodes one verified payload into the matching field of p.
+func (p *ContentPack) decodeEntry(name string, payload io.Reader) error {
+	var err error
+	switch {
+	case name == MapBinEntry:
+		p.Map, err = mapbin.Read(payload)
+	case name == MonsterBinEntry:
+		p.Monsters, err = monsterbin.Read(payload)
+	case strings.HasPrefix(name, QuestEntryPrefix):
+		var q questfile.QuestFile
+		if q, err = questfile.Read(payload); err == nil {
+			p.Quests = append(p.Quests, q)
+		}
+	default:
+		err = ErrUnknownEntry
+	}
+
+	return err
+}
+
+// unexpectedEOF maps io.EOF to io.ErrUnexpectedEOF: once the header has been
+// requested, running out of data always means the pack is truncated.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,109 @@
+package content
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+)
+
+func testQuest(id uint16) questfile.QuestFile {
+	var q questfile.QuestFile
+	q.Header.SetQuestID(id)
+	for i := range q.Objectives {
+		q.Objectives[i].Block[questfile.OffType] = byte(questfile.TypeKILL)
+	}
+	q.Objectives[0].Block[questfile.OffType] = byte(questfile.TypeDROP)
+	q.Objectives[0].Block[questfile.OffNameLen] = 4
+	q.Objectives[0].Name = []byte("Wolf")
+	q.Continuation = [3]uint32{questfile.UnusedContinuation, questfile.UnusedContinuation, questfile.UnusedContinuation}
+	return q
+}
+
+func testPack() ContentPack {
+	var m mapbin.MapBinItem
+	m.ID = 1
+	copy(m.Name[:], "Temoz")
+	var mon monsterbin.MonsterBinItem
+	mon.ID = 2
+	copy(mon.Name[:], "Wolf")
+
+	return ContentPack{
+		Map:      mapbin.MapBin{m},
+		Monsters: monsterbin.MonsterBin{mon},
+		Quests:   []questfile.QuestFile{testQuest(10), testQuest(11)},
+	}
+}
+
+func TestWritePack_ReadPack_RoundTrip(t *testing.T) {
+	pack := testPack()
+	var buf bytes.Buffer
+	require.NoError(t, WritePack(&buf, pack))
+
+	got, err := ReadPack(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, pack, got)
+}
+
+func TestReadPack_ChecksumMismatchNamesEntry(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePack(&buf, testPack()))
+	data := buf.Bytes()
+	data[len(data)-1] ^= 0xFF // last byte belongs to the last quest
+
+	_, err := ReadPack(bytes.NewReader(data))
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	var entryErr *EntryError
+	require.ErrorAs(t, err, &entryErr)
+	assert.Equal(t, "quest/1", entryErr.Entry)
+}
+
+func TestReadPack_BadMagic(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePack(&buf, testPack()))
+	data := buf.Bytes()
+	data[0] = 'X'
+
+	_, err := ReadPack(bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrBadMagic)
+}
+
+func TestReadPack_UnsupportedVersion(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePack(&buf, testPack()))
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint32(data[4:], PackVersion+1)
+
+	_, err := ReadPack(bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestReadPack_Truncated(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePack(&buf, testPack()))
+	data := buf.Bytes()
+
+	for _, n := range []int{0, 6, 20, len(data) - 1} {
+		_, err := ReadPack(bytes.NewReader(data[:n]))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "length %d", n)
+	}
+}
+
+func TestReadPack_UnknownEntry(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, &packHeader{Magic: PackMagic, Version: PackVersion, EntryCount: 1}))
+	entry := manifestEntry{Length: 1, CRC32: 0xD202EF8D} // CRC-32 of a single zero byte
+	copy(entry.Name[:], "readme.txt")
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, &entry))
+	buf.WriteByte(0)
+
+	_, err := ReadPack(&buf)
+	assert.ErrorIs(t, err, ErrUnknownEntry)
+}
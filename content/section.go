@@ -0,0 +1,165 @@
+package content
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/npcfile"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+	"github.com/project-agonyl/agonyl-utils-go/spawnlist"
+)
+
+// SectionTag identifies the format of a section in a server data file.
+type SectionTag uint8
+
+// Section tags. Tags not listed here are skipped by SectionReader.
+const (
+	SectionQuestArchive SectionTag = iota + 1 // uint32 count, then quest files
+	SectionSpawnList                          // spawnlist file
+	SectionMapBin                             // mapbin file
+	SectionMonsterBin                         // monsterbin file
+	SectionNPCs                               // npcfile records back to back
+)
+
+// String returns the lower-case name of the tag, or "tag(n)" for unknown
+// tags.
+func (t SectionTag) String() string {
+	switch t {
+	case SectionQuestArchive:
+		return "quests"
+	case SectionSpawnList:
+		return "spawns"
+	case SectionMapBin:
+		return "map"
+	case SectionMonsterBin:
+		return "monster"
+	case SectionNPCs:
+		return "npcs"
+	default:
+		return fmt.Sprintf("tag(%d)", uint8(t))
+	}
+}
+
+// sectionHeaderSize is the size of a section's tag byte and length.
+const sectionHeaderSize = 5
+
+// ErrSectionLength is returned when a section's payload holds more bytes than
+// its format decoder consumed.
+var ErrSectionLength = errors.New("content: section length does not match its payload")
+
+// SectionError annotates an error with the zero-based index and tag of the
+// section it applies to. Skipped sections count towards Index.
+type SectionError struct {
+	Index int
+	Tag   SectionTag
+	Err   error
+}
+
+func (e *SectionError) Error() string {
+	return fmt.Sprintf("section %d (%s): %v", e.Index, e.Tag, e.Err)
+}
+
+func (e *SectionError) Unwrap() error {
+	return e.Err
+}
+
+// Section is one decoded section. Tag says which of the other fields is set.
+type Section struct {
+	Tag      SectionTag
+	Quests   []questfile.QuestFile
+	Spawns   spawnlist.SpawnList
+	Map      mapbin.MapBin
+	Monsters monsterbin.MonsterBin
+	NPCs     []npcfile.NPCFileData
+}
+
+// SectionReader reads a server data file made of tagged sections. Each
+// section is a 1-byte SectionTag, a little-endian uint32 payload length and
+// the payload, which is decoded with the Read function of the matching
+// package.
+type SectionReader struct {
+	r     io.Reader
+	index int
+}
+
+// NewSectionReader returns a SectionReader reading sections from r.
+func NewSectionReader(r io.Reader) *SectionReader {
+	return &SectionReader{r: r}
+}
+
+// Next reads and decodes the next section with a known tag, skipping any
+// sections with unknown tags by their length. It returns io.EOF when r ends
+// cleanly between sections. Other errors are returned as a *SectionError:
+//   - io.ErrUnexpectedEOF – the stream ends inside a section
+//   - ErrSectionLength – the payload has bytes left after decoding
+//   - a decode error from the section's package
+//
+// A section that fails to decode is still consumed in full, so Next can be
+// called again to continue with the following section; after a truncation
+// or a read error from r every later call fails too.
+func (s *SectionReader) Next() (Section, error) {
+	for {
+		var header [sectionHeaderSize]byte
+		if _, err := io.ReadFull(s.r, header[:]); err != nil {
+			if err == io.EOF {
+				return Section{}, io.EOF
+			}
+
+			return Section{}, &SectionError{Index: s.index, Err: err}
+		}
+
+		tag := SectionTag(header[0])
+		length := int64(binary.LittleEndian.Uint32(header[1:]))
+		index := s.index
+		s.index++
+
+		payload := &io.LimitedReader{R: s.r, N: length}
+		sec, known, err := decodeSection(tag, payload)
+		left := payload.N
+		if _, drainErr := io.Copy(io.Discard, payload); drainErr != nil {
+			err = drainErr
+		} else if payload.N > 0 {
+			err = io.ErrUnexpectedEOF
+		} else if known && err == nil && left > 0 {
+			err = ErrSectionLength
+		}
+
+		if err != nil {
+			return Section{}, &SectionError{Index: index, Tag: tag, Err: err}
+		}
+
+		if known {
+			return sec, nil
+		}
+	}
+}
+
+// decodeSection decodes a payload with the reader for tag. known is false,
+// and nothing is read, for unknown tags.
+func decodeSection(tag SectionTag, r io.Reader) (sec Section, known bool, err error) {
+	sec.Tag = tag
+	switch tag {
+	case SectionQuestArchive:
+		sec.Quests, err = questfile.ReadArchive(r)
+		if errors.Is(err, questfile.ErrTrailingBytes) {
+			// Bytes after the last quest are still inside the payload.
+			err = ErrSectionLength
+		}
+	case SectionSpawnList:
+		sec.Spawns, err = spawnlist.Read(r)
+	case SectionMapBin:
+		sec.Map, err = mapbin.Read(r)
+	case SectionMonsterBin:
+		sec.Monsters, err = monsterbin.Read(r)
+	case SectionNPCs:
+		sec.NPCs, err = npcfile.ReadAll(r)
+	default:
+		return Section{}, false, nil
+	}
+
+	return sec, true, err
+}
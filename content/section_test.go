@@ -0,0 +1,163 @@
+package content
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/npcfile"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+	"github.com/project-agonyl/agonyl-utils-go/spawnlist"
+)
+
+// section frames payload with a tag byte and little-endian length.
+func section(tag SectionTag, payload []byte) []byte {
+	out := binary.LittleEndian.AppendUint32([]byte{byte(tag)}, uint32(len(payload)))
+	return append(out, payload...)
+}
+
+func encode(t *testing.T, write func(*bytes.Buffer) error) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, write(&buf))
+	return buf.Bytes()
+}
+
+func questArchive(t *testing.T, quests ...questfile.QuestFile) []byte {
+	t.Helper()
+	out := binary.LittleEndian.AppendUint32(nil, uint32(len(quests)))
+	for _, q := range quests {
+		out = append(out, encode(t, func(b *bytes.Buffer) error { return questfile.Write(b, q) })...)
+	}
+
+	return out
+}
+
+func TestSectionReader_ReadsEveryFormat(t *testing.T) {
+	pack := testPack()
+	spawns := spawnlist.SpawnList{{Id: 7, X: 1, Y: 2}}
+	var npc npcfile.NPCFileData
+	npc.Id = 3
+
+	var data []byte
+	data = append(data, section(SectionQuestArchive, questArchive(t, pack.Quests...))...)
+	data = append(data, section(SectionSpawnList, encode(t, func(b *bytes.Buffer) error { return spawnlist.Write(b, spawns) }))...)
+	data = append(data, section(SectionMapBin, encode(t, func(b *bytes.Buffer) error { return mapbin.Write(b, pack.Map) }))...)
+	data = append(data, section(SectionMonsterBin, encode(t, func(b *bytes.Buffer) error { return monsterbin.Write(b, pack.Monsters) }))...)
+	data = append(data, section(SectionNPCs, encode(t, func(b *bytes.Buffer) error { return npcfile.Write(b, npc) }))...)
+
+	r := NewSectionReader(bytes.NewReader(data))
+	sec, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Section{Tag: SectionQuestArchive, Quests: pack.Quests}, sec)
+
+	sec, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Section{Tag: SectionSpawnList, Spawns: spawns}, sec)
+
+	sec, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Section{Tag: SectionMapBin, Map: pack.Map}, sec)
+
+	sec, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Section{Tag: SectionMonsterBin, Monsters: pack.Monsters}, sec)
+
+	sec, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, Section{Tag: SectionNPCs, NPCs: []npcfile.NPCFileData{npc}}, sec)
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestSectionReader_SkipsUnknownTags(t *testing.T) {
+	pack := testPack()
+	var data []byte
+	data = append(data, section(0xEE, []byte("opaque payload"))...)
+	data = append(data, section(SectionMapBin, encode(t, func(b *bytes.Buffer) error { return mapbin.Write(b, pack.Map) }))...)
+	data = append(data, section(0xEF, nil)...)
+
+	r := NewSectionReader(bytes.NewReader(data))
+	sec, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, SectionMapBin, sec.Tag)
+	assert.Equal(t, pack.Map, sec.Map)
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestSectionReader_Empty(t *testing.T) {
+	_, err := NewSectionReader(bytes.NewReader(nil)).Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestSectionReader_Truncated(t *testing.T) {
+	pack := testPack()
+	full := section(SectionMapBin, encode(t, func(b *bytes.Buffer) error { return mapbin.Write(b, pack.Map) }))
+
+	for _, n := range []int{2, sectionHeaderSize, len(full) - 1} {
+		_, err := NewSectionReader(bytes.NewReader(full[:n])).Next()
+		var secErr *SectionError
+		require.ErrorAs(t, err, &secErr, "length %d", n)
+		assert.Equal(t, 0, secErr.Index)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "length %d", n)
+	}
+
+	// Unknown sections are skipped by length, so truncation is still caught.
+	_, err := NewSectionReader(bytes.NewReader(section(0xEE, []byte("abc"))[:6])).Next()
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestSectionReader_PayloadLongerThanContent(t *testing.T) {
+	pack := testPack()
+	payload := encode(t, func(b *bytes.Buffer) error { return monsterbin.Write(b, pack.Monsters) })
+	data := section(SectionMonsterBin, append(payload, 0xAA, 0xBB))
+	data = append(data, section(SectionMapBin, encode(t, func(b *bytes.Buffer) error { return mapbin.Write(b, pack.Map) }))...)
+
+	r := NewSectionReader(bytes.NewReader(data))
+	_, err := r.Next()
+	var secErr *SectionError
+	require.ErrorAs(t, err, &secErr)
+	assert.Equal(t, SectionMonsterBin, secErr.Tag)
+	assert.ErrorIs(t, err, ErrSectionLength)
+
+	// The bad section was consumed in full, so reading continues.
+	sec, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, pack.Map, sec.Map)
+}
+
+func TestSectionReader_QuestPayloadLongerThanArchive(t *testing.T) {
+	data := section(SectionQuestArchive, append(questArchive(t, testQuest(10)), 0xAA))
+
+	_, err := NewSectionReader(bytes.NewReader(data)).Next()
+	var secErr *SectionError
+	require.ErrorAs(t, err, &secErr)
+	assert.Equal(t, SectionQuestArchive, secErr.Tag)
+	assert.ErrorIs(t, err, ErrSectionLength)
+}
+
+func TestSectionReader_DecodeErrorNamesSection(t *testing.T) {
+	archive := questArchive(t, testQuest(10))
+	archive[0] = 2 // announces a second quest that is not there
+
+	var data []byte
+	data = append(data, section(0xEE, nil)...)
+	data = append(data, section(SectionQuestArchive, archive)...)
+
+	_, err := NewSectionReader(bytes.NewReader(data)).Next()
+	var secErr *SectionError
+	require.ErrorAs(t, err, &secErr)
+	assert.Equal(t, 1, secErr.Index)
+	assert.Equal(t, SectionQuestArchive, secErr.Tag)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, "section 1 (quests): quest 1 at offset 788: unexpected EOF", err.Error())
+}
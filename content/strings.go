@@ -0,0 +1,149 @@
+package content
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/npcfile"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+	agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+)
+
+// Keys of the translatable strings returned by ExtractStrings. They are built
+// from record IDs and objective slots only, so they stay the same across
+// builds as long as the IDs do.
+func questObjectiveKey(questID uint16, slot int) string {
+	return fmt.Sprintf("quest:%d:obj:%d", questID, slot)
+}
+
+func mapNameKey(id uint32) string {
+	return fmt.Sprintf("map:%d:name", id)
+}
+
+func monsterNameKey(id uint32) string {
+	return fmt.Sprintf("monster:%d:name", id)
+}
+
+func npcNameKey(id uint16) string {
+	return fmt.Sprintf("npc:%d:name", id)
+}
+
+// ExtractStrings collects every translatable string of a content set, keyed
+// by a stable ID: "quest:<QuestID>:obj:<slot>" for objective names,
+// "map:<ID>:name", "monster:<ID>:name" and "npc:<Id>:name" for record names.
+// Names are decoded from CP949 to UTF-8; bytes that do not decode are kept
+// as-is. Empty names and unused objective slots are left out. Records that
+// share an ID share a key, and the last one wins.
+func ExtractStrings(quests []questfile.QuestFile, mb mapbin.MapBin, monb monsterbin.MonsterBin, npcs []npcfile.NPCFileData) map[string]string {
+	out := make(map[string]string)
+	for i := range quests {
+		id := quests[i].Header.QuestID()
+		for slot := range quests[i].Objectives {
+			o := &quests[i].Objectives[slot]
+			if o.IsActive() && len(o.Name) > 0 {
+				out[questObjectiveKey(id, slot)] = decodeName(o.Name)
+			}
+		}
+	}
+
+	addFixed := func(key string, field []byte) {
+		if name, _ := agutils.ReadStringChecked(field); name != "" {
+			out[key] = decodeName([]byte(name))
+		}
+	}
+
+	for i := range mb {
+		addFixed(mapNameKey(mb[i].ID), mb[i].Name[:])
+	}
+
+	for i := range monb {
+		addFixed(monsterNameKey(monb[i].ID), monb[i].Name[:])
+	}
+
+	for i := range npcs {
+		addFixed(npcNameKey(npcs[i].Id), npcs[i].Name[:])
+	}
+
+	return out
+}
+
+// ApplyStrings writes translations back into a content set, the inverse of
+// ExtractStrings: every record or objective whose key is in translations
+// gets that string, encoded to CP949, as its name. The slices are modified in
+// place. Keys that match nothing are ignored, so one translation file can be
+// applied to part of a set, and records that share an ID all get the
+// translation.
+//
+// Fixed-size names longer than their field are truncated at a character
+// boundary, as utils.SetFixedString does. An objective name is set together
+// with its name-length byte; a name longer than questfile.MaxNameLength, or
+// a translation for an objective type that cannot carry a name, is not
+// applied. Problems, including strings CP949 cannot represent, are returned
+// joined with errors.Join, each naming its key; everything else is still
+// applied.
+func ApplyStrings(translations map[string]string, quests []questfile.QuestFile, mb mapbin.MapBin, monb monsterbin.MonsterBin, npcs []npcfile.NPCFileData) error {
+	var errs []error
+	for i := range quests {
+		id := quests[i].Header.QuestID()
+		for slot := range quests[i].Objectives {
+			key := questObjectiveKey(id, slot)
+			s, ok := translations[key]
+			if !ok {
+				continue
+			}
+
+			if err := setObjectiveName(&quests[i].Objectives[slot], s); err != nil {
+				errs = append(errs, fmt.Errorf("content: %s: %w", key, err))
+			}
+		}
+	}
+
+	setFixed := func(key string, field []byte) {
+		s, ok := translations[key]
+		if !ok {
+			return
+		}
+
+		if err := agutils.SetFixedString(field, s, agutils.EncodingCP949); err != nil {
+			errs = append(errs, fmt.Errorf("content: %s: %w", key, err))
+		}
+	}
+
+	for i := range mb {
+		setFixed(mapNameKey(mb[i].ID), mb[i].Name[:])
+	}
+
+	for i := range monb {
+		setFixed(monsterNameKey(monb[i].ID), monb[i].Name[:])
+	}
+
+	for i := range npcs {
+		setFixed(npcNameKey(npcs[i].Id), npcs[i].Name[:])
+	}
+
+	return errors.Join(errs...)
+}
+
+// decodeName converts a CP949 name to UTF-8, falling back to the raw bytes if
+// they do not decode.
+func decodeName(b []byte) string {
+	s, err := agutils.DecodeCP949(b)
+	if err != nil {
+		return string(b)
+	}
+
+	return s
+}
+
+// setObjectiveName stores s, encoded to CP949, as o's name and updates the
+// name-length byte. o is left unchanged on error.
+func setObjectiveName(o *questfile.Objective, s string) error {
+	name, err := agutils.EncodeCP949(s)
+	if err != nil {
+		return err
+	}
+
+	return o.SetName(string(name))
+}
@@ -0,0 +1,76 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project-agonyl/agonyl-utils-go/mapbin"
+	"github.com/project-agonyl/agonyl-utils-go/monsterbin"
+	"github.com/project-agonyl/agonyl-utils-go/npcfile"
+	"github.com/project-agonyl/agonyl-utils-go/questfile"
+	agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+)
+
+func stringsFixture(t *testing.T) ([]questfile.QuestFile, mapbin.MapBin, monsterbin.MonsterBin, []npcfile.NPCFileData) {
+	t.Helper()
+	var q questfile.QuestFile
+	q.Header.SetQuestID(12)
+	for i := range q.Objectives {
+		q.Objectives[i].Block[questfile.OffType] = byte(questfile.TypeUnused)
+	}
+	q.Objectives[2].Block[questfile.OffType] = byte(questfile.TypeFIND)
+	q.Objectives[2].Name = []byte("Hidden Cave")
+	q.Objectives[2].Block[questfile.OffNameLen] = uint8(len(q.Objectives[2].Name))
+
+	mb := mapbin.MapBin{{ID: 5}}
+	copy(mb[0].Name[:], "Temoz")
+	monb := monsterbin.MonsterBin{{ID: 9}, {ID: 10}} // 10 has no name
+	require.NoError(t, agutils.SetFixedString(monb[0].Name[:], "늑대", agutils.EncodingCP949))
+	npcs := []npcfile.NPCFileData{{Id: 3}}
+	copy(npcs[0].Name[:], "Guard")
+	return []questfile.QuestFile{q}, mb, monb, npcs
+}
+
+func TestExtractStrings(t *testing.T) {
+	quests, mb, monb, npcs := stringsFixture(t)
+	assert.Equal(t, map[string]string{
+		"quest:12:obj:2": "Hidden Cave",
+		"map:5:name":     "Temoz",
+		"monster:9:name": "늑대",
+		"npc:3:name":     "Guard",
+	}, ExtractStrings(quests, mb, monb, npcs))
+}
+
+func TestApplyStrings_RoundTrip(t *testing.T) {
+	quests, mb, monb, npcs := stringsFixture(t)
+	translations := map[string]string{
+		"quest:12:obj:2": "숨겨진 동굴",
+		"map:5:name":     "테모즈",
+		"monster:9:name": "Wolf",
+		"npc:3:name":     "경비병",
+		"npc:99:name":    "ignored",
+	}
+
+	require.NoError(t, ApplyStrings(translations, quests, mb, monb, npcs))
+	delete(translations, "npc:99:name")
+	assert.Equal(t, translations, ExtractStrings(quests, mb, monb, npcs))
+	assert.NoError(t, quests[0].Validate())
+}
+
+func TestApplyStrings_Errors(t *testing.T) {
+	quests, mb, monb, npcs := stringsFixture(t)
+	quests[0].Objectives[0].Block[questfile.OffType] = byte(questfile.TypeKILL)
+	err := ApplyStrings(map[string]string{
+		"quest:12:obj:0": "Wolves",
+		"quest:12:obj:2": "\U0001F600",
+		"map:5:name":     "Temoz Canyon",
+	}, quests, mb, monb, npcs)
+
+	require.ErrorIs(t, err, questfile.ErrNameLengthForType)
+	assert.Contains(t, err.Error(), "quest:12:obj:0")
+	assert.Contains(t, err.Error(), "quest:12:obj:2")
+	assert.Equal(t, "Hidden Cave", string(quests[0].Objectives[2].Name), "failed translations are not applied")
+	assert.Equal(t, "Temoz Canyon", mb[0].GetName(), "other translations still are")
+}
@@ -19,16 +19,32 @@ type crypto562 struct {
 	dynamicKey2 byte
 	constKeyEn  uint32
 	constKeyDe  uint32
+	offset      int
 }
 
-// offset is the starting point for the 562 cipher.
-const offset = 0x0C
+// DefaultOffset is the starting point for the 562 cipher in standard packets:
+// the 12-byte message header is left in plain text.
+const DefaultOffset = 0x0C
+
+// Option configures a Crypto returned by NewCrypto562.
+type Option func(*crypto562)
+
+// WithOffset makes the cipher start transforming at byte offset instead of
+// DefaultOffset, for packets whose encrypted region begins elsewhere (for
+// example handshake packets that start encryption at byte 4). Encrypt and
+// decrypt must use the same offset. A negative offset is treated as 0.
+func WithOffset(offset int) Option {
+	return func(c *crypto562) {
+		c.offset = max(offset, 0)
+	}
+}
 
 // NewCrypto562 returns a Crypto implementation using the 562 cipher with
 // the given dynamic key. The dynamic key is typically derived from
 // session or packet context and must match between encrypt and decrypt.
-func NewCrypto562(dynamicKey int) Crypto {
-	return &crypto562{
+// Transformation starts at DefaultOffset unless WithOffset is given.
+func NewCrypto562(dynamicKey int, opts ...Option) Crypto {
+	c := &crypto562{
 		constKey1:   0x241AE7,
 		constKey2:   0x15DCB2,
 		dynamicKey:  dynamicKey,
@@ -36,15 +52,21 @@ func NewCrypto562(dynamicKey int) Crypto {
 		dynamicKey2: 0x01,
 		constKeyEn:  0xA7F0753B,
 		constKeyDe:  0xAAF29BF3,
+		offset:      DefaultOffset,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
 // DecryptInPlace decrypts data in place using the 562 cipher.
-// Only bytes from offset onward are modified, in 4-byte blocks.
+// Only bytes from the configured offset onward are modified, in 4-byte blocks.
 // Data is modified in place; the slice length is unchanged.
 func (c *crypto562) DecryptInPlace(data []byte) {
 	bufferLen := len(data)
-	for i := offset; i+4 <= bufferLen; i += 4 {
+	for i := c.offset; i+4 <= bufferLen; i += 4 {
 		DynamicKey := c.dynamicKey
 		for j := i; j < i+4; j++ {
 			pSrc := data[j]
@@ -55,11 +77,11 @@ func (c *crypto562) DecryptInPlace(data []byte) {
 }
 
 // EncryptInPlace encrypts data in place using the 562 cipher.
-// Only bytes from offset onward are modified, in 4-byte blocks.
+// Only bytes from the configured offset onward are modified, in 4-byte blocks.
 // Data is modified in place; the slice length is unchanged.
 func (c *crypto562) EncryptInPlace(data []byte) {
 	bufferLen := len(data)
-	for i := offset; i+4 <= bufferLen; i += 4 {
+	for i := c.offset; i+4 <= bufferLen; i += 4 {
 		DynamicKey := c.dynamicKey
 		for j := i; j < i+4; j++ {
 			data[j] = data[j] ^ byte(DynamicKey>>8)
@@ -120,3 +120,48 @@ func TestEncryptInPlace_MultipleBlocks(t *testing.T) {
 	c.DecryptInPlace(data)
 	assert.Equal(t, original, data, "Round-trip should hold for multiple blocks")
 }
+
+func TestWithOffset_StartsAtCustomOffset(t *testing.T) {
+	c := NewCrypto562(0x1234, WithOffset(4))
+	original := make([]byte, 16)
+	for i := range original {
+		original[i] = byte(i + 1)
+	}
+	data := make([]byte, len(original))
+	copy(data, original)
+
+	c.EncryptInPlace(data)
+	assert.Equal(t, original[:4], data[:4], "bytes before the offset must be untouched")
+	assert.NotEqual(t, original[4:8], data[4:8], "block at the custom offset must be encrypted")
+
+	c.DecryptInPlace(data)
+	assert.Equal(t, original, data)
+}
+
+func TestWithOffset_DefaultMatchesPlainConstructor(t *testing.T) {
+	plain := make([]byte, 24)
+	for i := range plain {
+		plain[i] = byte(i * 3)
+	}
+	a := make([]byte, len(plain))
+	b := make([]byte, len(plain))
+	copy(a, plain)
+	copy(b, plain)
+
+	NewCrypto562(99).EncryptInPlace(a)
+	NewCrypto562(99, WithOffset(DefaultOffset)).EncryptInPlace(b)
+	assert.Equal(t, a, b)
+}
+
+func TestWithOffset_MismatchedOffsetsDoNotRoundTrip(t *testing.T) {
+	plain := make([]byte, 24)
+	for i := range plain {
+		plain[i] = byte(i)
+	}
+	data := make([]byte, len(plain))
+	copy(data, plain)
+
+	NewCrypto562(7, WithOffset(4)).EncryptInPlace(data)
+	NewCrypto562(7).DecryptInPlace(data)
+	assert.NotEqual(t, plain, data, "encrypt and decrypt must use the same offset")
+}
@@ -0,0 +1,30 @@
+package crypto
+
+import "os"
+
+// WriteEncryptedFile encrypts a copy of plaintext with c and writes it to
+// path, creating or truncating the file with mode 0644. plaintext itself is
+// never modified, so it may be a buffer shared with other code. Which bytes are
+// encrypted is decided by c: with the default options the first DefaultOffset
+// bytes stay in the clear.
+func WriteEncryptedFile(path string, plaintext []byte, c Crypto) error {
+	data := make([]byte, len(plaintext))
+	copy(data, plaintext)
+	c.EncryptInPlace(data)
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ReadEncryptedFile reads the file at path and returns its contents
+// decrypted with c. c must be configured exactly as the Crypto used to write
+// the file (same dynamic key and offset).
+func ReadEncryptedFile(path string, c Crypto) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.DecryptInPlace(data)
+
+	return data, nil
+}
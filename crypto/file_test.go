@@ -0,0 +1,46 @@
+package crypto
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedFile_RoundTrip(t *testing.T) {
+	c := NewCrypto562(0x1234)
+	plaintext := bytes.Repeat([]byte{0x11, 0x22, 0x33, 0x44}, 10)
+	original := bytes.Clone(plaintext)
+	path := filepath.Join(t.TempDir(), "quest.dat")
+
+	require.NoError(t, WriteEncryptedFile(path, plaintext, c))
+	assert.Equal(t, original, plaintext, "WriteEncryptedFile must not mutate its input")
+
+	onDisk, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotEqual(t, plaintext, onDisk)
+	assert.Equal(t, plaintext[:DefaultOffset], onDisk[:DefaultOffset], "header stays in the clear")
+
+	got, err := ReadEncryptedFile(path, c)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, got)
+}
+
+func TestEncryptedFile_WithOffset(t *testing.T) {
+	c := NewCrypto562(0x4321, WithOffset(0))
+	plaintext := bytes.Repeat([]byte{0xAB}, 16)
+	path := filepath.Join(t.TempDir(), "monster.bin")
+
+	require.NoError(t, WriteEncryptedFile(path, plaintext, c))
+	got, err := ReadEncryptedFile(path, c)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, got)
+}
+
+func TestReadEncryptedFile_Missing(t *testing.T) {
+	_, err := ReadEncryptedFile(filepath.Join(t.TempDir(), "missing"), NewCrypto562(1))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
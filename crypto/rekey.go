@@ -0,0 +1,15 @@
+package crypto
+
+// Rekey returns a copy of data decrypted with from and re-encrypted with to,
+// for replaying a captured packet under a different session key. data itself
+// is never modified, so it may point into a shared capture buffer. Each
+// Crypto applies its own offset, so the bytes in the clear are those of from
+// on the way in and of to on the way out.
+func Rekey(data []byte, from, to Crypto) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+	from.DecryptInPlace(out)
+	to.EncryptInPlace(out)
+
+	return out
+}
@@ -0,0 +1,53 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRekey_SameKeyIsIdentity(t *testing.T) {
+	c := NewCrypto562(0x1234)
+	plaintext := bytes.Repeat([]byte{0x10, 0x20, 0x30, 0x40}, 8)
+	captured := bytes.Clone(plaintext)
+	c.EncryptInPlace(captured)
+
+	got := Rekey(captured, c, c)
+	assert.Equal(t, captured, got)
+	assert.NotSame(t, &captured[0], &got[0], "Rekey must return a new slice")
+}
+
+func TestRekey_ReencryptsUnderNewKey(t *testing.T) {
+	from, to := NewCrypto562(0x1111), NewCrypto562(0x2222)
+	plaintext := bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 8)
+	captured := bytes.Clone(plaintext)
+	from.EncryptInPlace(captured)
+	original := bytes.Clone(captured)
+
+	got := Rekey(captured, from, to)
+	assert.Equal(t, original, captured, "Rekey must not mutate its input")
+
+	want := bytes.Clone(plaintext)
+	to.EncryptInPlace(want)
+	assert.Equal(t, want, got)
+
+	to.DecryptInPlace(got)
+	assert.Equal(t, plaintext, got)
+}
+
+func TestRekey_RespectsOffsets(t *testing.T) {
+	from, to := NewCrypto562(0x1111), NewCrypto562(0x2222, WithOffset(0))
+	plaintext := bytes.Repeat([]byte{0xAA, 0xBB, 0xCC, 0xDD}, 8)
+	captured := bytes.Clone(plaintext)
+	from.EncryptInPlace(captured)
+
+	got := Rekey(captured, from, to)
+	to.DecryptInPlace(got)
+	assert.Equal(t, plaintext, got)
+}
+
+func TestRekey_Empty(t *testing.T) {
+	c := NewCrypto562(1)
+	assert.Empty(t, Rekey(nil, c, c))
+}
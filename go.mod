@@ -5,6 +5,7 @@ go 1.25
 require (
 	github.com/cyberinferno/go-utils v0.1.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/text v0.34.0
 )
 
 require (
@@ -4,11 +4,60 @@ package mapbin
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
+	"unsafe"
 
 	"github.com/cyberinferno/go-utils/utils"
+	agutils "github.com/project-agonyl/agonyl-utils-go/utils"
 )
 
+// FormatVersion is the newest version byte ReadVersioned accepts and the
+// version new versioned files should be written with.
+const FormatVersion = 1
+
+// ItemSize is the encoded size of a MapBinItem in bytes.
+const ItemSize = 56
+
+// MaxRecords is the largest entry count Read accepts. The count comes from
+// the file, so it is capped to keep a corrupt or malicious file from making
+// Read work through billions of records. The default of 65536 is far above
+// any real client file; raise it before reading trusted inputs that need
+// more. It must not be changed while reads are in progress.
+var MaxRecords = 1 << 16
+
+// ErrTooLarge is returned by Read when the entry count exceeds MaxRecords.
+var ErrTooLarge = errors.New("mapbin: entry count exceeds MaxRecords")
+
+// preallocRecords is the most records Read allocates room for before reading
+// them. A larger count, up to MaxRecords, grows the slice as records actually
+// arrive, so a count the input cannot back fails with io.ErrUnexpectedEOF
+// after at most 56 KiB up front instead of allocating for the whole count.
+const preallocRecords = 1024
+
+// ReadOptions adjusts how ReadWithOptions decodes a map bin.
+type ReadOptions struct {
+	// StrictTrailing fails the read with ErrTrailingBytes when r holds more
+	// data after the last of the entry-count records, to catch files with
+	// garbage appended. The check reads one byte past the last record, so
+	// leave it off when the bin is followed by other data in the same
+	// stream.
+	StrictTrailing bool
+}
+
+// ErrTrailingBytes is returned by ReadWithOptions, with StrictTrailing set,
+// if data follows the last record.
+var ErrTrailingBytes = errors.New("mapbin: trailing bytes after last entry")
+
+// ErrUnsupportedVersion is returned by ReadVersioned and WriteVersioned when
+// the version byte is 0 or newer than FormatVersion.
+var ErrUnsupportedVersion = errors.New("mapbin: unsupported format version")
+
+// ErrShortBuffer is returned by UnmarshalBinary when the input is shorter
+// than ItemSize.
+var ErrShortBuffer = errors.New("mapbin: buffer shorter than item size")
+
 // MapBinItem is a single map record (ID, unknown fields, and name).
 // Name is 0x20 bytes; Unknown1–Unknown5 are reserved uint32 values.
 type MapBinItem struct {
@@ -24,36 +73,143 @@ type MapBinItem struct {
 // MapBin is a slice of map entries as stored in the bin file.
 type MapBin []MapBinItem
 
+// MemSize returns the approximate number of bytes m occupies in memory:
+// the slice header plus cap(m) MapBinItem values. Summed over a loaded
+// dataset it gives a figure for capacity planning; allocator overhead is not
+// included.
+func (m MapBin) MemSize() int {
+	return int(unsafe.Sizeof(m)) + cap(m)*int(unsafe.Sizeof(MapBinItem{}))
+}
+
 // Read reads a map bin from r: entry count then each MapBinItem.
 // Returns the decoded slice or an error if the stream is truncated or invalid.
+// Any truncation, including an empty stream or a short final record, is
+// reported as io.ErrUnexpectedEOF; Read never returns a bare io.EOF. A count
+// above MaxRecords is rejected with ErrTooLarge before any record is read,
+// and memory grows with the records actually read rather than with the
+// stored count. Data after the last record is ignored, as it always has
+// been; use ReadWithOptions to reject it.
 func Read(r io.Reader) (MapBin, error) {
-	var entryCount uint32
-	if err := binary.Read(r, binary.LittleEndian, &entryCount); err != nil {
-		return nil, err
+	return ReadWithOptions(r, ReadOptions{})
+}
+
+// ReadWithOptions reads a map bin like Read, adjusted by opts.
+func ReadWithOptions(r io.Reader, opts ReadOptions) (MapBin, error) {
+	var countBuf [4]byte
+	if _, err := io.ReadFull(r, countBuf[:]); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+
+	count := binary.LittleEndian.Uint32(countBuf[:])
+	if uint64(count) > uint64(MaxRecords) {
+		return nil, fmt.Errorf("%w: %d", ErrTooLarge, count)
 	}
 
-	mapData := make(MapBin, entryCount)
-	for i := range mapData {
-		if err := binary.Read(r, binary.LittleEndian, &mapData[i]); err != nil {
-			return nil, err
+	mapData := make(MapBin, 0, min(count, preallocRecords))
+	var buf [ItemSize]byte
+	for range count {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, unexpectedEOF(err)
 		}
+
+		var item MapBinItem
+		getItem(buf[:], &item)
+		mapData = append(mapData, item)
 	}
+
+	if opts.StrictTrailing && hasTrailing(r) {
+		return nil, ErrTrailingBytes
+	}
+
 	return mapData, nil
 }
 
+// ReadCount reads only the little-endian uint32 entry count at the start of a
+// map bin and returns it without reading or allocating any records, so r is
+// left positioned at the first record. The count is returned as stored; it is
+// not checked against MaxRecords. A stream shorter than 4 bytes returns an
+// error wrapping io.ErrUnexpectedEOF.
+func ReadCount(r io.Reader) (uint32, error) {
+	var countBuf [4]byte
+	if _, err := io.ReadFull(r, countBuf[:]); err != nil {
+		if err = unexpectedEOF(err); err == io.ErrUnexpectedEOF {
+			return 0, fmt.Errorf("mapbin: reading entry count: %w", err)
+		}
+
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(countBuf[:]), nil
+}
+
 // Write writes data to w in map bin format: entry count then each item.
+// The whole file is encoded into one buffer and written with a single call.
 func Write(w io.Writer, data MapBin) error {
-	entryCount := uint32(len(data))
-	if err := binary.Write(w, binary.LittleEndian, entryCount); err != nil {
+	buf := make([]byte, 4+len(data)*ItemSize)
+	binary.LittleEndian.PutUint32(buf, uint32(len(data)))
+	for i := range data {
+		putItem(buf[4+i*ItemSize:], &data[i])
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// WriteVersioned writes a one-byte version prefix followed by data in the
+// standard format produced by Write. A version ReadVersioned would reject, 0
+// or newer than FormatVersion, returns ErrUnsupportedVersion and nothing is
+// written.
+func WriteVersioned(w io.Writer, data MapBin, version byte) error {
+	if err := checkVersion(version); err != nil {
 		return err
 	}
 
-	for i := range data {
-		if err := binary.Write(w, binary.LittleEndian, &data[i]); err != nil {
-			return err
-		}
+	if _, err := w.Write([]byte{version}); err != nil {
+		return err
+	}
+
+	return Write(w, data)
+}
+
+// ReadVersioned reads a file written by WriteVersioned and returns its data
+// and version byte. Version 0 and versions newer than FormatVersion are
+// rejected with ErrUnsupportedVersion before any record is read. Unversioned
+// files must be read with Read.
+func ReadVersioned(r io.Reader) (MapBin, byte, error) {
+	var version [1]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, 0, unexpectedEOF(err)
+	}
+
+	if err := checkVersion(version[0]); err != nil {
+		return nil, version[0], err
+	}
+
+	data, err := Read(r)
+	if err != nil {
+		return nil, version[0], err
+	}
+
+	return data, version[0], nil
+}
+
+// MarshalBinary encodes m into its ItemSize-byte wire form, producing the
+// same bytes as binary.Write without the reflection cost.
+func (m *MapBinItem) MarshalBinary() ([]byte, error) {
+	b := make([]byte, ItemSize)
+	putItem(b, m)
+	return b, nil
+}
+
+// UnmarshalBinary decodes the first ItemSize bytes of b into m. It is the
+// inverse of MarshalBinary and returns ErrShortBuffer if b is too short.
+// Extra bytes after the item are ignored.
+func (m *MapBinItem) UnmarshalBinary(b []byte) error {
+	if len(b) < ItemSize {
+		return ErrShortBuffer
 	}
 
+	getItem(b, m)
 	return nil
 }
 
@@ -61,3 +217,65 @@ func Write(w io.Writer, data MapBin) error {
 func (m *MapBinItem) GetName() string {
 	return utils.ReadStringFromBytes(m.Name[:])
 }
+
+// GetNameChecked returns the name like GetName and reports whether the Name
+// field is null-terminated. A name that fills the whole field with no
+// terminator is a sign of a corrupt record.
+func (m *MapBinItem) GetNameChecked() (string, bool) {
+	return agutils.ReadStringChecked(m.Name[:])
+}
+
+// checkVersion returns ErrUnsupportedVersion unless version is between 1 and
+// FormatVersion.
+func checkVersion(version byte) error {
+	if version == 0 || version > FormatVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	return nil
+}
+
+// hasTrailing reports whether r yields at least one more byte. A read error
+// without data counts as no trailing bytes: the records before it were read
+// in full.
+func hasTrailing(r io.Reader) bool {
+	var one [1]byte
+	n, _ := io.ReadFull(r, one[:])
+	return n > 0
+}
+
+// unexpectedEOF maps io.EOF to io.ErrUnexpectedEOF. The format always starts
+// with a count, so running out of data at any point means the file is
+// truncated; callers never see a bare io.EOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// putItem encodes item into the first ItemSize bytes of b.
+func putItem(b []byte, item *MapBinItem) {
+	c := agutils.NewCursor(b[:ItemSize])
+	c.PutUint32(item.ID)
+	c.PutUint32(item.Unknown1)
+	c.PutUint32(item.Unknown2)
+	c.PutUint32(item.Unknown3)
+	c.PutUint32(item.Unknown4)
+	c.PutUint32(item.Unknown5)
+	c.PutBytes(item.Name[:])
+}
+
+// getItem decodes the first ItemSize bytes of b into item. It is the inverse
+// of putItem.
+func getItem(b []byte, item *MapBinItem) {
+	c := agutils.NewCursor(b[:ItemSize])
+	item.ID = c.Uint32()
+	item.Unknown1 = c.Uint32()
+	item.Unknown2 = c.Uint32()
+	item.Unknown3 = c.Uint32()
+	item.Unknown4 = c.Uint32()
+	item.Unknown5 = c.Uint32()
+	copy(item.Name[:], c.Bytes(len(item.Name)))
+}
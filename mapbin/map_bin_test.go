@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	agutils "github.com/project-agonyl/agonyl-utils-go/utils"
 )
 
 func TestRead_EmptyBin(t *testing.T) {
@@ -75,15 +77,8 @@ func TestRead_TruncatedItem(t *testing.T) {
 }
 
 func TestRead_InvalidReader(t *testing.T) {
-	var r errReader
-	_, err := Read(&r)
-	assert.Error(t, err)
-}
-
-type errReader struct{}
-
-func (errReader) Read([]byte) (int, error) {
-	return 0, io.ErrClosedPipe
+	_, err := Read(agutils.FailAfterReader(bytes.NewReader(nil), 0))
+	assert.ErrorIs(t, err, agutils.ErrInjected)
 }
 
 func TestWrite_Empty(t *testing.T) {
@@ -160,3 +155,176 @@ func TestGetName_NoNullInBuffer(t *testing.T) {
 	assert.Len(t, name, 32)
 	assert.Equal(t, "XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX", name)
 }
+
+func TestGetNameChecked(t *testing.T) {
+	var m MapBinItem
+	copy(m.Name[:], "Temoz")
+	got, terminated := m.GetNameChecked()
+	assert.Equal(t, "Temoz", got)
+	assert.True(t, terminated)
+
+	for i := range m.Name {
+		m.Name[i] = 'X'
+	}
+	got, terminated = m.GetNameChecked()
+	assert.Equal(t, m.GetName(), got)
+	assert.False(t, terminated, "a full field with no null is reported")
+}
+
+func TestWriteVersioned_ReadVersioned_RoundTrip(t *testing.T) {
+	items := MapBin{{ID: 1}, {ID: 2}}
+	var buf bytes.Buffer
+	require.NoError(t, WriteVersioned(&buf, items, FormatVersion))
+	assert.Equal(t, byte(FormatVersion), buf.Bytes()[0])
+
+	data, version, err := ReadVersioned(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, byte(FormatVersion), version)
+	assert.Equal(t, items, data)
+}
+
+func TestWriteVersioned_PayloadMatchesWrite(t *testing.T) {
+	items := MapBin{{ID: 7}}
+	var plain, versioned bytes.Buffer
+	require.NoError(t, Write(&plain, items))
+	require.NoError(t, WriteVersioned(&versioned, items, FormatVersion))
+	assert.Equal(t, plain.Bytes(), versioned.Bytes()[1:])
+}
+
+func TestReadVersioned_UnsupportedVersion(t *testing.T) {
+	for _, v := range []byte{0, FormatVersion + 1, 0xFF} {
+		buf := bytes.NewBuffer([]byte{v})
+		require.NoError(t, Write(buf, MapBin{{ID: 1}}))
+		_, version, err := ReadVersioned(buf)
+		assert.ErrorIs(t, err, ErrUnsupportedVersion, "version %d", v)
+		assert.Equal(t, v, version)
+	}
+}
+
+func TestWriteVersioned_UnsupportedVersion(t *testing.T) {
+	for _, v := range []byte{0, FormatVersion + 1, 0xFF} {
+		var buf bytes.Buffer
+		err := WriteVersioned(&buf, MapBin{{ID: 1}}, v)
+		assert.ErrorIs(t, err, ErrUnsupportedVersion, "version %d", v)
+		assert.Zero(t, buf.Len(), "version %d", v)
+	}
+}
+
+func TestReadVersioned_Empty(t *testing.T) {
+	_, _, err := ReadVersioned(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestRead_EmptyStreamIsTruncation(t *testing.T) {
+	_, err := Read(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestRead_TruncatedCountIsUnexpectedEOF(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte{0x01, 0x00}))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestRead_MissingFinalRecord(t *testing.T) {
+	// Count says 2 but the stream ends exactly after the first record.
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, MapBin{{ID: 1}, {ID: 2}}))
+	data := buf.Bytes()[:4+56]
+
+	_, err := Read(bytes.NewReader(data))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestRead_TruncatedFinalRecord(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, MapBin{{ID: 1}, {ID: 2}}))
+	data := buf.Bytes()
+
+	_, err := Read(bytes.NewReader(data[:len(data)-1]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestRead_CountAboveMaxRecords(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF}))
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestRead_RaisedMaxRecords(t *testing.T) {
+	defer func(old int) { MaxRecords = old }(MaxRecords)
+	MaxRecords = 1
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, MapBin{{ID: 1}, {ID: 2}}))
+	_, err := Read(bytes.NewReader(buf.Bytes()))
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	MaxRecords = 2
+	got, err := Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func TestRead_CountWithoutData(t *testing.T) {
+	// A count within MaxRecords that the input cannot back is truncation, not
+	// ErrTooLarge, and fails without allocating for the whole count.
+	_, err := Read(bytes.NewReader([]byte{0x00, 0x00, 0x01, 0x00}))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.NotErrorIs(t, err, ErrTooLarge)
+}
+
+func TestRead_CountAbovePrealloc(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, make(MapBin, preallocRecords+1)))
+	got, err := Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Len(t, got, preallocRecords+1)
+}
+
+func TestReadCount(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, make(MapBin, 3)))
+	buf.WriteString("rest")
+
+	count, err := ReadCount(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), count)
+	assert.Equal(t, 3*ItemSize+4, buf.Len(), "only the count must be consumed")
+}
+
+func TestReadCount_Short(t *testing.T) {
+	for _, data := range [][]byte{nil, {0x01, 0x00, 0x00}} {
+		_, err := ReadCount(bytes.NewReader(data))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "%d bytes", len(data))
+	}
+}
+
+func TestReadCount_ReaderError(t *testing.T) {
+	_, err := ReadCount(agutils.FailAfterReader(bytes.NewReader(nil), 0))
+	assert.ErrorIs(t, err, agutils.ErrInjected)
+}
+
+func TestMemSize(t *testing.T) {
+	var empty MapBin
+	assert.Equal(t, empty.MemSize(), make(MapBin, 0).MemSize())
+	// Capacity, not length, is what the backing array occupies.
+	assert.Equal(t, make(MapBin, 10).MemSize(), make(MapBin, 0, 10).MemSize())
+	assert.Equal(t, 10*ItemSize, make(MapBin, 0, 10).MemSize()-empty.MemSize())
+}
+
+func TestReadWithOptions_StrictTrailing(t *testing.T) {
+	data := RandomN(1, 2)
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data))
+	withGarbage := append(buf.Bytes(), 0x00)
+
+	got, err := Read(bytes.NewReader(withGarbage))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	strict := ReadOptions{StrictTrailing: true}
+	_, err = ReadWithOptions(bytes.NewReader(withGarbage), strict)
+	assert.ErrorIs(t, err, ErrTrailingBytes)
+	got, err = ReadWithOptions(bytes.NewReader(buf.Bytes()), strict)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
@@ -0,0 +1,19 @@
+package mapbin
+
+import agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+
+// NormalizeNames rewrites every name in m in canonical form with
+// utils.NormalizeName: trailing whitespace trimmed, UTF-8 names re-encoded to
+// CP949, names truncated at a character boundary to leave a null at the end of
+// the 0x20-byte field and the padding zeroed. It modifies m in place and
+// returns the number of entries whose Name changed.
+func NormalizeNames(m MapBin) int {
+	changed := 0
+	for i := range m {
+		if agutils.NormalizeName(m[i].Name[:]) {
+			changed++
+		}
+	}
+
+	return changed
+}
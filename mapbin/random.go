@@ -0,0 +1,25 @@
+package mapbin
+
+import agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+
+// RandomN returns n map entries generated deterministically from seed, for
+// tests and fuzz corpora: the same seed and n always give the same data. IDs
+// run from 1 to n, the Unknown fields are random and every name is a
+// null-terminated ASCII name, so the result round-trips through Write and
+// Read unchanged.
+func RandomN(seed int64, n int) MapBin {
+	rng := agutils.NewRand(seed)
+	data := make(MapBin, n)
+	for i := range data {
+		item := &data[i]
+		item.ID = uint32(i + 1)
+		item.Unknown1 = rng.Uint32()
+		item.Unknown2 = rng.Uint32()
+		item.Unknown3 = rng.Uint32()
+		item.Unknown4 = rng.Uint32()
+		item.Unknown5 = rng.Uint32()
+		agutils.RandomName(rng, item.Name[:])
+	}
+
+	return data
+}
@@ -0,0 +1,130 @@
+package monsterbin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleMonsterBin returns n items with every byte of every field varied, so the
+// hand-written codec is checked against encoding/binary field by field.
+func sampleMonsterBin(n int) MonsterBin {
+	data := make(MonsterBin, n)
+	for i := range data {
+		item := MonsterBinItem{ID: uint32(i) * 0x01010101}
+		for j := range item.Name {
+			item.Name[j] = byte(i + j)
+		}
+		for j := range item.Unknown {
+			item.Unknown[j] = byte(i ^ j)
+		}
+		data[i] = item
+	}
+
+	return data
+}
+
+// reflectionEncode encodes data the way Write did before the hand-written
+// codec: binary.Write on the count and then on each item.
+func reflectionEncode(t testing.TB, data MonsterBin) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, uint32(len(data))))
+	for i := range data {
+		require.NoError(t, binary.Write(&buf, binary.LittleEndian, &data[i]))
+	}
+
+	return buf.Bytes()
+}
+
+func TestItemSize(t *testing.T) {
+	assert.Equal(t, binary.Size(MonsterBinItem{}), ItemSize)
+}
+
+func TestWrite_MatchesReflection(t *testing.T) {
+	data := sampleMonsterBin(300)
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data))
+	assert.Equal(t, reflectionEncode(t, data), buf.Bytes())
+}
+
+func TestRead_MatchesReflection(t *testing.T) {
+	raw := reflectionEncode(t, sampleMonsterBin(300))
+
+	r := bytes.NewReader(raw)
+	var count uint32
+	require.NoError(t, binary.Read(r, binary.LittleEndian, &count))
+	want := make(MonsterBin, count)
+	for i := range want {
+		require.NoError(t, binary.Read(r, binary.LittleEndian, &want[i]))
+	}
+
+	got, err := Read(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestMarshalBinary_RoundTrip(t *testing.T) {
+	item := sampleMonsterBin(3)[2]
+	b, err := item.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, reflectionEncode(t, MonsterBin{item})[4:], b)
+
+	var decoded MonsterBinItem
+	require.NoError(t, decoded.UnmarshalBinary(b))
+	assert.Equal(t, item, decoded)
+}
+
+func TestUnmarshalBinary_ShortBuffer(t *testing.T) {
+	var item MonsterBinItem
+	assert.ErrorIs(t, item.UnmarshalBinary(make([]byte, ItemSize-1)), ErrShortBuffer)
+}
+
+func BenchmarkRead_Reflection_20000(b *testing.B) {
+	raw := reflectionEncode(b, sampleMonsterBin(20000))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := bytes.NewReader(raw)
+		var count uint32
+		_ = binary.Read(r, binary.LittleEndian, &count)
+		data := make(MonsterBin, count)
+		for j := range data {
+			_ = binary.Read(r, binary.LittleEndian, &data[j])
+		}
+	}
+}
+
+func BenchmarkRead_Manual_20000(b *testing.B) {
+	raw := reflectionEncode(b, sampleMonsterBin(20000))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Read(bytes.NewReader(raw))
+	}
+}
+
+func BenchmarkWrite_Reflection_20000(b *testing.B) {
+	data := sampleMonsterBin(20000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = binary.Write(io.Discard, binary.LittleEndian, uint32(len(data)))
+		for j := range data {
+			_ = binary.Write(io.Discard, binary.LittleEndian, &data[j])
+		}
+	}
+}
+
+func BenchmarkWrite_Manual_20000(b *testing.B) {
+	data := sampleMonsterBin(20000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Write(io.Discard, data)
+	}
+}
@@ -0,0 +1,68 @@
+package monsterbin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrUnknownByteOrder is returned by ReadAuto when neither byte order gives a
+// record count consistent with the data.
+var ErrUnknownByteOrder = errors.New("monsterbin: cannot determine byte order")
+
+// DetectEndian guesses the byte order of a complete monster bin held in data.
+// The guess is a heuristic based only on the leading count: a count is
+// plausible in a given order if count records of the fixed record size fit in
+// the bytes that follow it. An order whose count accounts for the data exactly
+// is preferred; otherwise the first order whose count fits is used. Little
+// endian wins every tie, so a file whose count reads the same both ways (for
+// example 0) is reported as little endian. ok is false when data is shorter
+// than the count or neither interpretation fits, in which case
+// binary.LittleEndian is returned.
+func DetectEndian(data []byte) (binary.ByteOrder, bool) {
+	if len(data) < 4 {
+		return binary.LittleEndian, false
+	}
+
+	orders := []binary.ByteOrder{binary.LittleEndian, binary.BigEndian}
+	payload := uint64(len(data) - 4)
+	for _, order := range orders {
+		if uint64(order.Uint32(data))*uint64(ItemSize) == payload {
+			return order, true
+		}
+	}
+
+	for _, order := range orders {
+		if uint64(order.Uint32(data))*uint64(ItemSize) <= payload {
+			return order, true
+		}
+	}
+
+	return binary.LittleEndian, false
+}
+
+// ReadAuto reads a whole monster bin from r, guesses its byte order with
+// DetectEndian and decodes it in that order. It is a recovery path for files
+// exported with the count and IDs byte-swapped; the returned MonsterBin is in
+// native form, so Write produces a normal little-endian file. The detected
+// order is returned so callers can report which files were swapped. Because
+// detection is heuristic, prefer Read for files known to be well formed.
+func ReadAuto(r io.Reader) (MonsterBin, binary.ByteOrder, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order, ok := DetectEndian(data)
+	if !ok {
+		return nil, nil, ErrUnknownByteOrder
+	}
+
+	monsters, err := read(bytes.NewReader(data), order, ReadOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return monsters, order, nil
+}
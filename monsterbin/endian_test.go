@@ -0,0 +1,72 @@
+package monsterbin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeOrder writes data like Write but in the given byte order.
+func encodeOrder(t *testing.T, data MonsterBin, order binary.ByteOrder) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, binary.Write(&buf, order, uint32(len(data))))
+	for i := range data {
+		require.NoError(t, binary.Write(&buf, order, &data[i]))
+	}
+
+	return buf.Bytes()
+}
+
+func testMonsters() MonsterBin {
+	data := MonsterBin{{ID: 101}, {ID: 0x01020304}}
+	copy(data[0].Name[:], "Goblin")
+	copy(data[1].Name[:], "Dragon")
+	return data
+}
+
+func TestDetectEndian(t *testing.T) {
+	le := encodeOrder(t, testMonsters(), binary.LittleEndian)
+	order, ok := DetectEndian(le)
+	require.True(t, ok)
+	assert.Equal(t, binary.LittleEndian, order)
+
+	be := encodeOrder(t, testMonsters(), binary.BigEndian)
+	order, ok = DetectEndian(be)
+	require.True(t, ok)
+	assert.Equal(t, binary.BigEndian, order)
+}
+
+func TestDetectEndian_EmptyBinIsLittleEndian(t *testing.T) {
+	order, ok := DetectEndian([]byte{0, 0, 0, 0})
+	require.True(t, ok)
+	assert.Equal(t, binary.LittleEndian, order)
+}
+
+func TestDetectEndian_Implausible(t *testing.T) {
+	_, ok := DetectEndian([]byte{0x01, 0x00})
+	assert.False(t, ok)
+
+	// Count 0x7F7F7F7F is absurd in both orders.
+	_, ok = DetectEndian([]byte{0x7F, 0x7F, 0x7F, 0x7F, 0x00})
+	assert.False(t, ok)
+}
+
+func TestReadAuto(t *testing.T) {
+	want := testMonsters()
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		got, detected, err := ReadAuto(bytes.NewReader(encodeOrder(t, want, order)))
+		require.NoError(t, err)
+		assert.Equal(t, order, detected)
+		assert.Equal(t, want, got)
+		assert.Equal(t, "Dragon", got[1].GetName())
+	}
+}
+
+func TestReadAuto_Unknown(t *testing.T) {
+	_, _, err := ReadAuto(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF}))
+	assert.ErrorIs(t, err, ErrUnknownByteOrder)
+}
@@ -4,11 +4,60 @@ package monsterbin
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
+	"unsafe"
 
 	"github.com/cyberinferno/go-utils/utils"
+	agutils "github.com/project-agonyl/agonyl-utils-go/utils"
 )
 
+// FormatVersion is the newest version byte ReadVersioned accepts and the
+// version new versioned files should be written with.
+const FormatVersion = 1
+
+// ItemSize is the encoded size of a MonsterBinItem in bytes.
+const ItemSize = 96
+
+// MaxRecords is the largest entry count Read accepts. The count comes from
+// the file, so it is capped to keep a corrupt or malicious file from making
+// Read work through billions of records. The default of 65536 is far above
+// any real client file; raise it before reading trusted inputs that need
+// more. It must not be changed while reads are in progress.
+var MaxRecords = 1 << 16
+
+// ErrTooLarge is returned by Read when the entry count exceeds MaxRecords.
+var ErrTooLarge = errors.New("monsterbin: entry count exceeds MaxRecords")
+
+// preallocRecords is the most records Read allocates room for before reading
+// them. A larger count, up to MaxRecords, grows the slice as records actually
+// arrive, so a count the input cannot back fails with io.ErrUnexpectedEOF
+// after at most 96 KiB up front instead of allocating for the whole count.
+const preallocRecords = 1024
+
+// ReadOptions adjusts how ReadWithOptions decodes a monster bin.
+type ReadOptions struct {
+	// StrictTrailing fails the read with ErrTrailingBytes when r holds more
+	// data after the last of the entry-count records, to catch files with
+	// garbage appended. The check reads one byte past the last record, so
+	// leave it off when the bin is followed by other data in the same
+	// stream.
+	StrictTrailing bool
+}
+
+// ErrTrailingBytes is returned by ReadWithOptions, with StrictTrailing set,
+// if data follows the last record.
+var ErrTrailingBytes = errors.New("monsterbin: trailing bytes after last entry")
+
+// ErrUnsupportedVersion is returned by ReadVersioned and WriteVersioned when
+// the version byte is 0 or newer than FormatVersion.
+var ErrUnsupportedVersion = errors.New("monsterbin: unsupported format version")
+
+// ErrShortBuffer is returned by UnmarshalBinary when the input is shorter
+// than ItemSize.
+var ErrShortBuffer = errors.New("monsterbin: buffer shorter than item size")
+
 // MonsterBinItem is a single monster record (ID, name, and reserved bytes).
 // Name is 0x1F bytes; Unknown is 0x3D bytes of reserved/padding data.
 type MonsterBinItem struct {
@@ -20,37 +69,148 @@ type MonsterBinItem struct {
 // MonsterBin is a slice of monster entries as stored in the bin file.
 type MonsterBin []MonsterBinItem
 
+// MemSize returns the approximate number of bytes m occupies in memory:
+// the slice header plus cap(m) MonsterBinItem values. Summed over a loaded
+// dataset it gives a figure for capacity planning; allocator overhead is not
+// included.
+func (m MonsterBin) MemSize() int {
+	return int(unsafe.Sizeof(m)) + cap(m)*int(unsafe.Sizeof(MonsterBinItem{}))
+}
+
 // Read reads a monster bin from r: entry count then each MonsterBinItem.
 // Returns the decoded slice or an error if the stream is truncated or invalid.
+// Any truncation, including an empty stream or a short final record, is
+// reported as io.ErrUnexpectedEOF; Read never returns a bare io.EOF. A count
+// above MaxRecords is rejected with ErrTooLarge before any record is read,
+// and memory grows with the records actually read rather than with the
+// stored count. Data after the last record is ignored, as it always has
+// been; use ReadWithOptions to reject it.
 func Read(r io.Reader) (MonsterBin, error) {
-	var entryCount uint32
-	if err := binary.Read(r, binary.LittleEndian, &entryCount); err != nil {
-		return nil, err
+	return read(r, binary.LittleEndian, ReadOptions{})
+}
+
+// ReadWithOptions reads a monster bin like Read, adjusted by opts.
+func ReadWithOptions(r io.Reader, opts ReadOptions) (MonsterBin, error) {
+	return read(r, binary.LittleEndian, opts)
+}
+
+// read implements Read for either byte order.
+func read(r io.Reader, order binary.ByteOrder, opts ReadOptions) (MonsterBin, error) {
+	var countBuf [4]byte
+	if _, err := io.ReadFull(r, countBuf[:]); err != nil {
+		return nil, unexpectedEOF(err)
 	}
 
-	monsterData := make(MonsterBin, entryCount)
-	for i := range monsterData {
-		if err := binary.Read(r, binary.LittleEndian, &monsterData[i]); err != nil {
-			return nil, err
+	count := order.Uint32(countBuf[:])
+	if uint64(count) > uint64(MaxRecords) {
+		return nil, fmt.Errorf("%w: %d", ErrTooLarge, count)
+	}
+
+	monsterData := make(MonsterBin, 0, min(count, preallocRecords))
+	var buf [ItemSize]byte
+	for range count {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, unexpectedEOF(err)
 		}
+
+		var item MonsterBinItem
+		getItem(buf[:], order, &item)
+		monsterData = append(monsterData, item)
+	}
+
+	if opts.StrictTrailing && hasTrailing(r) {
+		return nil, ErrTrailingBytes
 	}
 
 	return monsterData, nil
 }
 
+// ReadCount reads only the little-endian uint32 entry count at the start of a
+// monster bin and returns it without reading or allocating any records, so r is
+// left positioned at the first record. The count is returned as stored; it is
+// not checked against MaxRecords. A stream shorter than 4 bytes returns an
+// error wrapping io.ErrUnexpectedEOF.
+func ReadCount(r io.Reader) (uint32, error) {
+	var countBuf [4]byte
+	if _, err := io.ReadFull(r, countBuf[:]); err != nil {
+		if err = unexpectedEOF(err); err == io.ErrUnexpectedEOF {
+			return 0, fmt.Errorf("monsterbin: reading entry count: %w", err)
+		}
+
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(countBuf[:]), nil
+}
+
 // Write writes data to w in monster bin format: entry count then each item.
+// The whole file is encoded into one buffer and written with a single call.
 func Write(w io.Writer, data MonsterBin) error {
-	entryCount := uint32(len(data))
-	if err := binary.Write(w, binary.LittleEndian, entryCount); err != nil {
+	buf := make([]byte, 4+len(data)*ItemSize)
+	binary.LittleEndian.PutUint32(buf, uint32(len(data)))
+	for i := range data {
+		putItem(buf[4+i*ItemSize:], &data[i])
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+// WriteVersioned writes a one-byte version prefix followed by data in the
+// standard format produced by Write. A version ReadVersioned would reject, 0
+// or newer than FormatVersion, returns ErrUnsupportedVersion and nothing is
+// written.
+func WriteVersioned(w io.Writer, data MonsterBin, version byte) error {
+	if err := checkVersion(version); err != nil {
 		return err
 	}
 
-	for i := range data {
-		if err := binary.Write(w, binary.LittleEndian, &data[i]); err != nil {
-			return err
-		}
+	if _, err := w.Write([]byte{version}); err != nil {
+		return err
 	}
 
+	return Write(w, data)
+}
+
+// ReadVersioned reads a file written by WriteVersioned and returns its data
+// and version byte. Version 0 and versions newer than FormatVersion are
+// rejected with ErrUnsupportedVersion before any record is read. Unversioned
+// files must be read with Read.
+func ReadVersioned(r io.Reader) (MonsterBin, byte, error) {
+	var version [1]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, 0, unexpectedEOF(err)
+	}
+
+	if err := checkVersion(version[0]); err != nil {
+		return nil, version[0], err
+	}
+
+	data, err := Read(r)
+	if err != nil {
+		return nil, version[0], err
+	}
+
+	return data, version[0], nil
+}
+
+// MarshalBinary encodes m into its ItemSize-byte wire form, producing the
+// same bytes as binary.Write without the reflection cost.
+func (m *MonsterBinItem) MarshalBinary() ([]byte, error) {
+	b := make([]byte, ItemSize)
+	putItem(b, m)
+	return b, nil
+}
+
+// UnmarshalBinary decodes the first ItemSize bytes of b into m. It is the
+// inverse of MarshalBinary and returns ErrShortBuffer if b is too short.
+// Extra bytes after the item are ignored.
+func (m *MonsterBinItem) UnmarshalBinary(b []byte) error {
+	if len(b) < ItemSize {
+		return ErrShortBuffer
+	}
+
+	getItem(b, binary.LittleEndian, m)
 	return nil
 }
 
@@ -58,3 +218,57 @@ func Write(w io.Writer, data MonsterBin) error {
 func (m *MonsterBinItem) GetName() string {
 	return utils.ReadStringFromBytes(m.Name[:])
 }
+
+// GetNameChecked returns the name like GetName and reports whether the Name
+// field is null-terminated. A name that fills the whole field with no
+// terminator is a sign of a corrupt record.
+func (m *MonsterBinItem) GetNameChecked() (string, bool) {
+	return agutils.ReadStringChecked(m.Name[:])
+}
+
+// checkVersion returns ErrUnsupportedVersion unless version is between 1 and
+// FormatVersion.
+func checkVersion(version byte) error {
+	if version == 0 || version > FormatVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+
+	return nil
+}
+
+// hasTrailing reports whether r yields at least one more byte. A read error
+// without data counts as no trailing bytes: the records before it were read
+// in full.
+func hasTrailing(r io.Reader) bool {
+	var one [1]byte
+	n, _ := io.ReadFull(r, one[:])
+	return n > 0
+}
+
+// unexpectedEOF maps io.EOF to io.ErrUnexpectedEOF. The format always starts
+// with a count, so running out of data at any point means the file is
+// truncated; callers never see a bare io.EOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// putItem encodes item little-endian into the first ItemSize bytes of b.
+func putItem(b []byte, item *MonsterBinItem) {
+	c := agutils.NewCursor(b[:ItemSize])
+	c.PutUint32(item.ID)
+	c.PutBytes(item.Name[:])
+	c.PutBytes(item.Unknown[:])
+}
+
+// getItem decodes the first ItemSize bytes of b into item using order for the
+// ID. It is the inverse of putItem.
+func getItem(b []byte, order binary.ByteOrder, item *MonsterBinItem) {
+	c := agutils.NewCursor(b[:ItemSize])
+	item.ID = order.Uint32(c.Bytes(4))
+	copy(item.Name[:], c.Bytes(len(item.Name)))
+	copy(item.Unknown[:], c.Bytes(len(item.Unknown)))
+}
@@ -0,0 +1,21 @@
+package monsterbin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeNames(t *testing.T) {
+	data := make(MonsterBin, 3)
+	copy(data[0].Name[:], "Canonical")
+	copy(data[1].Name[:], "Trailing   ")
+	copy(data[2].Name[:], "전사")
+
+	assert.Equal(t, 2, NormalizeNames(data))
+	assert.Equal(t, "Canonical", data[0].GetName())
+	assert.Equal(t, "Trailing", data[1].GetName())
+	assert.Equal(t, []byte{0xC0, 0xFC, 0xBB, 0xE7, 0}, data[2].Name[:5])
+
+	assert.Zero(t, NormalizeNames(data), "normalizing twice changes nothing")
+}
@@ -0,0 +1,23 @@
+package monsterbin
+
+import agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+
+// RandomN returns n monster entries generated deterministically from seed,
+// for tests and fuzz corpora: the same seed and n always give the same data.
+// IDs run from 1 to n, the Unknown bytes are random and every name is a
+// null-terminated ASCII name, so the result round-trips through Write and
+// Read unchanged.
+func RandomN(seed int64, n int) MonsterBin {
+	rng := agutils.NewRand(seed)
+	data := make(MonsterBin, n)
+	for i := range data {
+		item := &data[i]
+		item.ID = uint32(i + 1)
+		agutils.RandomName(rng, item.Name[:])
+		for j := range item.Unknown {
+			item.Unknown[j] = byte(rng.UintN(256))
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,82 @@
+package monsterbin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrDuplicateID is reported by ReadShards for a monster ID that appears more
+// than once across the shards.
+var ErrDuplicateID = errors.New("monsterbin: duplicate monster id")
+
+// ErrNoShards is returned by WriteShards when it is given no writers.
+var ErrNoShards = errors.New("monsterbin: no shard writers")
+
+// ReadShards reads one monster bin from each reader, in the format Read
+// accepts, and concatenates their records in shard order. A final pass drops
+// every record whose ID was already seen, keeping the first occurrence, so the
+// result holds each ID once.
+//
+// If a shard cannot be read ReadShards returns nil and an error naming the
+// shard's index. Otherwise dropped duplicates are reported by returning the
+// merged MonsterBin together with an error joining one ErrDuplicateID per
+// dropped record, naming the ID and both shards; the error is nil when the
+// shards do not overlap.
+func ReadShards(readers ...io.Reader) (MonsterBin, error) {
+	var merged MonsterBin
+	var shardOf []int
+	for i, r := range readers {
+		data, err := Read(r)
+		if err != nil {
+			return nil, fmt.Errorf("monsterbin: shard %d: %w", i, err)
+		}
+
+		merged = append(merged, data...)
+		for range data {
+			shardOf = append(shardOf, i)
+		}
+	}
+
+	first := make(map[uint32]int, len(merged))
+	deduped := merged[:0]
+	var errs []error
+	for i, item := range merged {
+		if j, ok := first[item.ID]; ok {
+			errs = append(errs, fmt.Errorf("%w: id %d in shard %d and shard %d", ErrDuplicateID, item.ID, j, shardOf[i]))
+			continue
+		}
+
+		first[item.ID] = shardOf[i]
+		deduped = append(deduped, item)
+	}
+
+	if merged == nil {
+		deduped = MonsterBin{}
+	}
+
+	return deduped, errors.Join(errs...)
+}
+
+// WriteShards splits data into len(writers) contiguous shards of nearly equal
+// size, in order, and writes shard i to writers[i] with Write. Shard sizes
+// differ by at most one record, and a shard may be empty when data has fewer
+// records than there are writers; it is still written with a zero count.
+// Reading the shards back with ReadShards in the same order yields data. It
+// returns ErrNoShards if writers is empty and stops at the first write error,
+// naming the shard's index.
+func WriteShards(data MonsterBin, writers ...io.Writer) error {
+	if len(writers) == 0 {
+		return ErrNoShards
+	}
+
+	n := len(writers)
+	for i, w := range writers {
+		shard := data[i*len(data)/n : (i+1)*len(data)/n]
+		if err := Write(w, shard); err != nil {
+			return fmt.Errorf("monsterbin: shard %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package monsterbin
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteShards_ReadShards_RoundTrip(t *testing.T) {
+	data := RandomN(3, 10)
+	bufs := make([]bytes.Buffer, 3)
+	require.NoError(t, WriteShards(data, &bufs[0], &bufs[1], &bufs[2]))
+
+	var sizes []uint32
+	readers := make([]io.Reader, len(bufs))
+	for i := range bufs {
+		count, err := ReadCount(bytes.NewReader(bufs[i].Bytes()))
+		require.NoError(t, err)
+		sizes = append(sizes, count)
+		readers[i] = &bufs[i]
+	}
+	assert.Equal(t, []uint32{3, 3, 4}, sizes)
+
+	got, err := ReadShards(readers...)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestWriteShards_MoreShardsThanRecords(t *testing.T) {
+	data := RandomN(1, 1)
+	var a, b bytes.Buffer
+	require.NoError(t, WriteShards(data, &a, &b))
+	assert.Equal(t, []byte{0, 0, 0, 0}, a.Bytes())
+	assert.Equal(t, 4+ItemSize, b.Len())
+}
+
+func TestWriteShards_NoWriters(t *testing.T) {
+	assert.ErrorIs(t, WriteShards(RandomN(1, 2)), ErrNoShards)
+}
+
+func TestReadShards_Duplicates(t *testing.T) {
+	data := RandomN(5, 4)
+	dup := data[1]
+	dup.Name[0] = 'Z'
+	var a, b bytes.Buffer
+	require.NoError(t, Write(&a, data[:3]))
+	require.NoError(t, Write(&b, MonsterBin{dup, data[3]}))
+
+	got, err := ReadShards(&a, &b)
+	require.ErrorIs(t, err, ErrDuplicateID)
+	assert.Contains(t, err.Error(), "id 2 in shard 0 and shard 1")
+	assert.Equal(t, data, got, "the first occurrence wins")
+}
+
+func TestReadShards_TruncatedShard(t *testing.T) {
+	var a bytes.Buffer
+	require.NoError(t, Write(&a, RandomN(1, 2)))
+	got, err := ReadShards(bytes.NewReader(a.Bytes()), bytes.NewReader(a.Bytes()[:10]))
+	assert.Nil(t, got)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Contains(t, err.Error(), "shard 1")
+}
+
+func TestReadShards_Empty(t *testing.T) {
+	got, err := ReadShards()
+	require.NoError(t, err)
+	assert.NotNil(t, got)
+	assert.Empty(t, got)
+}
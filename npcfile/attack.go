@@ -0,0 +1,48 @@
+package npcfile
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by SetAttacks and AddAttack.
+var (
+	// ErrTooManyAttacks is returned by SetAttacks when more attacks are
+	// supplied than NPCFileData.Attacks has slots.
+	ErrTooManyAttacks = errors.New("npcfile: too many attacks")
+
+	// ErrAttacksFull is returned by AddAttack when every attack slot is in use.
+	ErrAttacksFull = errors.New("npcfile: no free attack slot")
+)
+
+// IsEmpty reports whether the attack slot is unused, i.e. all its fields are
+// zero.
+func (a NPCAttack) IsEmpty() bool {
+	return a == NPCAttack{}
+}
+
+// SetAttacks replaces the NPC's attacks with attacks, in order, and zeroes the
+// remaining slots. It returns ErrTooManyAttacks, leaving n unchanged, if
+// attacks has more entries than there are slots.
+func (n *NPCFileData) SetAttacks(attacks []NPCAttack) error {
+	if len(attacks) > len(n.Attacks) {
+		return fmt.Errorf("%w: %d, limit %d", ErrTooManyAttacks, len(attacks), len(n.Attacks))
+	}
+
+	clear(n.Attacks[:])
+	copy(n.Attacks[:], attacks)
+	return nil
+}
+
+// AddAttack stores a in the first empty attack slot. It returns
+// ErrAttacksFull if every slot already holds an attack.
+func (n *NPCFileData) AddAttack(a NPCAttack) error {
+	for i := range n.Attacks {
+		if n.Attacks[i].IsEmpty() {
+			n.Attacks[i] = a
+			return nil
+		}
+	}
+
+	return ErrAttacksFull
+}
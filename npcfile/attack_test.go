@@ -0,0 +1,48 @@
+package npcfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetAttacks(t *testing.T) {
+	var n NPCFileData
+	n.Attacks[2] = NPCAttack{Range: 9}
+	a := NPCAttack{Range: 1, Damage: 10}
+	b := NPCAttack{Range: 2, Damage: 20}
+
+	require.NoError(t, n.SetAttacks([]NPCAttack{a, b}))
+	assert.Equal(t, [3]NPCAttack{a, b, {}}, n.Attacks, "unused slot must be zeroed")
+
+	require.NoError(t, n.SetAttacks(nil))
+	assert.Equal(t, [3]NPCAttack{}, n.Attacks)
+}
+
+func TestSetAttacks_TooMany(t *testing.T) {
+	var n NPCFileData
+	n.Attacks[0] = NPCAttack{Range: 9}
+	err := n.SetAttacks(make([]NPCAttack, 4))
+	assert.ErrorIs(t, err, ErrTooManyAttacks)
+	assert.Equal(t, NPCAttack{Range: 9}, n.Attacks[0], "attacks must be unchanged on error")
+}
+
+func TestAddAttack(t *testing.T) {
+	var n NPCFileData
+	n.Attacks[0] = NPCAttack{Range: 1}
+	require.NoError(t, n.AddAttack(NPCAttack{Range: 2}))
+	require.NoError(t, n.AddAttack(NPCAttack{Range: 3}))
+	assert.Equal(t, [3]NPCAttack{{Range: 1}, {Range: 2}, {Range: 3}}, n.Attacks)
+
+	assert.ErrorIs(t, n.AddAttack(NPCAttack{Range: 4}), ErrAttacksFull)
+	assert.Equal(t, NPCAttack{Range: 3}, n.Attacks[2])
+}
+
+func TestAddAttack_FillsFirstGap(t *testing.T) {
+	var n NPCFileData
+	n.Attacks[0] = NPCAttack{Range: 1}
+	n.Attacks[2] = NPCAttack{Range: 3}
+	require.NoError(t, n.AddAttack(NPCAttack{Range: 2}))
+	assert.Equal(t, NPCAttack{Range: 2}, n.Attacks[1])
+}
@@ -0,0 +1,160 @@
+package npcfile
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrDuplicateID is returned by Diff when a dataset holds two records with the
+// same Id, which makes the comparison ambiguous.
+var ErrDuplicateID = errors.New("npcfile: duplicate NPC id")
+
+// ChangeKind says how an NPC differs between two datasets.
+type ChangeKind uint8
+
+// Change kinds. Renamed means only Name differs; Changed means at least one
+// stat field differs, whether or not the name changed too.
+const (
+	Added ChangeKind = iota + 1
+	Removed
+	Renamed
+	Changed
+)
+
+// String returns "added", "removed", "renamed" or "changed".
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Renamed:
+		return "renamed"
+	case Changed:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// NPCChange describes one added, removed, renamed or changed NPC. Old is the
+// zero value for an added NPC and New for a removed one. Fields lists the
+// fields that differ in declaration order, with attack slots reported per
+// field as "Attacks[i].Damage" and so on; it is nil for added and removed
+// NPCs.
+type NPCChange struct {
+	Kind   ChangeKind
+	Id     uint16
+	Old    NPCFileData
+	New    NPCFileData
+	Fields []string
+}
+
+// String formats the change as a single line, for example
+// "changed npc 12: HP, Attacks[0].Damage".
+func (c NPCChange) String() string {
+	s := fmt.Sprintf("%s npc %d", c.Kind, c.Id)
+	if len(c.Fields) > 0 {
+		s += ": " + strings.Join(c.Fields, ", ")
+	}
+
+	return s
+}
+
+// Diff compares two NPC datasets keyed by Id and returns the added, removed,
+// renamed and changed NPCs sorted by Id. Unchanged NPCs are omitted. If
+// either dataset contains an Id more than once Diff returns no changes and an
+// error joining one ErrDuplicateID per repeated Id.
+func Diff(oldData, newData []NPCFileData) ([]NPCChange, error) {
+	before, errOld := indexByID("old", oldData)
+	after, errNew := indexByID("new", newData)
+	if err := errors.Join(errOld, errNew); err != nil {
+		return nil, err
+	}
+
+	var changes []NPCChange
+	for id, o := range before {
+		n, ok := after[id]
+		if !ok {
+			changes = append(changes, NPCChange{Kind: Removed, Id: id, Old: o})
+			continue
+		}
+
+		fields := diffFields(o, n)
+		switch {
+		case len(fields) == 0:
+			continue
+		case len(fields) == 1 && fields[0] == "Name":
+			changes = append(changes, NPCChange{Kind: Renamed, Id: id, Old: o, New: n, Fields: fields})
+		default:
+			changes = append(changes, NPCChange{Kind: Changed, Id: id, Old: o, New: n, Fields: fields})
+		}
+	}
+
+	for id, n := range after {
+		if _, ok := before[id]; !ok {
+			changes = append(changes, NPCChange{Kind: Added, Id: id, New: n})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b NPCChange) int { return cmp.Compare(a.Id, b.Id) })
+	return changes, nil
+}
+
+// indexByID maps each record of data by Id, reporting repeated Ids.
+func indexByID(name string, data []NPCFileData) (map[uint16]NPCFileData, error) {
+	index := make(map[uint16]NPCFileData, len(data))
+	first := make(map[uint16]int, len(data))
+	var errs []error
+	for i, n := range data {
+		if j, ok := first[n.Id]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s dataset has id %d at indexes %d and %d", ErrDuplicateID, name, n.Id, j, i))
+			continue
+		}
+
+		first[n.Id] = i
+		index[n.Id] = n
+	}
+
+	return index, errors.Join(errs...)
+}
+
+// diffFields lists the fields of a and b that differ, expanding the attack
+// slots into their individual fields.
+func diffFields(a, b NPCFileData) []string {
+	var fields []string
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := range va.NumField() {
+		name := va.Type().Field(i).Name
+		if name == "Attacks" {
+			for slot := range a.Attacks {
+				fields = append(fields, attackFields(slot, a.Attacks[slot], b.Attacks[slot])...)
+			}
+
+			continue
+		}
+
+		if va.Field(i).Interface() != vb.Field(i).Interface() {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// attackFields lists the differing fields of one attack slot.
+func attackFields(slot int, a, b NPCAttack) []string {
+	var fields []string
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := range va.NumField() {
+		if va.Field(i).Interface() != vb.Field(i).Interface() {
+			fields = append(fields, fmt.Sprintf("Attacks[%d].%s", slot, va.Type().Field(i).Name))
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,66 @@
+package npcfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func diffNPC(id uint16, name string) NPCFileData {
+	var n NPCFileData
+	n.Id = id
+	copy(n.Name[:], name)
+	n.Level = 10
+	n.HP = 500
+	return n
+}
+
+func TestDiff(t *testing.T) {
+	unchanged := diffNPC(1, "Guard")
+	renamed := diffNPC(2, "Wolf")
+	stats := diffNPC(3, "Bear")
+	removed := diffNPC(4, "Goblin")
+	added := diffNPC(5, "Orc")
+
+	renamedNew := renamed
+	copy(renamedNew.Name[:], "Dire Wolf")
+	statsNew := stats
+	statsNew.HP = 750
+	statsNew.Attacks[1].Damage = 40
+	copy(statsNew.Name[:], "Cave Bear")
+
+	changes, err := Diff(
+		[]NPCFileData{removed, stats, unchanged, renamed},
+		[]NPCFileData{added, unchanged, renamedNew, statsNew},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []NPCChange{
+		{Kind: Renamed, Id: 2, Old: renamed, New: renamedNew, Fields: []string{"Name"}},
+		{Kind: Changed, Id: 3, Old: stats, New: statsNew, Fields: []string{"Name", "Attacks[1].Damage", "HP"}},
+		{Kind: Removed, Id: 4, Old: removed},
+		{Kind: Added, Id: 5, New: added},
+	}, changes)
+
+	assert.Equal(t, "renamed npc 2: Name", changes[0].String())
+	assert.Equal(t, "changed npc 3: Name, Attacks[1].Damage, HP", changes[1].String())
+	assert.Equal(t, "removed npc 4", changes[2].String())
+}
+
+func TestDiff_Identical(t *testing.T) {
+	data := []NPCFileData{diffNPC(1, "Guard"), diffNPC(2, "Wolf")}
+	changes, err := Diff(data, data)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiff_DuplicateIDs(t *testing.T) {
+	old := []NPCFileData{diffNPC(1, "Guard"), diffNPC(1, "Guard copy")}
+	updated := []NPCFileData{diffNPC(2, "Wolf"), diffNPC(3, "Bear"), diffNPC(2, "Wolf copy")}
+
+	changes, err := Diff(old, updated)
+	assert.Nil(t, changes)
+	require.ErrorIs(t, err, ErrDuplicateID)
+	assert.Contains(t, err.Error(), "old dataset has id 1 at indexes 0 and 1")
+	assert.Contains(t, err.Error(), "new dataset has id 2 at indexes 0 and 2")
+}
@@ -0,0 +1,19 @@
+package npcfile
+
+import agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+
+// NormalizeNames rewrites the name of every record in records in canonical
+// form with utils.NormalizeName: trailing whitespace trimmed, UTF-8 names
+// re-encoded to CP949, names truncated at a character boundary to leave a null
+// at the end of the 0x14-byte field and the padding zeroed. It modifies
+// records in place and returns the number of records whose Name changed.
+func NormalizeNames(records []NPCFileData) int {
+	changed := 0
+	for i := range records {
+		if agutils.NormalizeName(records[i].Name[:]) {
+			changed++
+		}
+	}
+
+	return changed
+}
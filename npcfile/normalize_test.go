@@ -0,0 +1,20 @@
+package npcfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeNames(t *testing.T) {
+	records := []NPCFileData{makeNPCWithName("Guard"), makeNPCWithName("Guard \t"), makeNPCWithName("전사")}
+	records[0].Name[len(records[0].Name)-1] = 0x7F // stray byte in the padding
+
+	assert.Equal(t, 3, NormalizeNames(records))
+	assert.Equal(t, "Guard", records[0].GetName())
+	assert.Zero(t, records[0].Name[len(records[0].Name)-1])
+	assert.Equal(t, "Guard", records[1].GetName())
+	assert.Equal(t, []byte{0xC0, 0xFC, 0xBB, 0xE7, 0}, records[2].Name[:5])
+
+	assert.Zero(t, NormalizeNames(records))
+}
@@ -3,12 +3,20 @@
 package npcfile
 
 import (
-	"encoding/binary"
+	"errors"
 	"io"
 
 	"github.com/cyberinferno/go-utils/utils"
+	agutils "github.com/project-agonyl/agonyl-utils-go/utils"
 )
 
+// RecordSize is the encoded size of an NPCFileData record in bytes.
+const RecordSize = 78
+
+// ErrShortBuffer is returned by UnmarshalBinary when the input is shorter
+// than RecordSize.
+var ErrShortBuffer = errors.New("npcfile: buffer shorter than record size")
+
 // NPCFileData is a single NPC record as stored in the NPC file.
 // Name is 0x14 bytes; Attacks holds up to 3 attack definitions.
 type NPCFileData struct {
@@ -44,25 +52,111 @@ type NPCAttack struct {
 
 // Read reads a single NPC record from r in little-endian binary format.
 // Returns the decoded NPCFileData or an error if the stream is truncated or invalid.
+// It decodes with UnmarshalBinary through utils.ReadFixed, so an empty or
+// truncated stream returns io.ErrUnexpectedEOF.
 func Read(r io.Reader) (NPCFileData, error) {
-	var data NPCFileData
-	if err := binary.Read(r, binary.LittleEndian, &data); err != nil {
-		return NPCFileData{}, err
-	}
-
-	return data, nil
+	return agutils.ReadFixed[NPCFileData](r)
 }
 
 // Write writes data to w in NPC file binary format (little-endian).
 func Write(w io.Writer, data NPCFileData) error {
-	if err := binary.Write(w, binary.LittleEndian, data); err != nil {
+	b, err := data.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(b); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// MarshalBinary encodes n into its RecordSize-byte wire form. It writes the
+// fields in order with a utils.Cursor and produces the same bytes as
+// binary.Write without the reflection cost.
+func (n *NPCFileData) MarshalBinary() ([]byte, error) {
+	b := make([]byte, RecordSize)
+	c := agutils.NewCursor(b)
+	c.PutBytes(n.Name[:])
+	c.PutUint16(n.Id)
+	c.PutUint16(n.RespawnRate)
+	c.PutByte(n.AttackTypeInfo)
+	c.PutByte(n.TargetSelectionInfo)
+	c.PutByte(n.Defense)
+	c.PutByte(n.AdditionalDefense)
+	for _, a := range n.Attacks {
+		c.PutUint16(a.Range)
+		c.PutUint16(a.Area)
+		c.PutUint16(a.Damage)
+		c.PutUint16(a.AdditionalDamage)
+	}
+
+	c.PutUint16(n.AttackSpeedLow)
+	c.PutUint16(n.AttackSpeedHigh)
+	c.PutUint32(n.MovementSpeed)
+	c.PutByte(n.Level)
+	c.PutUint16(n.PlayerExp)
+	c.PutByte(n.Appearance)
+	c.PutUint32(n.HP)
+	c.PutUint16(n.BlueAttackDefense)
+	c.PutUint16(n.RedAttackDefense)
+	c.PutUint16(n.GreyAttackDefense)
+	c.PutUint16(n.MercenaryExp)
+	c.PutUint16(n.Unknown)
+
+	return b, c.Err()
+}
+
+// UnmarshalBinary decodes the first RecordSize bytes of b into n. It is the
+// hand-written inverse of MarshalBinary and returns ErrShortBuffer if b is
+// too short. Extra bytes after the record are ignored.
+func (n *NPCFileData) UnmarshalBinary(b []byte) error {
+	if len(b) < RecordSize {
+		return ErrShortBuffer
+	}
+
+	c := agutils.NewCursor(b[:RecordSize])
+	copy(n.Name[:], c.Bytes(len(n.Name)))
+	n.Id = c.Uint16()
+	n.RespawnRate = c.Uint16()
+	n.AttackTypeInfo = c.Byte()
+	n.TargetSelectionInfo = c.Byte()
+	n.Defense = c.Byte()
+	n.AdditionalDefense = c.Byte()
+	for i := range n.Attacks {
+		n.Attacks[i] = NPCAttack{
+			Range:            c.Uint16(),
+			Area:             c.Uint16(),
+			Damage:           c.Uint16(),
+			AdditionalDamage: c.Uint16(),
+		}
+	}
+
+	n.AttackSpeedLow = c.Uint16()
+	n.AttackSpeedHigh = c.Uint16()
+	n.MovementSpeed = c.Uint32()
+	n.Level = c.Byte()
+	n.PlayerExp = c.Uint16()
+	n.Appearance = c.Byte()
+	n.HP = c.Uint32()
+	n.BlueAttackDefense = c.Uint16()
+	n.RedAttackDefense = c.Uint16()
+	n.GreyAttackDefense = c.Uint16()
+	n.MercenaryExp = c.Uint16()
+	n.Unknown = c.Uint16()
+
+	return c.Err()
+}
+
 // GetName returns the NPC display name as a string (trimmed of null padding).
 func (n *NPCFileData) GetName() string {
 	return utils.ReadStringFromBytes(n.Name[:])
 }
+
+// GetNameChecked returns the name like GetName and reports whether the Name
+// field is null-terminated. A name that fills the whole field with no
+// terminator is a sign of a corrupt record.
+func (n *NPCFileData) GetNameChecked() (string, bool) {
+	return agutils.ReadStringChecked(n.Name[:])
+}
@@ -0,0 +1,64 @@
+package npcfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func benchmarkRecord() NPCFileData {
+	data := makeNPCWithName("Benchmark")
+	data.Id = 1234
+	data.HP = 50000
+	data.Attacks[0] = NPCAttack{Range: 1, Area: 2, Damage: 300, AdditionalDamage: 40}
+	return data
+}
+
+func BenchmarkRead_Reflection(b *testing.B) {
+	raw := encodedBenchmarkRecord(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var data NPCFileData
+		_ = binary.Read(bytes.NewReader(raw), binary.LittleEndian, &data)
+	}
+}
+
+func BenchmarkRead_Manual(b *testing.B) {
+	raw := encodedBenchmarkRecord(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Read(bytes.NewReader(raw))
+	}
+}
+
+func BenchmarkWrite_Reflection(b *testing.B) {
+	data := benchmarkRecord()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = binary.Write(io.Discard, binary.LittleEndian, data)
+	}
+}
+
+func BenchmarkWrite_Manual(b *testing.B) {
+	data := benchmarkRecord()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Write(io.Discard, data)
+	}
+}
+
+func encodedBenchmarkRecord(b *testing.B) []byte {
+	b.Helper()
+	data := benchmarkRecord()
+	raw, err := data.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return raw
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	agutils "github.com/project-agonyl/agonyl-utils-go/utils"
 )
 
 func TestRead_ValidRecord(t *testing.T) {
@@ -35,37 +37,35 @@ func TestRead_Truncated(t *testing.T) {
 	buf := bytes.NewBuffer(truncated)
 
 	_, err := Read(buf)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
 }
 
 func TestRead_EmptyStream(t *testing.T) {
 	buf := bytes.NewBuffer(nil)
 	_, err := Read(buf)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
 }
 
 func TestRead_InvalidReader(t *testing.T) {
-	var r errReader
-	_, err := Read(&r)
-	assert.Error(t, err)
-}
-
-type errReader struct{}
-
-func (errReader) Read([]byte) (int, error) {
-	return 0, io.ErrClosedPipe
+	_, err := Read(agutils.FailAfterReader(bytes.NewReader(nil), 0))
+	assert.ErrorIs(t, err, agutils.ErrInjected)
 }
 
 func TestWrite_InvalidWriter(t *testing.T) {
-	var w errWriter
-	err := Write(&w, NPCFileData{})
-	assert.Error(t, err)
+	err := Write(agutils.FailAfterWriter(io.Discard, 0), NPCFileData{})
+	assert.ErrorIs(t, err, agutils.ErrInjected)
 }
 
-type errWriter struct{}
-
-func (errWriter) Write([]byte) (int, error) {
-	return 0, io.ErrShortWrite
+func TestReadWrite_FailureAtVariousOffsets(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, makeNPCWithName("Guard")))
+	raw := buf.Bytes()
+	for n := 0; n < len(raw); n++ {
+		_, err := Read(agutils.FailAfterReader(bytes.NewReader(raw), n))
+		require.ErrorIs(t, err, agutils.ErrInjected, "read failure after %d bytes", n)
+		err = Write(agutils.FailAfterWriter(io.Discard, n), makeNPCWithName("Guard"))
+		require.ErrorIs(t, err, agutils.ErrInjected, "write failure after %d bytes", n)
+	}
 }
 
 func TestWriteThenRead_RoundTrip(t *testing.T) {
@@ -161,6 +161,21 @@ func TestGetName_ExactBufferSize(t *testing.T) {
 	assert.Equal(t, "ABCDEFGHIJKLMNOPQRST", name)
 }
 
+func TestGetNameChecked(t *testing.T) {
+	var n NPCFileData
+	copy(n.Name[:], "Guard")
+	got, terminated := n.GetNameChecked()
+	assert.Equal(t, "Guard", got)
+	assert.True(t, terminated)
+
+	for i := range n.Name {
+		n.Name[i] = 'X'
+	}
+	got, terminated = n.GetNameChecked()
+	assert.Equal(t, n.GetName(), got)
+	assert.False(t, terminated, "a full field with no null is reported")
+}
+
 func TestRead_LittleEndian(t *testing.T) {
 	// Manually build a minimal record with known byte order
 	// Id=0x0102 (LE: 02 01), RespawnRate=0x0304 (LE: 04 03), etc.
@@ -204,3 +219,43 @@ func makeNPCWithName(name string) NPCFileData {
 	copy(n.Name[:], name)
 	return n
 }
+
+func TestRecordSize(t *testing.T) {
+	assert.Equal(t, RecordSize, binary.Size(NPCFileData{}))
+}
+
+func TestMarshalBinary_MatchesReflection(t *testing.T) {
+	// Every byte distinct so a misplaced field shows up as a mismatch.
+	raw := make([]byte, RecordSize)
+	for i := range raw {
+		raw[i] = byte(i + 1)
+	}
+	var data NPCFileData
+	require.NoError(t, binary.Read(bytes.NewReader(raw), binary.LittleEndian, &data))
+
+	var want bytes.Buffer
+	require.NoError(t, binary.Write(&want, binary.LittleEndian, data))
+	got, err := data.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, want.Bytes(), got)
+	assert.Equal(t, raw, got)
+}
+
+func TestUnmarshalBinary_MatchesReflection(t *testing.T) {
+	raw := make([]byte, RecordSize)
+	for i := range raw {
+		raw[i] = byte(0xFF - i)
+	}
+	var want NPCFileData
+	require.NoError(t, binary.Read(bytes.NewReader(raw), binary.LittleEndian, &want))
+
+	var got NPCFileData
+	require.NoError(t, got.UnmarshalBinary(raw))
+	assert.Equal(t, want, got)
+}
+
+func TestUnmarshalBinary_ShortBuffer(t *testing.T) {
+	var data NPCFileData
+	err := data.UnmarshalBinary(make([]byte, RecordSize-1))
+	assert.ErrorIs(t, err, ErrShortBuffer)
+}
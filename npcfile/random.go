@@ -0,0 +1,46 @@
+package npcfile
+
+import agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+
+// RandomN returns n NPC records generated deterministically from seed, for
+// tests and fuzz corpora: the same seed and n always give the same records.
+// Ids run from 1 to n, every name is a null-terminated ASCII name and
+// AttackSpeedLow never exceeds AttackSpeedHigh, so each record passes
+// Validate and round-trips through Write and Read unchanged.
+func RandomN(seed int64, n int) []NPCFileData {
+	rng := agutils.NewRand(seed)
+	records := make([]NPCFileData, n)
+	for i := range records {
+		r := &records[i]
+		agutils.RandomName(rng, r.Name[:])
+		r.Id = uint16(i + 1)
+		r.RespawnRate = uint16(rng.UintN(600))
+		r.AttackTypeInfo = byte(rng.UintN(256))
+		r.TargetSelectionInfo = byte(rng.UintN(256))
+		r.Defense = byte(rng.UintN(256))
+		r.AdditionalDefense = byte(rng.UintN(256))
+		for j := range 1 + rng.IntN(len(r.Attacks)) {
+			r.Attacks[j] = NPCAttack{
+				Range:            uint16(1 + rng.UintN(20)),
+				Area:             uint16(rng.UintN(10)),
+				Damage:           uint16(1 + rng.UintN(1000)),
+				AdditionalDamage: uint16(rng.UintN(500)),
+			}
+		}
+
+		r.AttackSpeedLow = uint16(rng.UintN(2000))
+		r.AttackSpeedHigh = r.AttackSpeedLow + uint16(rng.UintN(2000))
+		r.MovementSpeed = uint32(rng.UintN(1000))
+		r.Level = byte(1 + rng.UintN(150))
+		r.PlayerExp = uint16(rng.UintN(1 << 16))
+		r.Appearance = byte(rng.UintN(256))
+		r.HP = 1 + uint32(rng.UintN(1_000_000))
+		r.BlueAttackDefense = uint16(rng.UintN(1000))
+		r.RedAttackDefense = uint16(rng.UintN(1000))
+		r.GreyAttackDefense = uint16(rng.UintN(1000))
+		r.MercenaryExp = uint16(rng.UintN(1 << 16))
+		r.Unknown = uint16(rng.UintN(1 << 16))
+	}
+
+	return records
+}
@@ -0,0 +1,28 @@
+package npcfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomN_Deterministic(t *testing.T) {
+	assert.Equal(t, RandomN(42, 20), RandomN(42, 20))
+	assert.NotEqual(t, RandomN(42, 20), RandomN(43, 20))
+	assert.Empty(t, RandomN(1, 0))
+}
+
+func TestRandomN_RoundTrip(t *testing.T) {
+	records := RandomN(7, 50)
+	var buf bytes.Buffer
+	for i := range records {
+		require.NoError(t, records[i].Validate())
+		require.NoError(t, Write(&buf, records[i]))
+	}
+
+	got, err := ReadAll(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, records, got)
+}
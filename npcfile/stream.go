@@ -0,0 +1,101 @@
+package npcfile
+
+import (
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// RecordError reports the record that ReadAllIndexed failed to read: its
+// zero-based Index and the byte Offset at which it starts.
+type RecordError struct {
+	Index  int
+	Offset int64
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d at offset %d: %v", e.Index, e.Offset, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Iterate reads consecutive NPC records from r until EOF and calls fn with
+// the index and value of each. A stream that ends part-way through a record
+// returns io.ErrUnexpectedEOF; an error returned by fn stops iteration and is
+// returned unchanged. Records are decoded into a reused buffer, so Iterate
+// keeps memory flat regardless of the stream's length.
+func Iterate(r io.Reader, fn func(i int, n NPCFileData) error) error {
+	return IterateProgress(r, fn, nil)
+}
+
+// IterateProgress is Iterate with a progress callback: after each record is
+// decoded and passed to fn, onProgress is called with the total number of
+// bytes consumed from r so far. A nil onProgress is allowed.
+func IterateProgress(r io.Reader, fn func(i int, n NPCFileData) error, onProgress func(bytesRead int64)) error {
+	var buf [RecordSize]byte
+	var bytesRead int64
+	for i := 0; ; i++ {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		var n NPCFileData
+		if err := n.UnmarshalBinary(buf[:]); err != nil {
+			return err
+		}
+
+		if err := fn(i, n); err != nil {
+			return err
+		}
+
+		bytesRead += RecordSize
+		if onProgress != nil {
+			onProgress(bytesRead)
+		}
+	}
+}
+
+// ReadAll reads consecutive NPC records from r until EOF and returns them in
+// order. An empty stream yields an empty, non-nil slice. On failure the error
+// is a *RecordError naming the record that could not be read, and no records
+// are returned; use ReadAllIndexed to keep the ones read before it.
+func ReadAll(r io.Reader) ([]NPCFileData, error) {
+	records, err := ReadAllIndexed(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// ReadAllIndexed is ReadAll that also returns the records read successfully
+// before a failure. The error is a *RecordError carrying the failing record's
+// index and byte offset and wrapping the underlying error, for example
+// io.ErrUnexpectedEOF when the stream ends part-way through a record.
+func ReadAllIndexed(r io.Reader) ([]NPCFileData, error) {
+	records := []NPCFileData{}
+	err := Iterate(r, func(_ int, n NPCFileData) error {
+		records = append(records, n)
+		return nil
+	})
+	if err != nil {
+		return records, &RecordError{Index: len(records), Offset: int64(len(records)) * RecordSize, Err: err}
+	}
+
+	return records, nil
+}
+
+// MemSize returns the approximate number of bytes records occupies in memory:
+// the slice header plus cap(records) NPCFileData values. Summed over a loaded
+// dataset it gives a figure for capacity planning; allocator overhead is not
+// included.
+func MemSize(records []NPCFileData) int {
+	return int(unsafe.Sizeof(records)) + cap(records)*int(unsafe.Sizeof(NPCFileData{}))
+}
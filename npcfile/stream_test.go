@@ -0,0 +1,110 @@
+package npcfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRecords(t *testing.T, records ...NPCFileData) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, r := range records {
+		require.NoError(t, Write(&buf, r))
+	}
+	return &buf
+}
+
+func TestReadAll_MultipleRecords(t *testing.T) {
+	a := makeNPCWithName("Guard")
+	a.Id = 1
+	b := makeNPCWithName("Wolf")
+	b.Id = 2
+
+	records, err := ReadAll(writeRecords(t, a, b))
+	require.NoError(t, err)
+	assert.Equal(t, []NPCFileData{a, b}, records)
+}
+
+func TestReadAll_Empty(t *testing.T) {
+	records, err := ReadAll(bytes.NewReader(nil))
+	require.NoError(t, err)
+	assert.NotNil(t, records)
+	assert.Empty(t, records)
+}
+
+func TestReadAll_TrailingPartialRecord(t *testing.T) {
+	buf := writeRecords(t, makeNPCWithName("Guard"))
+	buf.Write([]byte{1, 2, 3})
+
+	_, err := ReadAll(buf)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReadAllIndexed_ReportsFailingRecord(t *testing.T) {
+	a := makeNPCWithName("Guard")
+	b := makeNPCWithName("Wolf")
+	buf := writeRecords(t, a, b)
+	buf.Write([]byte{1, 2, 3})
+
+	records, err := ReadAllIndexed(buf)
+	assert.Equal(t, []NPCFileData{a, b}, records, "records before the failure must be returned")
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	var recErr *RecordError
+	require.True(t, errors.As(err, &recErr))
+	assert.Equal(t, 2, recErr.Index)
+	assert.Equal(t, int64(2*RecordSize), recErr.Offset)
+
+	records, err = ReadAll(writeRecords(t, a))
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
+
+func TestReadAll_ErrorCarriesIndex(t *testing.T) {
+	buf := writeRecords(t, makeNPCWithName("Guard"))
+	buf.Write([]byte{1})
+
+	records, err := ReadAll(buf)
+	assert.Nil(t, records)
+	var recErr *RecordError
+	require.True(t, errors.As(err, &recErr))
+	assert.Equal(t, 1, recErr.Index)
+	assert.Equal(t, int64(RecordSize), recErr.Offset)
+}
+
+func TestIterate_StopsOnCallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	buf := writeRecords(t, makeNPCWithName("A"), makeNPCWithName("B"), makeNPCWithName("C"))
+
+	var seen []int
+	err := Iterate(buf, func(i int, _ NPCFileData) error {
+		seen = append(seen, i)
+		if i == 1 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []int{0, 1}, seen)
+}
+
+func TestIterateProgress_ReportsBytesRead(t *testing.T) {
+	buf := writeRecords(t, makeNPCWithName("A"), makeNPCWithName("B"), makeNPCWithName("C"))
+
+	var progress []int64
+	err := IterateProgress(buf, func(int, NPCFileData) error { return nil }, func(n int64) {
+		progress = append(progress, n)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{RecordSize, 2 * RecordSize, 3 * RecordSize}, progress)
+}
+
+func TestMemSize(t *testing.T) {
+	records := make([]NPCFileData, 3, 8)
+	assert.Equal(t, MemSize(nil)+8*int(unsafe.Sizeof(NPCFileData{})), MemSize(records))
+}
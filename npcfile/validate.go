@@ -0,0 +1,68 @@
+package npcfile
+
+import (
+	"errors"
+
+	agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+)
+
+// Validation errors.
+var (
+	// ErrEmptyName is reported when the record's Name starts with a NUL byte.
+	ErrEmptyName = errors.New("npcfile: empty name")
+
+	// ErrAttackSpeedRange is reported when AttackSpeedLow is greater than
+	// AttackSpeedHigh.
+	ErrAttackSpeedRange = errors.New("npcfile: attack speed low exceeds high")
+)
+
+// check ties a validation sentinel to its Problem code and field.
+type check struct {
+	err   error
+	code  string
+	field string
+	fails func(n *NPCFileData) bool
+}
+
+var checks = []check{
+	{ErrEmptyName, "npcfile.empty_name", "Name", func(n *NPCFileData) bool {
+		return n.Name[0] == 0
+	}},
+	{ErrAttackSpeedRange, "npcfile.attack_speed_range", "AttackSpeedLow", func(n *NPCFileData) bool {
+		return n.AttackSpeedLow > n.AttackSpeedHigh
+	}},
+}
+
+// Validate reports every problem found in n, joined with errors.Join. Each
+// problem is one of ErrEmptyName or ErrAttackSpeedRange. Validate does not
+// modify n.
+func (n *NPCFileData) Validate() error {
+	var errs []error
+	for _, c := range checks {
+		if c.fails(n) {
+			errs = append(errs, c.err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Problems reports the same findings as Validate in machine-readable form.
+// Codes are "npcfile.empty_name" and "npcfile.attack_speed_range"; Index is
+// always -1 because a record has no list position of its own. It returns nil
+// when n is valid.
+func (n *NPCFileData) Problems() []agutils.Problem {
+	var problems []agutils.Problem
+	for _, c := range checks {
+		if c.fails(n) {
+			problems = append(problems, agutils.Problem{
+				Code:    c.code,
+				Field:   c.field,
+				Index:   -1,
+				Message: c.err.Error(),
+			})
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,35 @@
+package npcfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	var n NPCFileData
+	copy(n.Name[:], "Guard")
+	n.AttackSpeedLow = 10
+	n.AttackSpeedHigh = 20
+	assert.NoError(t, n.Validate())
+	assert.Empty(t, n.Problems())
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	var n NPCFileData
+	n.AttackSpeedLow = 30
+	n.AttackSpeedHigh = 20
+
+	err := n.Validate()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrEmptyName)
+	assert.ErrorIs(t, err, ErrAttackSpeedRange)
+
+	problems := n.Problems()
+	require.Len(t, problems, 2)
+	assert.Equal(t, "npcfile.empty_name", problems[0].Code)
+	assert.Equal(t, "Name", problems[0].Field)
+	assert.Equal(t, -1, problems[0].Index)
+	assert.Equal(t, "npcfile.attack_speed_range", problems[1].Code)
+}
@@ -0,0 +1,62 @@
+package protocol
+
+import (
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/cyberinferno/go-utils/utils"
+)
+
+// ErrInvalidIPv4 is returned when an address written into a fixed IP field is
+// not an IPv4 address. The 0x10-byte fields hold a null-terminated dotted
+// string, which fits any IPv4 address but not IPv6 addresses or hostnames.
+var ErrInvalidIPv4 = errors.New("protocol: address is not IPv4")
+
+// ipField formats ip as a null-padded dotted IPv4 string for a 0x10-byte
+// field.
+func ipField(ip net.IP) ([0x10]byte, error) {
+	var field [0x10]byte
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return field, ErrInvalidIPv4
+	}
+
+	copy(field[:], ip4.String())
+	return field, nil
+}
+
+// parseIPField parses a null-padded dotted IP string, returning nil if the
+// field does not hold a valid address.
+func parseIPField(field []byte) net.IP {
+	return net.ParseIP(utils.ReadStringFromBytes(field))
+}
+
+// addrIPPort extracts the IP and port from addr. TCP and UDP addresses are
+// used directly; any other address must format as "host:port" with a literal
+// IP host.
+func addrIPPort(addr net.Addr) (net.IP, uint32, error) {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP, uint32(a.Port), nil
+	case *net.UDPAddr:
+		return a.IP, uint32(a.Port), nil
+	}
+
+	host, portStr, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, 0, ErrInvalidIPv4
+	}
+
+	return ip, uint32(port), nil
+}
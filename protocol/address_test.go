@@ -0,0 +1,68 @@
+package protocol
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stringAddr string
+
+func (a stringAddr) Network() string { return "tcp" }
+func (a stringAddr) String() string  { return string(a) }
+
+func TestMsgS2CGateInfo_IP(t *testing.T) {
+	msg := NewMsgS2CGateInfo(1, "", 0)
+	require.NoError(t, msg.SetIP(net.ParseIP("255.255.255.255")))
+	assert.Equal(t, [0x10]byte{'2', '5', '5', '.', '2', '5', '5', '.', '2', '5', '5', '.', '2', '5', '5', 0}, msg.ZaIP)
+	assert.True(t, net.IPv4bcast.Equal(msg.GetIP()))
+
+	// IPv4-mapped IPv6 is written in dotted form.
+	require.NoError(t, msg.SetIP(net.ParseIP("::ffff:10.0.0.7")))
+	assert.Equal(t, "10.0.0.7", msg.GetIP().String())
+}
+
+func TestMsgS2CGateInfo_SetIPRejectsIPv6(t *testing.T) {
+	msg := NewMsgS2CGateInfo(1, "10.0.0.1", 0)
+	assert.ErrorIs(t, msg.SetIP(net.ParseIP("2001:db8::1")), ErrInvalidIPv4)
+	assert.ErrorIs(t, msg.SetIP(nil), ErrInvalidIPv4)
+	assert.Equal(t, "10.0.0.1", msg.GetIP().String(), "field is unchanged on error")
+}
+
+func TestMsgS2CGateInfo_SetAddr(t *testing.T) {
+	msg := NewMsgS2CGateInfo(1, "", 0)
+	require.NoError(t, msg.SetAddr(&net.TCPAddr{IP: net.IPv4(192, 168, 1, 20), Port: 9999}))
+	assert.Equal(t, "192.168.1.20", msg.GetIP().String())
+	assert.Equal(t, uint32(9999), msg.ZaPort)
+
+	require.NoError(t, msg.SetAddr(stringAddr("127.0.0.1:7000")))
+	assert.Equal(t, "127.0.0.1", msg.GetIP().String())
+	assert.Equal(t, uint32(7000), msg.ZaPort)
+}
+
+func TestMsgS2CGateInfo_SetAddrRejectsHostname(t *testing.T) {
+	msg := NewMsgS2CGateInfo(1, "", 5)
+	assert.ErrorIs(t, msg.SetAddr(stringAddr("zone.example.com:7000")), ErrInvalidIPv4)
+	assert.ErrorIs(t, msg.SetAddr(&net.TCPAddr{IP: net.ParseIP("::1"), Port: 7000}), ErrInvalidIPv4)
+	assert.Equal(t, uint32(5), msg.ZaPort, "port is unchanged on error")
+}
+
+func TestMsgGate2LsConnect_Addr(t *testing.T) {
+	msg := NewMsgGate2LsConnect(1, 2, "", 0, "gate")
+	require.NoError(t, msg.SetAddr(&net.UDPAddr{IP: net.IPv4(10, 1, 2, 3), Port: 5555}))
+	assert.Equal(t, "10.1.2.3", msg.GetIP().String())
+	assert.Equal(t, uint32(5555), msg.Port)
+
+	data, err := GetBytesFromMsg(&msg)
+	require.NoError(t, err)
+	var decoded MsgGate2LsConnect
+	require.NoError(t, ReadMsgFromBytes(data, &decoded))
+	assert.Equal(t, "10.1.2.3", decoded.GetIP().String())
+}
+
+func TestGetIP_Invalid(t *testing.T) {
+	msg := NewMsgGate2LsConnect(1, 2, "not-an-ip", 0, "gate")
+	assert.Nil(t, msg.GetIP())
+}
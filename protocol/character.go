@@ -1,6 +1,10 @@
 package protocol
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/cyberinferno/go-utils/utils"
+)
 
 type MsgC2SAskDeletePlayer struct {
 	MsgHead
@@ -49,6 +53,20 @@ type CharacterInfo struct {
 	Wear     [0xA]AclCharacterWear
 }
 
+// SetName stores name in the fixed Name field, truncating it to 0x15 bytes
+// and clearing any bytes left over from a previous name.
+func (c *CharacterInfo) SetName(name string) {
+	copy(c.Name[:], utils.MakeFixedLengthStringBytes(name, len(c.Name)))
+}
+
+// SetWear copies up to len(c.Wear) entries from items into Wear and zeroes
+// the remaining slots, so a reused CharacterInfo never keeps equipment from a
+// previous character. Extra items are ignored.
+func (c *CharacterInfo) SetWear(items []AclCharacterWear) {
+	n := copy(c.Wear[:], items)
+	clear(c.Wear[n:])
+}
+
 type MsgS2CCharacterList struct {
 	MsgHead
 	CharacterList [0x5]CharacterInfo
@@ -87,6 +105,52 @@ func NewMsgS2CCharacterList(pcId uint32, characterList []CharacterInfo) MsgS2CCh
 	return msgS2CCharacterList
 }
 
+// WornItem is one equipped item of a CharacterSummary. ID is the server-side
+// item identifier sent as ItemPtr and Slot is the wear index.
+type WornItem struct {
+	ID     uint32
+	Code   uint32
+	Option uint32
+	Slot   uint32
+}
+
+// CharacterSummary is the domain view of a character-list entry, free of the
+// packet's fixed-size arrays.
+type CharacterSummary struct {
+	Name   string
+	Class  byte
+	Nation byte
+	Level  uint32
+	Wear   []WornItem
+}
+
+// NewMsgS2CCharacterListFromSummaries builds a character list from domain
+// values. Each summary is converted with SetName and SetWear, so names are
+// truncated to the Name field and at most len(CharacterInfo.Wear) items are
+// sent. Slots beyond the given characters get the Class 255 empty-slot
+// sentinel and characters beyond the five list slots are ignored, as in
+// NewMsgS2CCharacterList. SlotUsed is left zero.
+func NewMsgS2CCharacterListFromSummaries(pcId uint32, characters []CharacterSummary) MsgS2CCharacterList {
+	infos := make([]CharacterInfo, min(len(characters), len(MsgS2CCharacterList{}.CharacterList)))
+	for i := range infos {
+		c := characters[i]
+		infos[i].SetName(c.Name)
+		infos[i].Class = c.Class
+		infos[i].Nation = c.Nation
+		infos[i].Level = c.Level
+
+		wear := make([]AclCharacterWear, min(len(c.Wear), len(infos[i].Wear)))
+		for j := range wear {
+			item := c.Wear[j]
+			wear[j] = AclCharacterWear{ItemPtr: item.ID, ItemCode: item.Code, ItemOption: item.Option, WearIndex: item.Slot}
+		}
+
+		infos[i].SetWear(wear)
+	}
+
+	return NewMsgS2CCharacterList(pcId, infos)
+}
+
 func NewMsgS2CCharacterListEmpty(pcId uint32) MsgS2CCharacterList {
 	msgS2CCharacterList := MsgS2CCharacterList{
 		MsgHead: MsgHead{
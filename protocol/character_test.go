@@ -0,0 +1,101 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/cyberinferno/go-utils/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharacterInfo_SetName(t *testing.T) {
+	var c CharacterInfo
+	c.SetName("LongerPreviousName")
+	c.SetName("Hero")
+	assert.Equal(t, "Hero", utils.ReadStringFromBytes(c.Name[:]))
+	assert.Equal(t, [0x15]byte{'H', 'e', 'r', 'o'}, c.Name)
+
+	c.SetName("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	assert.Equal(t, "ABCDEFGHIJKLMNOPQRSTU", string(c.Name[:]))
+}
+
+func TestCharacterInfo_SetWear(t *testing.T) {
+	var c CharacterInfo
+	for i := range c.Wear {
+		c.Wear[i] = AclCharacterWear{ItemPtr: 1, ItemCode: 2, ItemOption: 3, WearIndex: uint32(i)}
+	}
+
+	items := []AclCharacterWear{{ItemCode: 100, WearIndex: 0}, {ItemCode: 200, WearIndex: 4}}
+	c.SetWear(items)
+	assert.Equal(t, items[0], c.Wear[0])
+	assert.Equal(t, items[1], c.Wear[1])
+	for i := 2; i < len(c.Wear); i++ {
+		assert.Zero(t, c.Wear[i], "slot %d must be cleared", i)
+	}
+}
+
+func TestCharacterInfo_SetWearTruncates(t *testing.T) {
+	var c CharacterInfo
+	items := make([]AclCharacterWear, 12)
+	for i := range items {
+		items[i].ItemCode = uint32(i + 1)
+	}
+
+	c.SetWear(items)
+	assert.Equal(t, uint32(10), c.Wear[9].ItemCode)
+
+	c.SetWear(nil)
+	assert.Equal(t, [0xA]AclCharacterWear{}, c.Wear)
+}
+
+func TestNewMsgS2CCharacterListFromSummaries(t *testing.T) {
+	msg := NewMsgS2CCharacterListFromSummaries(42, []CharacterSummary{
+		{
+			Name:   "Hero",
+			Class:  2,
+			Nation: 1,
+			Level:  55,
+			Wear:   []WornItem{{ID: 900, Code: 1001, Option: 7, Slot: 0}, {ID: 901, Code: 2002, Slot: 4}},
+		},
+		{Name: "ABCDEFGHIJKLMNOPQRSTUVWXYZ", Class: 0, Level: 1},
+	})
+
+	assert.Equal(t, uint32(42), msg.PcId)
+	assert.Equal(t, msg.GetSize(), msg.Size)
+
+	hero := msg.CharacterList[0]
+	assert.Equal(t, "Hero", utils.ReadStringFromBytes(hero.Name[:]))
+	assert.Equal(t, byte(2), hero.Class)
+	assert.Equal(t, byte(1), hero.Nation)
+	assert.Equal(t, uint32(55), hero.Level)
+	assert.Equal(t, AclCharacterWear{ItemPtr: 900, ItemCode: 1001, ItemOption: 7, WearIndex: 0}, hero.Wear[0])
+	assert.Equal(t, AclCharacterWear{ItemPtr: 901, ItemCode: 2002, WearIndex: 4}, hero.Wear[1])
+	for i := 2; i < len(hero.Wear); i++ {
+		assert.Zero(t, hero.Wear[i], "wear slot %d", i)
+	}
+
+	assert.Equal(t, "ABCDEFGHIJKLMNOPQRSTU", string(msg.CharacterList[1].Name[:]))
+	assert.Equal(t, byte(0), msg.CharacterList[1].Class)
+	for i := 2; i < len(msg.CharacterList); i++ {
+		assert.Equal(t, byte(255), msg.CharacterList[i].Class, "slot %d must be empty", i)
+	}
+}
+
+func TestNewMsgS2CCharacterListFromSummaries_Limits(t *testing.T) {
+	wear := make([]WornItem, 12)
+	for i := range wear {
+		wear[i].Code = uint32(i + 1)
+	}
+
+	characters := make([]CharacterSummary, 7)
+	for i := range characters {
+		characters[i] = CharacterSummary{Name: "Alt", Level: uint32(i + 1), Wear: wear}
+	}
+
+	msg := NewMsgS2CCharacterListFromSummaries(1, characters)
+	for i, c := range msg.CharacterList {
+		assert.Equal(t, uint32(i+1), c.Level)
+		assert.Equal(t, uint32(10), c.Wear[9].ItemCode)
+	}
+
+	assert.Equal(t, NewMsgS2CCharacterListEmpty(1), NewMsgS2CCharacterListFromSummaries(1, nil))
+}
@@ -0,0 +1,41 @@
+package protocol
+
+import "fmt"
+
+// MessageDescriptor describes one registered message type: its Go type name,
+// the routing key Decode uses for it, its encoded size in bytes and the
+// lowest client version that supports it (0 for any), as set with
+// SetMinClientVersion.
+type MessageDescriptor struct {
+	Name string
+	RoutingKey
+	Size             uint32
+	MinClientVersion uint32
+}
+
+// String formats the descriptor as its name, routing key and size, for
+// example "MsgC2SLogin C2S ctrl=0x01 cmd=0xE0 size=52". Without it the
+// embedded RoutingKey's String would be promoted and hide Name and Size.
+func (d MessageDescriptor) String() string {
+	return fmt.Sprintf("%s %s size=%d", d.Name, d.RoutingKey, d.Size)
+}
+
+// MessageDescriptors returns a descriptor for every registered message, in
+// registry order. The routing keys mirror the Ctrl/Cmd/Protocol values
+// hard-coded in each message's constructor. A message sent in more than one
+// direction (such as MsgZACLChkTimeTick) has one descriptor per direction.
+func MessageDescriptors() []MessageDescriptor {
+	versionMu.RLock()
+	defer versionMu.RUnlock()
+	descriptors := make([]MessageDescriptor, len(registry))
+	for i := range registry {
+		descriptors[i] = MessageDescriptor{
+			Name:             registry[i].name,
+			RoutingKey:       registry[i].key,
+			Size:             registry[i].new().GetSize(),
+			MinClientVersion: registryIndex[registry[i].key].minClientVersion,
+		}
+	}
+
+	return descriptors
+}
@@ -0,0 +1,157 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageDescriptors_CoverRegistry(t *testing.T) {
+	descriptors := MessageDescriptors()
+	require.Len(t, descriptors, len(registry))
+	for _, d := range descriptors {
+		assert.NotEmpty(t, d.Name)
+		assert.GreaterOrEqual(t, d.Size, uint32(headNoProtocolSize), d.Name)
+		if d.HasProtocol {
+			assert.Equal(t, byte(0x03), d.Ctrl, d.Name)
+			assert.Equal(t, byte(0xFF), d.Cmd, d.Name)
+		}
+	}
+}
+
+func TestMessageDescriptor_String(t *testing.T) {
+	d := MessageDescriptors()[0]
+	want := fmt.Sprintf("MsgC2SLogin C2S ctrl=0x01 cmd=0xE0 size=%d", d.Size)
+	assert.Equal(t, want, d.String())
+	assert.Equal(t, want, fmt.Sprintf("%v", d), "Name and Size are not hidden by the RoutingKey")
+}
+
+func TestMessageDescriptors_NameMatchesType(t *testing.T) {
+	for i, d := range MessageDescriptors() {
+		typ := reflect.TypeOf(registry[i].new()).Elem()
+		assert.Equal(t, typ.Name(), d.Name)
+	}
+}
+
+func TestMessageDescriptors_NoCollisions(t *testing.T) {
+	seen := make(map[RoutingKey]string)
+	for _, d := range MessageDescriptors() {
+		other, dup := seen[d.RoutingKey]
+		assert.False(t, dup, "%s and %s share routing key %+v", other, d.Name, d.RoutingKey)
+		seen[d.RoutingKey] = d.Name
+	}
+}
+
+// TestMessageDescriptors_MatchConstructors checks the registry against the
+// header bytes the constructors actually produce.
+func TestMessageDescriptors_MatchConstructors(t *testing.T) {
+	c2sLogin := NewMsgC2SLogin("user", "pass")
+	c2sSelectServer := NewMsgC2SSelectServer(1)
+	c2sCharacterLogin := NewMsgC2SCharacterLogin(1, "Hero", 562)
+	c2sWorldLogin := NewMsgC2SWorldLogin(1, "Hero")
+	c2sCharacterLogout := NewMsgC2SCharacterLogout(1)
+	c2sSay := NewMsgC2SSay(1, General, "Hero", "hi")
+	c2sReqClanInfo := NewMsgC2SReqClanInfo(1)
+	c2sAskDeletePlayer := NewMsgC2SAskDeletePlayer(1, "Hero")
+	ls2ClSay := NewMsgLs2ClSay("hi")
+	s2cGateInfo := NewMsgS2CGateInfo(1, "127.0.0.1", 9000)
+	s2cCharacterList := NewMsgS2CCharacterListEmpty(1)
+	s2cCharacterLogin := NewMsgS2CCharacterLogin(1, "Hero", 0, 1)
+	s2cSay := NewMsgS2CSay(1, General, "Hero", "hi")
+	gate2LsConnect := NewMsgGate2LsConnect(1, 1, "127.0.0.1", 9000, "gate")
+	gate2LsAccLogout := NewMsgGate2LsAccLogout(0, "user")
+	gate2LsPreparedAccLogin := NewMsgGate2LsPreparedAccLogin("user")
+	gate2ZsConnect := NewMsgGate2ZsConnect(1)
+	ls2GateLogin := NewMsgLs2GateLogin("user", 1)
+	ls2ZaDisconnect := NewMsgLs2ZaDisconnect(0, "user", 1)
+
+	constructed := map[string]Message{
+		"MsgC2SLogin":                &c2sLogin,
+		"MsgC2SSelectServer":         &c2sSelectServer,
+		"MsgC2SGateLogin":            NewMsgC2SGateLogin(1, "user", "pass"),
+		"MsgZACLChkTimeTick":         NewMsgZACLChkTimeTick(1, 2, 3),
+		"MsgC2SCharacterLogin":       &c2sCharacterLogin,
+		"MsgC2SWorldLogin":           &c2sWorldLogin,
+		"MsgC2SCharacterLogout":      &c2sCharacterLogout,
+		"MsgC2SSay":                  &c2sSay,
+		"MsgC2SReqClanInfo":          &c2sReqClanInfo,
+		"MsgC2SAskDeletePlayer":      &c2sAskDeletePlayer,
+		"MsgLs2ClSay":                &ls2ClSay,
+		"MsgS2CGateInfo":             &s2cGateInfo,
+		"MsgS2CError":                NewMsgS2CError(1, 2, "oops"),
+		"MsgS2CCharacterList":        &s2cCharacterList,
+		"MsgS2CCharacterLogin":       &s2cCharacterLogin,
+		"MsgS2CLevelUp":              NewMsgS2CLevelUp(10),
+		"MsgS2CSay":                  &s2cSay,
+		"MsgGate2LsConnect":          &gate2LsConnect,
+		"MsgGate2LsAccLogout":        &gate2LsAccLogout,
+		"MsgGate2LsPreparedAccLogin": &gate2LsPreparedAccLogin,
+		"MsgGate2ZsConnect":          &gate2ZsConnect,
+		"MsgLs2GateLogin":            &ls2GateLogin,
+		"MsgZa2ZsAccLogout":          NewMsgZa2ZsAccLogout(1, 0),
+		"MsgLs2ZaDisconnect":         &ls2ZaDisconnect,
+	}
+
+	for _, d := range MessageDescriptors() {
+		m, ok := constructed[d.Name]
+		if !ok {
+			continue // no constructor for this message
+		}
+
+		data, err := GetBytesFromMsg(m)
+		require.NoError(t, err, d.Name)
+		assert.Equal(t, d.Ctrl, data[8], "%s ctrl", d.Name)
+		assert.Equal(t, d.Cmd, data[9], "%s cmd", d.Name)
+		if d.HasProtocol {
+			assert.Equal(t, d.Protocol, binary.LittleEndian.Uint16(data[headNoProtocolSize:]), "%s protocol", d.Name)
+		}
+		assert.Equal(t, d.Size, uint32(len(data)), "%s size", d.Name)
+	}
+}
+
+func TestCheckRoutingUniqueness_Registry(t *testing.T) {
+	assert.NoError(t, CheckRoutingUniqueness())
+}
+
+func TestCheckRoutingUniqueness_NamesBothMessages(t *testing.T) {
+	regs := []registration{
+		{"MsgA", noProtocol(DirectionC2S, 0x01, 0xE0), nil},
+		{"MsgB", noProtocol(DirectionS2C, 0x01, 0xE0), nil}, // other direction: fine
+		{"MsgC", noProtocol(DirectionC2S, 0x02, 0xE0), nil}, // other ctrl: fine
+		{"MsgD", noProtocol(DirectionC2S, 0x01, 0xE0), nil},
+	}
+
+	err := checkRoutingUniqueness(regs)
+	require.ErrorIs(t, err, ErrDuplicateRoutingKey)
+	assert.Contains(t, err.Error(), "MsgA and MsgD")
+	assert.NotContains(t, err.Error(), "MsgB")
+	assert.NotContains(t, err.Error(), "MsgC")
+}
+
+func TestCheckRoutingUniqueness_DuplicateProtocol(t *testing.T) {
+	regs := []registration{
+		{"MsgA", withProtocol(DirectionS2C, 0x1107), nil},
+		{"MsgB", withProtocol(DirectionS2C, 0x1107), nil},
+		{"MsgC", withProtocol(DirectionS2C, 0x1108), nil},
+	}
+
+	err := checkRoutingUniqueness(regs)
+	require.ErrorIs(t, err, ErrDuplicateRoutingKey)
+	assert.Contains(t, err.Error(), "MsgA and MsgB both use S2C ctrl=0x03 cmd=0xFF protocol=0x1107")
+}
+
+func TestCheckRoutingUniqueness_NoProtocolShadowsProtocol(t *testing.T) {
+	regs := []registration{
+		{"MsgA", withProtocol(DirectionC2S, 0x1106), nil},
+		{"MsgB", noProtocol(DirectionC2S, 0x03, 0xFF), nil},
+	}
+
+	err := checkRoutingUniqueness(regs)
+	require.ErrorIs(t, err, ErrDuplicateRoutingKey)
+	assert.Contains(t, err.Error(), "MsgB")
+	assert.Contains(t, err.Error(), "shadows MsgA")
+}
@@ -2,6 +2,10 @@ package protocol
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
 
 	"github.com/cyberinferno/go-utils/utils"
 )
@@ -23,6 +27,41 @@ func (msg *MsgGate2LsConnect) SetSize() {
 	msg.Size = msg.GetSize()
 }
 
+// GetIP parses IpAddress back into a net.IP. It returns nil if the field does
+// not hold a valid dotted address.
+func (msg *MsgGate2LsConnect) GetIP() net.IP {
+	return parseIPField(msg.IpAddress[:])
+}
+
+// SetIP writes ip into IpAddress as a dotted IPv4 string. It returns
+// ErrInvalidIPv4, leaving IpAddress unchanged, if ip is not an IPv4 address.
+func (msg *MsgGate2LsConnect) SetIP(ip net.IP) error {
+	field, err := ipField(ip)
+	if err != nil {
+		return err
+	}
+
+	msg.IpAddress = field
+	return nil
+}
+
+// SetAddr sets IpAddress and Port from addr, typically the gate listener's
+// Addr(). It returns ErrInvalidIPv4 if addr does not carry an IPv4 address and
+// leaves msg unchanged on any error.
+func (msg *MsgGate2LsConnect) SetAddr(addr net.Addr) error {
+	ip, port, err := addrIPPort(addr)
+	if err != nil {
+		return err
+	}
+
+	if err := msg.SetIP(ip); err != nil {
+		return err
+	}
+
+	msg.Port = port
+	return nil
+}
+
 func NewMsgGate2LsConnect(serverId byte, agentId byte, ipAddress string, port uint32, name string) MsgGate2LsConnect {
 	msg := MsgGate2LsConnect{
 		MsgHeadNoProtocol: MsgHeadNoProtocol{Ctrl: 0x02, Cmd: 0xE0},
@@ -52,6 +91,58 @@ func (msg *MsgGate2LsAccLogout) SetSize() {
 	msg.Size = msg.GetSize()
 }
 
+// Layouts of the LogoutDate and LogoutTime fields: null-terminated digit
+// strings in the gate's local time. No client or server source for this
+// format is available; the layouts are inferred from the field widths alone
+// (nine bytes fit "YYYYMMDD" and its terminator, seven fit "HHMMSS") and are
+// provisional until checked against a captured logout packet.
+const (
+	logoutDateLayout = "20060102"
+	logoutTimeLayout = "150405"
+)
+
+// ErrInvalidLogoutTime is returned by GetLogoutTime when LogoutDate or
+// LogoutTime does not hold a valid date or time.
+var ErrInvalidLogoutTime = errors.New("protocol: invalid logout date/time")
+
+// SetLogoutTime writes t into LogoutDate as "YYYYMMDD" and LogoutTime as
+// "HHMMSS", each null-terminated. t is formatted in its own location; the
+// fields carry no zone, so pass a time in the zone the login server expects
+// (normally the local zone). A zero t clears both fields.
+func (msg *MsgGate2LsAccLogout) SetLogoutTime(t time.Time) {
+	msg.LogoutDate = [0x09]byte{}
+	msg.LogoutTime = [0x07]byte{}
+	if t.IsZero() {
+		return
+	}
+
+	copy(msg.LogoutDate[:], t.Format(logoutDateLayout))
+	copy(msg.LogoutTime[:], t.Format(logoutTimeLayout))
+}
+
+// GetLogoutTime parses LogoutDate and LogoutTime back into a time in the
+// local zone. It returns the zero time and no error if both fields are empty,
+// and ErrInvalidLogoutTime if either does not parse.
+func (msg *MsgGate2LsAccLogout) GetLogoutTime() (time.Time, error) {
+	date := utils.ReadStringFromBytes(msg.LogoutDate[:])
+	clock := utils.ReadStringFromBytes(msg.LogoutTime[:])
+	if date == "" && clock == "" {
+		return time.Time{}, nil
+	}
+
+	invalid := fmt.Errorf("%w: %q %q", ErrInvalidLogoutTime, date, clock)
+	if len(date) != len(logoutDateLayout) || len(clock) != len(logoutTimeLayout) {
+		return time.Time{}, invalid
+	}
+
+	t, err := time.ParseInLocation(logoutDateLayout+logoutTimeLayout, date+clock, time.Local)
+	if err != nil {
+		return time.Time{}, invalid
+	}
+
+	return t, nil
+}
+
 func NewMsgGate2LsAccLogout(reason byte, account string) MsgGate2LsAccLogout {
 	msg := MsgGate2LsAccLogout{
 		MsgHeadNoProtocol: MsgHeadNoProtocol{Ctrl: 0x02, Cmd: 0xE2},
@@ -62,6 +153,14 @@ func NewMsgGate2LsAccLogout(reason byte, account string) MsgGate2LsAccLogout {
 	return msg
 }
 
+// NewMsgGate2LsAccLogoutAt is NewMsgGate2LsAccLogout with logoutTime stamped
+// into LogoutDate and LogoutTime as SetLogoutTime does.
+func NewMsgGate2LsAccLogoutAt(reason byte, account string, logoutTime time.Time) MsgGate2LsAccLogout {
+	msg := NewMsgGate2LsAccLogout(reason, account)
+	msg.SetLogoutTime(logoutTime)
+	return msg
+}
+
 type MsgGate2LsPreparedAccLogin struct {
 	MsgHeadNoProtocol
 	Account [0x15]byte
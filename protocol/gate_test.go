@@ -0,0 +1,54 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgGate2LsAccLogout_LogoutTimeLayout(t *testing.T) {
+	logout := time.Date(2024, time.March, 7, 9, 5, 30, 0, time.Local)
+	msg := NewMsgGate2LsAccLogoutAt(1, "user", logout)
+
+	assert.Equal(t, [0x09]byte{'2', '0', '2', '4', '0', '3', '0', '7', 0}, msg.LogoutDate)
+	assert.Equal(t, [0x07]byte{'0', '9', '0', '5', '3', '0', 0}, msg.LogoutTime)
+
+	got, err := msg.GetLogoutTime()
+	require.NoError(t, err)
+	assert.True(t, logout.Equal(got), "got %v, want %v", got, logout)
+}
+
+func TestMsgGate2LsAccLogout_SetLogoutTimeDropsSubseconds(t *testing.T) {
+	var msg MsgGate2LsAccLogout
+	msg.SetLogoutTime(time.Date(2023, time.December, 31, 23, 59, 59, 999, time.Local))
+
+	got, err := msg.GetLogoutTime()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, time.December, 31, 23, 59, 59, 0, time.Local), got)
+}
+
+func TestMsgGate2LsAccLogout_ZeroTime(t *testing.T) {
+	msg := NewMsgGate2LsAccLogoutAt(1, "user", time.Now())
+	msg.SetLogoutTime(time.Time{})
+	assert.Equal(t, [0x09]byte{}, msg.LogoutDate)
+	assert.Equal(t, [0x07]byte{}, msg.LogoutTime)
+
+	got, err := msg.GetLogoutTime()
+	require.NoError(t, err)
+	assert.True(t, got.IsZero())
+}
+
+func TestMsgGate2LsAccLogout_GetLogoutTimeInvalid(t *testing.T) {
+	var msg MsgGate2LsAccLogout
+	copy(msg.LogoutDate[:], "20241301")
+	copy(msg.LogoutTime[:], "120000")
+	_, err := msg.GetLogoutTime()
+	assert.ErrorIs(t, err, ErrInvalidLogoutTime)
+
+	msg = MsgGate2LsAccLogout{}
+	copy(msg.LogoutDate[:], "20240101")
+	_, err = msg.GetLogoutTime()
+	assert.ErrorIs(t, err, ErrInvalidLogoutTime)
+}
@@ -1,5 +1,17 @@
 package protocol
 
+import "strconv"
+
+// PcId identifies a player session on a server link. On the wire it is the
+// plain uint32 PcId field of every message header; the named type lets
+// routing code key maps by it without mixing it up with other uint32 IDs.
+type PcId uint32
+
+// String returns the ID in decimal, prefixed with "pc", for example "pc42".
+func (id PcId) String() string {
+	return "pc" + strconv.FormatUint(uint64(id), 10)
+}
+
 type MsgHeadNoProtocol struct {
 	Size uint32
 	PcId uint32
@@ -7,6 +19,17 @@ type MsgHeadNoProtocol struct {
 	Cmd  byte
 }
 
+// GetPcId returns the header's PcId field as a PcId. It is promoted to every
+// message type.
+func (h *MsgHeadNoProtocol) GetPcId() PcId {
+	return PcId(h.PcId)
+}
+
+// SetPcId stores id in the header's PcId field.
+func (h *MsgHeadNoProtocol) SetPcId(id PcId) {
+	h.PcId = uint32(id)
+}
+
 type MsgHead struct {
 	MsgHeadNoProtocol
 	Protocol uint16
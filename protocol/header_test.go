@@ -0,0 +1,35 @@
+package protocol
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPcId_String(t *testing.T) {
+	assert.Equal(t, "pc42", PcId(42).String())
+	assert.Equal(t, "pc4294967295", fmt.Sprint(PcId(0xFFFFFFFF)))
+}
+
+func TestGetPcId_PromotedToMessages(t *testing.T) {
+	say := NewMsgC2SSay(7, General, "PlayerOne", "hello")
+	assert.Equal(t, PcId(7), say.GetPcId())
+
+	login := NewMsgC2SGateLogin(9, "account", "password")
+	assert.Equal(t, PcId(9), login.GetPcId())
+
+	sessions := map[PcId]string{login.GetPcId(): "account"}
+	assert.Equal(t, "account", sessions[9])
+}
+
+func TestSetPcId_WireFormatUnchanged(t *testing.T) {
+	msg := NewMsgC2SSelectServer(1)
+	msg.SetPcId(0x01020304)
+	assert.Equal(t, uint32(0x01020304), msg.PcId)
+
+	data, err := GetBytesFromMsg(&msg)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x03, 0x02, 0x01}, data[4:8])
+}
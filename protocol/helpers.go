@@ -4,6 +4,9 @@ package protocol
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
 )
 
 // GetBytesFromMsg serializes v into a byte slice using little-endian binary encoding.
@@ -21,3 +24,133 @@ func GetBytesFromMsg(v any) ([]byte, error) {
 func ReadMsgFromBytes(data []byte, v any) error {
 	return binary.Read(bytes.NewReader(data), binary.LittleEndian, v)
 }
+
+// DecodeVariable decodes a message made of a fixed-size head followed by a
+// count-prefixed run of fixed-size elements, a shape binary.Read cannot decode
+// into a single struct because the run has no fixed length. head must be a
+// pointer accepted by ReadMsgFromBytes; it is decoded first, then count is
+// called to obtain the number of elements (typically by reading a field of
+// the freshly decoded head) and that many values of E are decoded from the
+// bytes that follow. Bytes after the last element are ignored.
+//
+// DecodeVariable returns io.ErrUnexpectedEOF if data is too short for the
+// head or for count elements, and never allocates more elements than the
+// remaining data can hold.
+func DecodeVariable[E any](data []byte, head any, count func() int) ([]E, error) {
+	headSize := binary.Size(head)
+	if headSize < 0 {
+		return nil, fmt.Errorf("protocol: DecodeVariable: invalid head type %T", head)
+	}
+
+	if len(data) < headSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	if err := ReadMsgFromBytes(data[:headSize], head); err != nil {
+		return nil, err
+	}
+
+	var zero E
+	elemSize := binary.Size(zero)
+	if elemSize <= 0 {
+		return nil, fmt.Errorf("protocol: DecodeVariable: invalid element type %T", zero)
+	}
+
+	n := count()
+	rest := data[headSize:]
+	if n < 0 || n > len(rest)/elemSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	elems := make([]E, n)
+	if err := ReadMsgFromBytes(rest[:n*elemSize], elems); err != nil {
+		return nil, err
+	}
+
+	return elems, nil
+}
+
+// DecodePrefix decodes as many leading fields of v as data holds, as a
+// research aid for messages whose layout is only partly known. v must be a
+// non-nil pointer to a fixed-size type. Fields are decoded little-endian in
+// declaration order, descending into nested and embedded structs, and
+// decoding stops at the first field (or array) that does not fit entirely in
+// the remaining bytes; that field and everything after it are left untouched.
+// Bytes after the last decoded field are ignored. DecodePrefix returns the
+// number of bytes consumed; unlike ReadMsgFromBytes, short or long input is
+// never an error.
+func DecodePrefix(data []byte, v any) (int, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return 0, fmt.Errorf("protocol: DecodePrefix: %T is not a non-nil pointer", v)
+	}
+
+	if binary.Size(v) < 0 {
+		return 0, fmt.Errorf("protocol: DecodePrefix: %T has no fixed size", v)
+	}
+
+	n, _ := decodePrefix(data, rv.Elem())
+	return n, nil
+}
+
+// decodePrefix decodes the leading fields of the addressable value v that fit
+// in data. It returns the bytes consumed and whether v was decoded completely.
+func decodePrefix(data []byte, v reflect.Value) (int, bool) {
+	size := int(v.Type().Size())
+	if s := binary.Size(v.Addr().Interface()); s >= 0 {
+		size = s
+	}
+
+	if size <= len(data) {
+		_, _ = binary.Decode(data[:size], binary.LittleEndian, v.Addr().Interface())
+		return size, true
+	}
+
+	if v.Kind() != reflect.Struct {
+		return 0, false
+	}
+
+	n := 0
+	for i := range v.NumField() {
+		field := v.Field(i)
+		if !v.Type().Field(i).IsExported() {
+			// Blank padding fields are skipped like binary.Read does.
+			skip := binary.Size(reflect.Zero(field.Type()).Interface())
+			if skip > len(data)-n {
+				return n, false
+			}
+
+			n += skip
+			continue
+		}
+
+		m, complete := decodePrefix(data[n:], field)
+		n += m
+		if !complete {
+			return n, false
+		}
+	}
+
+	return n, true
+}
+
+// RestoreSizePrefix returns a new frame made of a little-endian uint32 Size
+// followed by data, for captures that recorded a frame without its leading
+// Size field. The synthesized Size is len(data)+4, the length of the returned
+// frame, which is what the sender stamped if the capture is complete. data is
+// not modified.
+func RestoreSizePrefix(data []byte) []byte {
+	frame := make([]byte, 4+len(data))
+	binary.LittleEndian.PutUint32(frame, uint32(len(frame)))
+	copy(frame[4:], data)
+	return frame
+}
+
+// DecodeHeadless decodes data into v like ReadMsgFromBytes, except that data
+// is a frame with its leading 4-byte Size field stripped: it starts with the
+// header's PcId, followed by Ctrl, Cmd and, for messages with a Protocol
+// field, Protocol, so every offset is 4 less than in the full frame. The Size
+// field of v is set to len(data)+4, as RestoreSizePrefix synthesizes it.
+func DecodeHeadless(data []byte, v any) error {
+	return ReadMsgFromBytes(RestoreSizePrefix(data), v)
+}
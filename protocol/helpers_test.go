@@ -1,7 +1,10 @@
 package protocol
 
 import (
+	"bytes"
 	"encoding/binary"
+	"errors"
+	"io"
 	"reflect"
 	"testing"
 )
@@ -74,3 +77,201 @@ func TestReadMsgFromBytes_C2SSay_TooShortData(t *testing.T) {
 		t.Error("ReadMsgFromBytes: expected error when data is too short, got nil")
 	}
 }
+
+// serverListHead and serverEntry model a list-bearing message: a fixed head
+// whose Count field gives the number of entries that follow it.
+type serverListHead struct {
+	MsgHead
+	Count uint32
+}
+
+type serverEntry struct {
+	ID    byte
+	Name  [0x10]byte
+	Users uint16
+}
+
+func encodeServerList(t *testing.T, count uint32, entries []serverEntry) []byte {
+	t.Helper()
+	head := serverListHead{MsgHead: MsgHead{Protocol: 0x1234}, Count: count}
+	data, err := GetBytesFromMsg(&head)
+	if err != nil {
+		t.Fatalf("GetBytesFromMsg head: %v", err)
+	}
+	body, err := GetBytesFromMsg(entries)
+	if err != nil {
+		t.Fatalf("GetBytesFromMsg entries: %v", err)
+	}
+	return append(data, body...)
+}
+
+func TestDecodeVariable_RoundTrip(t *testing.T) {
+	entries := []serverEntry{{ID: 1, Users: 10}, {ID: 2, Users: 20}, {ID: 3, Users: 30}}
+	copy(entries[0].Name[:], "Temoz")
+	data := encodeServerList(t, 3, entries)
+
+	var head serverListHead
+	got, err := DecodeVariable[serverEntry](data, &head, func() int { return int(head.Count) })
+	if err != nil {
+		t.Fatalf("DecodeVariable: unexpected error: %v", err)
+	}
+	if head.Protocol != 0x1234 || head.Count != 3 {
+		t.Errorf("DecodeVariable: head = %+v", head)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("DecodeVariable: got %+v, want %+v", got, entries)
+	}
+}
+
+func TestDecodeVariable_Empty(t *testing.T) {
+	data := encodeServerList(t, 0, nil)
+	var head serverListHead
+	got, err := DecodeVariable[serverEntry](data, &head, func() int { return int(head.Count) })
+	if err != nil {
+		t.Fatalf("DecodeVariable: unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("DecodeVariable: got %d entries, want 0", len(got))
+	}
+}
+
+func TestDecodeVariable_CountExceedsData(t *testing.T) {
+	// The head claims far more entries than the frame holds.
+	data := encodeServerList(t, 1<<30, []serverEntry{{ID: 1}})
+	var head serverListHead
+	_, err := DecodeVariable[serverEntry](data, &head, func() int { return int(head.Count) })
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("DecodeVariable: got error %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestDecodeVariable_ShortHead(t *testing.T) {
+	var head serverListHead
+	_, err := DecodeVariable[serverEntry]([]byte{1, 2, 3}, &head, func() int { return 0 })
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("DecodeVariable: got error %v, want io.ErrUnexpectedEOF", err)
+	}
+}
+
+func TestDecodePrefix_PartialFrame(t *testing.T) {
+	msg := NewMsgC2SSay(12345, General, "PlayerOne", "Hello world")
+	data, err := GetBytesFromMsg(&msg)
+	if err != nil {
+		t.Fatalf("GetBytesFromMsg: unexpected error: %v", err)
+	}
+
+	// Header, SayType and the first 10 bytes of SayPC: SayPC does not fit.
+	headSize := binary.Size(MsgHead{})
+	var decoded MsgC2SSay
+	n, err := DecodePrefix(data[:headSize+1+10], &decoded)
+	if err != nil {
+		t.Fatalf("DecodePrefix: unexpected error: %v", err)
+	}
+
+	if n != headSize+1 {
+		t.Errorf("consumed = %d, want %d", n, headSize+1)
+	}
+
+	if decoded.MsgHead != msg.MsgHead || decoded.SayType != msg.SayType {
+		t.Errorf("decoded head = %+v/%v, want %+v/%v", decoded.MsgHead, decoded.SayType, msg.MsgHead, msg.SayType)
+	}
+
+	if decoded.SayPC != [0x15]byte{} || decoded.Words != [0x40]byte{} {
+		t.Error("fields past the prefix must be left untouched")
+	}
+}
+
+func TestDecodePrefix_SplitsNestedHeader(t *testing.T) {
+	msg := NewMsgC2SSay(12345, General, "PlayerOne", "Hello world")
+	data, err := GetBytesFromMsg(&msg)
+	if err != nil {
+		t.Fatalf("GetBytesFromMsg: unexpected error: %v", err)
+	}
+
+	// Size, PcId and Ctrl of the embedded header fit, Cmd does not.
+	var decoded MsgC2SSay
+	n, err := DecodePrefix(data[:9], &decoded)
+	if err != nil {
+		t.Fatalf("DecodePrefix: unexpected error: %v", err)
+	}
+
+	if n != 9 {
+		t.Errorf("consumed = %d, want 9", n)
+	}
+
+	if decoded.Size != msg.Size || decoded.PcId != msg.PcId || decoded.Ctrl != msg.Ctrl || decoded.Cmd != 0 {
+		t.Errorf("decoded header = %+v, want Size, PcId and Ctrl only", decoded.MsgHead)
+	}
+}
+
+func TestDecodePrefix_TrailingBytes(t *testing.T) {
+	msg := NewMsgC2SSay(12345, General, "PlayerOne", "Hello world")
+	data, err := GetBytesFromMsg(&msg)
+	if err != nil {
+		t.Fatalf("GetBytesFromMsg: unexpected error: %v", err)
+	}
+
+	var decoded MsgC2SSay
+	n, err := DecodePrefix(append(data, 0xAA, 0xBB), &decoded)
+	if err != nil {
+		t.Fatalf("DecodePrefix: unexpected error: %v", err)
+	}
+
+	if n != len(data) {
+		t.Errorf("consumed = %d, want %d", n, len(data))
+	}
+
+	if !reflect.DeepEqual(decoded, msg) {
+		t.Errorf("decoded = %+v, want %+v", decoded, msg)
+	}
+}
+
+func TestDecodePrefix_InvalidTarget(t *testing.T) {
+	var msg MsgC2SSay
+	if _, err := DecodePrefix(nil, msg); err == nil {
+		t.Error("non-pointer target: expected error")
+	}
+
+	var variable struct{ Name string }
+	if _, err := DecodePrefix(nil, &variable); err == nil {
+		t.Error("variable-size target: expected error")
+	}
+}
+
+func TestRestoreSizePrefix(t *testing.T) {
+	msg := NewMsgC2SSay(7, General, "PlayerOne", "hello")
+	data, err := GetBytesFromMsg(&msg)
+	if err != nil {
+		t.Fatalf("GetBytesFromMsg: unexpected error: %v", err)
+	}
+
+	headless := data[4:]
+	if got := RestoreSizePrefix(headless); !bytes.Equal(got, data) {
+		t.Errorf("RestoreSizePrefix = %x, want %x", got, data)
+	}
+
+	if got := RestoreSizePrefix(nil); !bytes.Equal(got, []byte{0x04, 0x00, 0x00, 0x00}) {
+		t.Errorf("RestoreSizePrefix(nil) = %x, want 04000000", got)
+	}
+}
+
+func TestDecodeHeadless(t *testing.T) {
+	msg := NewMsgC2SWorldLogin(42, "Hero")
+	data, err := GetBytesFromMsg(&msg)
+	if err != nil {
+		t.Fatalf("GetBytesFromMsg: unexpected error: %v", err)
+	}
+
+	var decoded MsgC2SWorldLogin
+	if err := DecodeHeadless(data[4:], &decoded); err != nil {
+		t.Fatalf("DecodeHeadless: unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, msg) {
+		t.Errorf("decoded = %+v, want %+v", decoded, msg)
+	}
+
+	if err := DecodeHeadless(data[4:len(data)-1], &decoded); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated capture: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+}
@@ -2,10 +2,17 @@ package protocol
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
 
 	"github.com/cyberinferno/go-utils/utils"
 )
 
+// ErrPcIdMismatch is returned by EffectivePcId when the header PcId and the
+// body PcId of a message differ.
+var ErrPcIdMismatch = errors.New("protocol: header and body PcId differ")
+
 type MsgC2SLogin struct {
 	MsgHeadNoProtocol
 	Username [0x15]byte
@@ -20,6 +27,18 @@ func (msg *MsgC2SLogin) SetSize() {
 	msg.Size = msg.GetSize()
 }
 
+// GetUsername returns the username trimmed of null padding. A username that
+// fills the whole field without a null terminator is returned in full.
+func (msg *MsgC2SLogin) GetUsername() string {
+	return utils.ReadStringFromBytes(msg.Username[:])
+}
+
+// GetPassword returns the password trimmed of null padding. A password that
+// fills the whole field without a null terminator is returned in full.
+func (msg *MsgC2SLogin) GetPassword() string {
+	return utils.ReadStringFromBytes(msg.Password[:])
+}
+
 func NewMsgC2SLogin(username, password string) MsgC2SLogin {
 	msg := MsgC2SLogin{
 		MsgHeadNoProtocol: MsgHeadNoProtocol{Ctrl: 0x01, Cmd: 0xE0},
@@ -45,6 +64,26 @@ func (msg *MsgC2SGateLogin) SetSize() {
 	msg.Size = msg.GetSize()
 }
 
+// GetAccount returns the account name trimmed of null padding. An account
+// that fills the whole field without a null terminator is returned in full.
+func (msg *MsgC2SGateLogin) GetAccount() string {
+	return utils.ReadStringFromBytes(msg.Account[:])
+}
+
+// GetPassword returns the password trimmed of null padding. A password that
+// fills the whole field without a null terminator is returned in full.
+func (msg *MsgC2SGateLogin) GetPassword() string {
+	return utils.ReadStringFromBytes(msg.Password[:])
+}
+
+// EffectivePcId returns the session ID the message carries. The ID appears
+// twice, in the header and in the body's PcId field, and NewMsgC2SGateLogin
+// sets both; a decoded packet whose copies disagree was crafted or corrupted,
+// and EffectivePcId reports it with an error wrapping ErrPcIdMismatch.
+func (msg *MsgC2SGateLogin) EffectivePcId() (uint32, error) {
+	return effectivePcId(msg.MsgHeadNoProtocol.PcId, msg.PcId)
+}
+
 func NewMsgC2SGateLogin(pcId uint32, account string, password string) *MsgC2SGateLogin {
 	msg := MsgC2SGateLogin{
 		MsgHeadNoProtocol: MsgHeadNoProtocol{Ctrl: 0x01, Cmd: 0xE2, PcId: pcId},
@@ -101,6 +140,12 @@ func (msg *MsgLs2GateLogin) SetSize() {
 	msg.Size = msg.GetSize()
 }
 
+// GetAccount returns the account name trimmed of null padding. An account
+// that fills the whole field without a null terminator is returned in full.
+func (msg *MsgLs2GateLogin) GetAccount() string {
+	return utils.ReadStringFromBytes(msg.Account[:])
+}
+
 func NewMsgLs2GateLogin(account string, pcId uint32) MsgLs2GateLogin {
 	msg := MsgLs2GateLogin{
 		MsgHeadNoProtocol: MsgHeadNoProtocol{Ctrl: 0x01, Cmd: 0xE1, PcId: pcId},
@@ -125,6 +170,47 @@ func (msg *MsgS2CGateInfo) SetSize() {
 	msg.Size = msg.GetSize()
 }
 
+// GetIP parses ZaIP back into a net.IP. It returns nil if the field does not
+// hold a valid dotted address.
+func (msg *MsgS2CGateInfo) GetIP() net.IP {
+	return parseIPField(msg.ZaIP[:])
+}
+
+// SetIP writes ip into ZaIP as a dotted IPv4 string. It returns
+// ErrInvalidIPv4, leaving ZaIP unchanged, if ip is not an IPv4 address.
+func (msg *MsgS2CGateInfo) SetIP(ip net.IP) error {
+	field, err := ipField(ip)
+	if err != nil {
+		return err
+	}
+
+	msg.ZaIP = field
+	return nil
+}
+
+// SetAddr sets ZaIP and ZaPort from addr, typically the zone agent listener's
+// Addr(). It returns ErrInvalidIPv4 if addr does not carry an IPv4 address and
+// leaves msg unchanged on any error.
+func (msg *MsgS2CGateInfo) SetAddr(addr net.Addr) error {
+	ip, port, err := addrIPPort(addr)
+	if err != nil {
+		return err
+	}
+
+	if err := msg.SetIP(ip); err != nil {
+		return err
+	}
+
+	msg.ZaPort = port
+	return nil
+}
+
+// EffectivePcId returns the session ID the message carries, checking that
+// the header and body copies agree as MsgC2SGateLogin.EffectivePcId does.
+func (msg *MsgS2CGateInfo) EffectivePcId() (uint32, error) {
+	return effectivePcId(msg.MsgHeadNoProtocol.PcId, msg.PcId)
+}
+
 func NewMsgS2CGateInfo(pcId uint32, zaIP string, zaPort uint32) MsgS2CGateInfo {
 	msg := MsgS2CGateInfo{
 		MsgHeadNoProtocol: MsgHeadNoProtocol{Ctrl: 0x01, Cmd: 0xE2, PcId: pcId},
@@ -292,6 +378,49 @@ func (msg *MsgS2CWorldLogin) SetSize() {
 	msg.Size = msg.GetSize()
 }
 
+// CharacterStats holds the gameplay stats carried by MsgS2CWorldLogin, free of
+// any wire-format concerns.
+type CharacterStats struct {
+	Level           uint16
+	Exp             uint32
+	Woonz           uint32
+	HPPot           uint32
+	MPPot           uint32
+	Lore            uint32
+	RemainingPoints uint16
+	Strength        uint16
+	Intelligence    uint16
+}
+
+// Stats returns the character stats carried by the message.
+func (msg *MsgS2CWorldLogin) Stats() CharacterStats {
+	return CharacterStats{
+		Level:           msg.Level,
+		Exp:             msg.Exp,
+		Woonz:           msg.Woonz,
+		HPPot:           msg.HPPot,
+		MPPot:           msg.MPPot,
+		Lore:            msg.Lore,
+		RemainingPoints: msg.RemainingPoints,
+		Strength:        msg.Strength,
+		Intelligence:    msg.Intelligence,
+	}
+}
+
+// ApplyStats writes stats back into the message. All other fields, including
+// the header, are left unchanged.
+func (msg *MsgS2CWorldLogin) ApplyStats(stats CharacterStats) {
+	msg.Level = stats.Level
+	msg.Exp = stats.Exp
+	msg.Woonz = stats.Woonz
+	msg.HPPot = stats.HPPot
+	msg.MPPot = stats.MPPot
+	msg.Lore = stats.Lore
+	msg.RemainingPoints = stats.RemainingPoints
+	msg.Strength = stats.Strength
+	msg.Intelligence = stats.Intelligence
+}
+
 type MsgS2CCharacterLogin struct {
 	MsgHead
 	CharacterName [0x15]byte
@@ -317,3 +446,13 @@ func NewMsgS2CCharacterLogin(pcId uint32, characterName string, unknown uint32,
 	msg.SetSize()
 	return msg
 }
+
+// effectivePcId returns header if body matches it and ErrPcIdMismatch
+// otherwise.
+func effectivePcId(header, body uint32) (uint32, error) {
+	if header != body {
+		return 0, fmt.Errorf("%w: header %d, body %d", ErrPcIdMismatch, header, body)
+	}
+
+	return header, nil
+}
@@ -0,0 +1,110 @@
+package protocol
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgS2CWorldLogin_Stats(t *testing.T) {
+	msg := MsgS2CWorldLogin{
+		Level:           42,
+		Exp:             1000,
+		Woonz:           2000,
+		HPPot:           3,
+		MPPot:           4,
+		Lore:            5,
+		RemainingPoints: 6,
+		Strength:        7,
+		Intelligence:    8,
+	}
+
+	assert.Equal(t, CharacterStats{
+		Level:           42,
+		Exp:             1000,
+		Woonz:           2000,
+		HPPot:           3,
+		MPPot:           4,
+		Lore:            5,
+		RemainingPoints: 6,
+		Strength:        7,
+		Intelligence:    8,
+	}, msg.Stats())
+}
+
+func TestMsgS2CWorldLogin_ApplyStats(t *testing.T) {
+	var msg MsgS2CWorldLogin
+	msg.Protocol = S2CWorldLogin
+	msg.Class = 2
+	msg.MapNum = 9
+	stats := CharacterStats{Level: 10, Exp: 11, Woonz: 12, HPPot: 13, MPPot: 14, Lore: 15, RemainingPoints: 16, Strength: 17, Intelligence: 18}
+
+	msg.ApplyStats(stats)
+	assert.Equal(t, stats, msg.Stats())
+	assert.Equal(t, S2CWorldLogin, msg.Protocol, "header must be untouched")
+	assert.Equal(t, byte(2), msg.Class)
+	assert.Equal(t, uint32(9), msg.MapNum)
+}
+
+func TestMsgC2SLogin_Credentials(t *testing.T) {
+	msg := NewMsgC2SLogin("user", "secret")
+	assert.Equal(t, "user", msg.GetUsername())
+	assert.Equal(t, "secret", msg.GetPassword())
+}
+
+func TestMsgC2SLogin_FullLengthCredentials(t *testing.T) {
+	var msg MsgC2SLogin
+	full := strings.Repeat("a", len(msg.Username))
+	copy(msg.Username[:], full)
+	copy(msg.Password[:], full)
+	assert.Equal(t, full, msg.GetUsername())
+	assert.Equal(t, full, msg.GetPassword())
+}
+
+func TestMsgC2SGateLogin_Credentials(t *testing.T) {
+	msg := NewMsgC2SGateLogin(1, "account", "pw")
+	assert.Equal(t, "account", msg.GetAccount())
+	assert.Equal(t, "pw", msg.GetPassword())
+
+	var empty MsgC2SGateLogin
+	assert.Equal(t, "", empty.GetAccount())
+	assert.Equal(t, "", empty.GetPassword())
+}
+
+func TestMsgLs2GateLogin_Account(t *testing.T) {
+	msg := NewMsgLs2GateLogin("account", 1)
+	assert.Equal(t, "account", msg.GetAccount())
+
+	full := strings.Repeat("z", len(msg.Account))
+	copy(msg.Account[:], full)
+	assert.Equal(t, full, msg.GetAccount())
+}
+
+func TestMsgC2SGateLogin_EffectivePcId(t *testing.T) {
+	msg := NewMsgC2SGateLogin(42, "account", "password")
+	id, err := msg.EffectivePcId()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(42), id)
+
+	data, err := GetBytesFromMsg(msg)
+	require.NoError(t, err)
+	data[headNoProtocolSize] = 43 // body PcId follows the header
+	var decoded MsgC2SGateLogin
+	require.NoError(t, ReadMsgFromBytes(data, &decoded))
+	id, err = decoded.EffectivePcId()
+	assert.ErrorIs(t, err, ErrPcIdMismatch)
+	assert.Zero(t, id)
+}
+
+func TestMsgS2CGateInfo_EffectivePcId(t *testing.T) {
+	msg := NewMsgS2CGateInfo(7, "10.0.0.1", 9000)
+	id, err := msg.EffectivePcId()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(7), id)
+
+	msg.SetPcId(8)
+	_, err = msg.EffectivePcId()
+	assert.ErrorIs(t, err, ErrPcIdMismatch)
+}
@@ -0,0 +1,286 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Message is implemented by every protocol message. GetSize reports the encoded
+// size of the message and SetSize stamps that size into the message header.
+// All message types implement it on their pointer receiver.
+type Message interface {
+	GetSize() uint32
+	SetSize()
+}
+
+// Sentinel errors.
+var (
+	// ErrInvalidFrameSize is returned when a frame's Size field is smaller than
+	// the fixed message header and so cannot describe a valid message.
+	ErrInvalidFrameSize = errors.New("protocol: invalid frame size")
+
+	// ErrUnknownMessage is returned when a frame's routing key (Ctrl, Cmd and,
+	// where applicable, Protocol) does not match any registered message.
+	ErrUnknownMessage = errors.New("protocol: unknown message")
+
+	// ErrTooLarge is returned when a frame's Size field exceeds
+	// MaxMessageSize.
+	ErrTooLarge = errors.New("protocol: frame exceeds MaxMessageSize")
+
+	// ErrAmbiguousMessage is returned by ReadMessage when a frame's routing
+	// key names different messages in different directions.
+	ErrAmbiguousMessage = errors.New("protocol: message direction is ambiguous")
+)
+
+// MaxMessageSize is the largest frame ReadFrame accepts, in bytes. The frame
+// buffer is allocated from the peer-supplied Size field, so the cap keeps a
+// single malicious frame from forcing a huge allocation. The default of 64 KiB
+// is well above the largest registered message; raise it before reading from
+// trusted peers that need more. It must not be changed while reads are in
+// progress.
+var MaxMessageSize uint32 = 1 << 16
+
+// headNoProtocolSize is the encoded size of MsgHeadNoProtocol, the smallest
+// header any message carries.
+var headNoProtocolSize = binary.Size(MsgHeadNoProtocol{})
+
+// ReadFrame reads one complete message frame from r. Every message starts with
+// a little-endian uint32 Size covering the whole frame, header included, so the
+// returned slice contains exactly Size bytes.
+//
+// ReadFrame returns io.EOF if r is exhausted before any byte of the frame is
+// read, io.ErrUnexpectedEOF if the frame is truncated, ErrInvalidFrameSize if
+// the Size field is smaller than the message header, and ErrTooLarge, without
+// reading the rest of the frame, if it is larger than MaxMessageSize.
+func ReadFrame(r io.Reader) ([]byte, error) {
+	var sizeBuf [4]byte
+	if _, err := io.ReadFull(r, sizeBuf[:]); err != nil {
+		return nil, err
+	}
+
+	size := binary.LittleEndian.Uint32(sizeBuf[:])
+	if size < uint32(headNoProtocolSize) {
+		return nil, ErrInvalidFrameSize
+	}
+
+	if size > MaxMessageSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooLarge, size)
+	}
+
+	frame := make([]byte, size)
+	copy(frame, sizeBuf[:])
+	if _, err := io.ReadFull(r, frame[4:]); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+// WriteMessage encodes m and writes it to conn. SetSize is called before
+// encoding so the Size field always matches the encoded length. If timeout is
+// positive a write deadline is set for the duration of the call and cleared
+// afterwards; a zero timeout leaves the connection's deadline untouched. A
+// write that makes no progress without reporting an error returns
+// io.ErrShortWrite.
+func WriteMessage(conn net.Conn, m Message, timeout time.Duration) error {
+	m.SetSize()
+	data, err := GetBytesFromMsg(m)
+	if err != nil {
+		return err
+	}
+
+	if timeout > 0 {
+		if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+			return err
+		}
+
+		defer func() { _ = conn.SetWriteDeadline(time.Time{}) }()
+	}
+
+	for len(data) > 0 {
+		n, err := conn.Write(data)
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+
+		data = data[n:]
+	}
+
+	return nil
+}
+
+// BatchSize returns the total encoded size of msgs, the sum of each
+// message's GetSize. Use it to check a batch against a frame or MTU budget
+// before encoding it.
+func BatchSize(msgs ...Message) int {
+	total := 0
+	for _, m := range msgs {
+		total += int(m.GetSize())
+	}
+
+	return total
+}
+
+// EncodeBatch encodes msgs back to back into a single buffer, calling SetSize
+// on each first exactly as WriteMessage does. The buffer is allocated once
+// with BatchSize capacity, so encoding never reallocates.
+func EncodeBatch(msgs ...Message) ([]byte, error) {
+	buf := make([]byte, 0, BatchSize(msgs...))
+	for _, m := range msgs {
+		m.SetSize()
+		var err error
+		if buf, err = binary.Append(buf, binary.LittleEndian, m); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf, nil
+}
+
+// ReadMessage reads one frame from conn and decodes it into the registered
+// message type, trying every direction. The result is a Message; it is typed
+// any so callers can switch on it directly. When the routing key names
+// different messages in different directions, as Ctrl 0x01 / Cmd 0xE0 does,
+// the one whose encoded size equals the frame length is chosen; if that does
+// not settle it ReadMessage returns ErrAmbiguousMessage, and ReadMessageFrom,
+// which takes the direction of the link, should be used instead. If timeout
+// is positive a read deadline is set for the duration of the call and
+// cleared afterwards.
+func ReadMessage(conn net.Conn, timeout time.Duration) (any, error) {
+	frame, err := readFrameWithDeadline(conn, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*registered
+	for _, dir := range [...]Direction{DirectionC2S, DirectionS2C, DirectionS2S} {
+		if reg, ok := lookupFrame(dir, frame); ok {
+			candidates = append(candidates, reg)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: ctrl=0x%02X cmd=0x%02X", ErrUnknownMessage, frame[8], frame[9])
+	}
+
+	reg, err := chooseRegistration(candidates, uint32(len(frame)))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeInto(reg, frame)
+}
+
+// chooseRegistration picks the message a frame of size bytes decodes to from
+// the non-empty regs found for it in different directions, preferring the
+// message of that encoded size when they disagree.
+func chooseRegistration(regs []*registered, size uint32) (*registered, error) {
+	if sameMessage(regs) {
+		return regs[0], nil
+	}
+
+	var sized []*registered
+	for _, reg := range regs {
+		if reg.new().GetSize() == size {
+			sized = append(sized, reg)
+		}
+	}
+
+	if len(sized) == 0 || !sameMessage(sized) {
+		return nil, fmt.Errorf("%w: %d candidates for a %d-byte frame", ErrAmbiguousMessage, len(regs), size)
+	}
+
+	return sized[0], nil
+}
+
+// sameMessage reports whether every registration in regs is for the same
+// message type.
+func sameMessage(regs []*registered) bool {
+	for _, reg := range regs[1:] {
+		if reg.name != regs[0].name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReadMessageFrom reads one frame from conn and decodes it into the
+// registered message type for the given direction, as Decode does. If
+// timeout is positive a read deadline is set for the duration of the call and
+// cleared afterwards.
+func ReadMessageFrom(conn net.Conn, dir Direction, timeout time.Duration) (Message, error) {
+	frame, err := readFrameWithDeadline(conn, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode(dir, frame)
+}
+
+// readFrameWithDeadline is ReadFrame with the optional read deadline of
+// ReadMessage and ReadMessageFrom.
+func readFrameWithDeadline(conn net.Conn, timeout time.Duration) ([]byte, error) {
+	if timeout > 0 {
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, err
+		}
+
+		defer func() { _ = conn.SetReadDeadline(time.Time{}) }()
+	}
+
+	return ReadFrame(conn)
+}
+
+// Decode decodes a complete frame into a new instance of the message type
+// registered for its routing key in the given direction. Messages without a
+// Protocol field are matched on (Ctrl, Cmd) first; otherwise the Protocol field
+// at offset 10 is included in the lookup.
+func Decode(dir Direction, frame []byte) (Message, error) {
+	if len(frame) < headNoProtocolSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	reg, ok := lookupFrame(dir, frame)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s ctrl=0x%02X cmd=0x%02X", ErrUnknownMessage, dir, frame[8], frame[9])
+	}
+
+	return decodeInto(reg, frame)
+}
+
+// lookupFrame finds the registration for a frame of at least
+// headNoProtocolSize bytes sent in direction dir: by (Ctrl, Cmd) first, then
+// with the Protocol field at offset 10.
+func lookupFrame(dir Direction, frame []byte) (*registered, bool) {
+	ctrl, cmd := frame[8], frame[9]
+	reg, ok := registryIndex[RoutingKey{Direction: dir, Ctrl: ctrl, Cmd: cmd}]
+	if !ok && len(frame) >= headNoProtocolSize+2 {
+		protocol := binary.LittleEndian.Uint16(frame[headNoProtocolSize:])
+		reg, ok = registryIndex[RoutingKey{Direction: dir, Ctrl: ctrl, Cmd: cmd, HasProtocol: true, Protocol: protocol}]
+	}
+
+	return reg, ok
+}
+
+// decodeInto decodes frame into a new instance of reg's message type.
+func decodeInto(reg *registered, frame []byte) (Message, error) {
+	m := reg.new()
+	if err := ReadMsgFromBytes(frame, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
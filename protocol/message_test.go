@@ -0,0 +1,258 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFrame_SingleFrame(t *testing.T) {
+	msg := NewMsgC2SSay(7, General, "PlayerOne", "hello")
+	data := msg.GetBytes()
+
+	frame, err := ReadFrame(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, data, frame)
+}
+
+func TestReadFrame_BackToBack(t *testing.T) {
+	first := NewMsgC2SLogin("user", "pass")
+	second := NewMsgC2SSelectServer(3)
+	a, err := GetBytesFromMsg(&first)
+	require.NoError(t, err)
+	b, err := GetBytesFromMsg(&second)
+	require.NoError(t, err)
+
+	r := bytes.NewReader(append(a, b...))
+	frame, err := ReadFrame(r)
+	require.NoError(t, err)
+	assert.Equal(t, a, frame)
+	frame, err = ReadFrame(r)
+	require.NoError(t, err)
+	assert.Equal(t, b, frame)
+	_, err = ReadFrame(r)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReadFrame_Truncated(t *testing.T) {
+	msg := NewMsgC2SSay(7, General, "PlayerOne", "hello")
+	data := msg.GetBytes()
+	_, err := ReadFrame(bytes.NewReader(data[:len(data)-1]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, err = ReadFrame(bytes.NewReader(data[:2]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReadFrame_SizeTooSmall(t *testing.T) {
+	_, err := ReadFrame(bytes.NewReader([]byte{0x04, 0x00, 0x00, 0x00}))
+	assert.ErrorIs(t, err, ErrInvalidFrameSize)
+}
+
+func TestDecode_ProtocolMessage(t *testing.T) {
+	msg := NewMsgC2SWorldLogin(42, "Hero")
+	data, err := GetBytesFromMsg(&msg)
+	require.NoError(t, err)
+
+	decoded, err := Decode(DirectionC2S, data)
+	require.NoError(t, err)
+	require.IsType(t, &MsgC2SWorldLogin{}, decoded)
+	assert.Equal(t, msg, *decoded.(*MsgC2SWorldLogin))
+}
+
+func TestDecode_NoProtocolMessage(t *testing.T) {
+	msg := NewMsgC2SGateLogin(9, "account", "password")
+	data, err := GetBytesFromMsg(msg)
+	require.NoError(t, err)
+
+	decoded, err := Decode(DirectionC2S, data)
+	require.NoError(t, err)
+	require.IsType(t, &MsgC2SGateLogin{}, decoded)
+	assert.Equal(t, *msg, *decoded.(*MsgC2SGateLogin))
+}
+
+func TestDecode_DirectionSelectsType(t *testing.T) {
+	// C2SCharacterLogin and S2CCharacterLoginOk share the same routing bytes.
+	login := NewMsgS2CCharacterLogin(5, "Hero", 0, 1)
+	data, err := GetBytesFromMsg(&login)
+	require.NoError(t, err)
+
+	decoded, err := Decode(DirectionS2C, data)
+	require.NoError(t, err)
+	assert.IsType(t, &MsgS2CCharacterLogin{}, decoded)
+}
+
+func TestDecode_UnknownMessage(t *testing.T) {
+	msg := NewMsgC2SLogin("user", "pass")
+	data, err := GetBytesFromMsg(&msg)
+	require.NoError(t, err)
+
+	data[9] = 0x7F
+	_, err = Decode(DirectionC2S, data)
+	assert.ErrorIs(t, err, ErrUnknownMessage)
+}
+
+func TestDecode_ShortFrame(t *testing.T) {
+	_, err := Decode(DirectionC2S, []byte{0x01, 0x02})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestWriteMessage_ReadMessage_RoundTrip(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+
+	msg := NewMsgC2SCharacterLogin(77, "Hero", 562)
+	msg.Size = 0 // WriteMessage must stamp the size itself
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- WriteMessage(client, &msg, time.Second)
+	}()
+
+	decoded, err := ReadMessage(server, time.Second)
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+	require.IsType(t, &MsgC2SCharacterLogin{}, decoded)
+	assert.Equal(t, msg.GetSize(), decoded.(*MsgC2SCharacterLogin).Size)
+	assert.Equal(t, msg, *decoded.(*MsgC2SCharacterLogin))
+}
+
+func TestWriteMessage_DeadlineExceeded(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+
+	// Nobody reads from server, so the write must time out.
+	msg := NewMsgC2SSelectServer(1)
+	err := WriteMessage(client, &msg, 10*time.Millisecond)
+	require.Error(t, err)
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr))
+	assert.True(t, netErr.Timeout())
+}
+
+func TestReadMessage_DeadlineExceeded(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+
+	_, err := ReadMessage(server, 10*time.Millisecond)
+	require.Error(t, err)
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr))
+	assert.True(t, netErr.Timeout())
+}
+
+// stalledConn is a net.Conn whose Write reports no progress and no error.
+type stalledConn struct{ net.Conn }
+
+func (stalledConn) Write([]byte) (int, error) { return 0, nil }
+
+func TestWriteMessage_NoProgress(t *testing.T) {
+	msg := NewMsgC2SSelectServer(1)
+	assert.ErrorIs(t, WriteMessage(stalledConn{}, &msg, 0), io.ErrShortWrite)
+}
+
+func TestReadMessageFrom(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+
+	msg := NewMsgLs2ClSay("hi")
+	go func() { _ = WriteMessage(client, &msg, time.Second) }()
+
+	decoded, err := ReadMessageFrom(server, DirectionS2C, time.Second)
+	require.NoError(t, err)
+	assert.IsType(t, &MsgLs2ClSay{}, decoded)
+}
+
+func TestReadMessage_ChoosesDirectionBySize(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+
+	// Ctrl 0x01 / Cmd 0xE0 is MsgC2SLogin, MsgLs2ClSay and MsgGate2ZsConnect.
+	login := NewMsgC2SLogin("user", "pass")
+	go func() { _ = WriteMessage(client, &login, time.Second) }()
+
+	decoded, err := ReadMessage(server, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, &login, decoded)
+}
+
+func TestReadMessage_Ambiguous(t *testing.T) {
+	login := NewMsgC2SLogin("user", "pass")
+	data, err := GetBytesFromMsg(&login)
+	require.NoError(t, err)
+	data = append(data, 0) // matches none of the candidates' sizes
+	binary.LittleEndian.PutUint32(data, uint32(len(data)))
+
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+	go func() { _, _ = client.Write(data) }()
+
+	_, err = ReadMessage(server, time.Second)
+	assert.ErrorIs(t, err, ErrAmbiguousMessage)
+}
+
+func TestBatchSize(t *testing.T) {
+	login := NewMsgC2SLogin("user", "pass")
+	sel := NewMsgC2SSelectServer(1)
+	assert.Equal(t, 0, BatchSize())
+	assert.Equal(t, int(login.GetSize()+sel.GetSize()), BatchSize(&login, &sel))
+}
+
+func TestEncodeBatch(t *testing.T) {
+	login := NewMsgC2SLogin("user", "pass")
+	sel := NewMsgC2SSelectServer(3)
+	sel.Size = 0 // EncodeBatch must stamp the size itself
+	a, err := GetBytesFromMsg(&login)
+	require.NoError(t, err)
+
+	data, err := EncodeBatch(&login, &sel)
+	require.NoError(t, err)
+	assert.Equal(t, BatchSize(&login, &sel), len(data))
+	assert.Equal(t, BatchSize(&login, &sel), cap(data), "buffer must be allocated once at the exact size")
+
+	b, err := GetBytesFromMsg(&sel)
+	require.NoError(t, err)
+	assert.Equal(t, append(a, b...), data)
+
+	r := bytes.NewReader(data)
+	frame, err := ReadFrame(r)
+	require.NoError(t, err)
+	assert.Equal(t, a, frame)
+	frame, err = ReadFrame(r)
+	require.NoError(t, err)
+	assert.Equal(t, b, frame)
+}
+
+func TestReadFrame_TooLarge(t *testing.T) {
+	// Only the size is sent: ReadFrame must reject it without waiting for or
+	// allocating the body.
+	_, err := ReadFrame(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF}))
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestReadFrame_RaisedMaxMessageSize(t *testing.T) {
+	defer func(old uint32) { MaxMessageSize = old }(MaxMessageSize)
+	msg := NewMsgC2SSay(7, General, "PlayerOne", "hello")
+	data := msg.GetBytes()
+
+	MaxMessageSize = uint32(len(data)) - 1
+	_, err := ReadFrame(bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	MaxMessageSize = uint32(len(data))
+	frame, err := ReadFrame(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, data, frame)
+}
@@ -0,0 +1,96 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Sentinel errors returned by PatchField and FieldOffset.
+var (
+	// ErrUnknownField is returned when the named field does not exist in the
+	// struct layout, or is ambiguous between two embedded structs.
+	ErrUnknownField = errors.New("protocol: unknown field")
+
+	// ErrFieldSize is returned by PatchField when the new value's encoded size
+	// differs from the field's.
+	ErrFieldSize = errors.New("protocol: value size does not match field")
+)
+
+// FieldOffset returns the byte offset and encoded size of the named field in
+// the binary layout of v, a struct or pointer to a struct with a fixed size.
+// Fields of embedded structs, such as PcId in every message, are found
+// through promotion, and fields of nested structs are named with a dotted
+// path such as "Info.Level". Elements of arrays cannot be addressed.
+//
+// A field of the message body shadows a header field of the same name, as Go
+// promotion does: in MsgC2SGateLogin and MsgS2CGateInfo "PcId" is the body
+// field at offset 10. Name the embedded header to reach the header field
+// instead, e.g. "MsgHeadNoProtocol.PcId" (offset 4), or "MsgHead.PcId" in
+// messages that embed MsgHead.
+func FieldOffset(v any, fieldName string) (offset, size int, err error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct || binary.Size(reflect.Zero(t).Interface()) < 0 {
+		return 0, 0, fmt.Errorf("protocol: FieldOffset: %T is not a fixed-size struct", v)
+	}
+
+	for _, name := range strings.Split(fieldName, ".") {
+		if t.Kind() != reflect.Struct {
+			return 0, 0, fmt.Errorf("%w: %q in %s", ErrUnknownField, fieldName, reflect.TypeOf(v))
+		}
+
+		f, ok := t.FieldByName(name)
+		if !ok || !f.IsExported() {
+			return 0, 0, fmt.Errorf("%w: %q in %s", ErrUnknownField, fieldName, reflect.TypeOf(v))
+		}
+
+		for _, i := range f.Index {
+			for j := range i {
+				offset += typeSize(t.Field(j).Type)
+			}
+
+			t = t.Field(i).Type
+		}
+	}
+
+	return offset, typeSize(t), nil
+}
+
+// PatchField overwrites the bytes of one field of the encoded struct in data,
+// leaving every other byte untouched. v describes the layout (a value or
+// pointer of the message type; its contents are not used) and fieldName
+// names the field as for FieldOffset. newValue is encoded little-endian and
+// must have exactly the field's encoded size, otherwise ErrFieldSize is
+// returned; its type need not match the field's. It returns
+// io.ErrUnexpectedEOF if data is too short to hold the field. This lets a
+// proxy rewrite a single field of an in-flight frame without decoding and
+// re-encoding the whole message.
+func PatchField(data []byte, v any, fieldName string, newValue any) error {
+	offset, size, err := FieldOffset(v, fieldName)
+	if err != nil {
+		return err
+	}
+
+	if n := binary.Size(newValue); n != size {
+		return fmt.Errorf("%w: %s is %d bytes, %T is %d", ErrFieldSize, fieldName, size, newValue, n)
+	}
+
+	if len(data) < offset+size {
+		return io.ErrUnexpectedEOF
+	}
+
+	_, err = binary.Encode(data[offset:offset+size], binary.LittleEndian, newValue)
+	return err
+}
+
+// typeSize returns the encoded size of a value of type t.
+func typeSize(t reflect.Type) int {
+	return binary.Size(reflect.Zero(t).Interface())
+}
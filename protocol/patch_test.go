@@ -0,0 +1,112 @@
+package protocol
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldOffset(t *testing.T) {
+	tests := []struct {
+		field        string
+		offset, size int
+	}{
+		{"Size", 0, 4},
+		{"PcId", 4, 4}, // promoted through MsgHead.MsgHeadNoProtocol
+		{"Protocol", 10, 2},
+		{"MsgHead.Protocol", 10, 2},
+		{"MsgHead.MsgHeadNoProtocol.Cmd", 9, 1},
+		{"CharacterName", 12, 0x15},
+		{"Unknown", 33, 4},
+		{"MapNum", 37, 2},
+	}
+
+	for _, tt := range tests {
+		offset, size, err := FieldOffset(MsgS2CCharacterLogin{}, tt.field)
+		require.NoError(t, err, tt.field)
+		assert.Equal(t, tt.offset, offset, tt.field)
+		assert.Equal(t, tt.size, size, tt.field)
+	}
+}
+
+func TestFieldOffset_Errors(t *testing.T) {
+	_, _, err := FieldOffset(&MsgS2CCharacterLogin{}, "Nope")
+	assert.ErrorIs(t, err, ErrUnknownField)
+	_, _, err = FieldOffset(&MsgS2CCharacterLogin{}, "MapNum.X")
+	assert.ErrorIs(t, err, ErrUnknownField)
+	_, _, err = FieldOffset(42, "Size")
+	assert.Error(t, err)
+}
+
+func TestPatchField(t *testing.T) {
+	msg := NewMsgS2CCharacterLogin(5, "Hero", 0, 1)
+	data, err := GetBytesFromMsg(&msg)
+	require.NoError(t, err)
+	original := bytes.Clone(data)
+
+	require.NoError(t, PatchField(data, &msg, "MapNum", uint16(7)))
+
+	var patched MsgS2CCharacterLogin
+	require.NoError(t, ReadMsgFromBytes(data, &patched))
+	want := msg
+	want.MapNum = 7
+	assert.Equal(t, want, patched)
+	assert.Equal(t, original[:37], data[:37], "bytes before the field must be untouched")
+}
+
+func TestPatchField_PromotedHeaderField(t *testing.T) {
+	msg := NewMsgC2SSay(7, General, "PlayerOne", "hello")
+	data := msg.GetBytes()
+	require.NoError(t, PatchField(data, msg, "PcId", uint32(99)))
+
+	var patched MsgC2SSay
+	require.NoError(t, ReadMsgFromBytes(data, &patched))
+	assert.Equal(t, uint32(99), patched.PcId)
+}
+
+func TestFieldOffset_ShadowedHeaderField(t *testing.T) {
+	for _, v := range []any{MsgC2SGateLogin{}, MsgS2CGateInfo{}} {
+		offset, size, err := FieldOffset(v, "PcId")
+		require.NoError(t, err)
+		assert.Equal(t, 10, offset, "%T: the body PcId shadows the header's", v)
+		assert.Equal(t, 4, size)
+
+		offset, size, err = FieldOffset(v, "MsgHeadNoProtocol.PcId")
+		require.NoError(t, err)
+		assert.Equal(t, 4, offset, "%T: the qualified name reaches the header", v)
+		assert.Equal(t, 4, size)
+	}
+}
+
+func TestPatchField_ShadowedHeaderField(t *testing.T) {
+	msg := NewMsgC2SGateLogin(7, "user", "pass")
+	msg.MsgHeadNoProtocol.PcId = 1
+	data, err := GetBytesFromMsg(msg)
+	require.NoError(t, err)
+
+	require.NoError(t, PatchField(data, msg, "MsgHeadNoProtocol.PcId", uint32(99)))
+	var patched MsgC2SGateLogin
+	require.NoError(t, ReadMsgFromBytes(data, &patched))
+	assert.Equal(t, uint32(99), patched.MsgHeadNoProtocol.PcId)
+	assert.Equal(t, uint32(7), patched.PcId, "the body field is untouched")
+
+	require.NoError(t, PatchField(data, msg, "PcId", uint32(8)))
+	require.NoError(t, ReadMsgFromBytes(data, &patched))
+	assert.Equal(t, uint32(99), patched.MsgHeadNoProtocol.PcId, "the header field is untouched")
+	assert.Equal(t, uint32(8), patched.PcId)
+}
+
+func TestPatchField_Errors(t *testing.T) {
+	msg := NewMsgS2CCharacterLogin(5, "Hero", 0, 1)
+	data, err := GetBytesFromMsg(&msg)
+	require.NoError(t, err)
+	original := bytes.Clone(data)
+
+	assert.ErrorIs(t, PatchField(data, &msg, "MapNum", uint32(7)), ErrFieldSize)
+	assert.ErrorIs(t, PatchField(data, &msg, "Missing", uint16(7)), ErrUnknownField)
+	assert.ErrorIs(t, PatchField(data[:38], &msg, "MapNum", uint16(7)), io.ErrUnexpectedEOF)
+	assert.Equal(t, original, data, "failed patches must not modify data")
+}
@@ -28,3 +28,25 @@ func NewMsgZACLChkTimeTick(pcId uint32, tickCount uint32, tickSvr uint32) *MsgZA
 	msg.SetSize()
 	return &msg
 }
+
+// NewMsgZACLChkTimeTickReply builds the client's answer to a tick request from
+// the server. The round trip is filled in this order:
+//
+//  1. The server sends a tick with TickCount (a sequence number) and TickSvr
+//     (its own tick, e.g. milliseconds, at send time); TickClt is zero.
+//  2. The client replies with this constructor, echoing PcId, TickCount and
+//     TickSvr unchanged and setting TickClt to its own tick at reply time.
+//  3. On receipt the server calls RTT with its current tick.
+func NewMsgZACLChkTimeTickReply(req *MsgZACLChkTimeTick, tickClt uint32) *MsgZACLChkTimeTick {
+	msg := NewMsgZACLChkTimeTick(req.PcId, req.TickCount, req.TickSvr)
+	msg.TickClt = tickClt
+	return msg
+}
+
+// RTT returns the round-trip time of a tick reply, measured on the server's
+// clock: now minus the echoed TickSvr. Both values must come from the same
+// clock. The subtraction is done in uint32 so it stays correct when the tick
+// counter wraps around between send and receipt.
+func (msg *MsgZACLChkTimeTick) RTT(now uint32) uint32 {
+	return now - msg.TickSvr
+}
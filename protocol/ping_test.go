@@ -0,0 +1,31 @@
+package protocol
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMsgZACLChkTimeTickReply(t *testing.T) {
+	req := NewMsgZACLChkTimeTick(7, 3, 1000)
+	reply := NewMsgZACLChkTimeTickReply(req, 5555)
+
+	assert.Equal(t, req.PcId, reply.PcId)
+	assert.Equal(t, req.Ctrl, reply.Ctrl)
+	assert.Equal(t, req.Cmd, reply.Cmd)
+	assert.Equal(t, uint32(3), reply.TickCount)
+	assert.Equal(t, uint32(1000), reply.TickSvr)
+	assert.Equal(t, uint32(5555), reply.TickClt)
+	assert.Equal(t, reply.GetSize(), reply.Size)
+}
+
+func TestMsgZACLChkTimeTick_RTT(t *testing.T) {
+	reply := NewMsgZACLChkTimeTickReply(NewMsgZACLChkTimeTick(1, 1, 1000), 0)
+	assert.Equal(t, uint32(250), reply.RTT(1250))
+}
+
+func TestMsgZACLChkTimeTick_RTTAcrossWrap(t *testing.T) {
+	reply := NewMsgZACLChkTimeTickReply(NewMsgZACLChkTimeTick(1, 1, math.MaxUint32-9), 0)
+	assert.Equal(t, uint32(20), reply.RTT(10))
+}
@@ -0,0 +1,167 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Direction identifies which side of a connection sends a message.
+type Direction byte
+
+// Message directions.
+const (
+	DirectionC2S Direction = iota + 1 // client to server
+	DirectionS2C                      // server to client
+	DirectionS2S                      // server to server (login, gate and zone links)
+)
+
+// String returns the short name of the direction (C2S, S2C or S2S).
+func (d Direction) String() string {
+	switch d {
+	case DirectionC2S:
+		return "C2S"
+	case DirectionS2C:
+		return "S2C"
+	case DirectionS2S:
+		return "S2S"
+	default:
+		return "unknown"
+	}
+}
+
+// RoutingKey identifies a message type on the wire. Messages embedding MsgHead
+// are distinguished by Protocol; messages embedding only MsgHeadNoProtocol are
+// distinguished by Ctrl and Cmd alone and leave HasProtocol false.
+type RoutingKey struct {
+	Direction   Direction
+	Ctrl        byte
+	Cmd         byte
+	HasProtocol bool
+	Protocol    uint16
+}
+
+// registration ties a message type to the routing key it is decoded from.
+type registration struct {
+	name string
+	key  RoutingKey
+	new  func() Message
+}
+
+func noProtocol(dir Direction, ctrl, cmd byte) RoutingKey {
+	return RoutingKey{Direction: dir, Ctrl: ctrl, Cmd: cmd}
+}
+
+func withProtocol(dir Direction, protocol uint16) RoutingKey {
+	return RoutingKey{Direction: dir, Ctrl: 0x03, Cmd: 0xFF, HasProtocol: true, Protocol: protocol}
+}
+
+// registry lists every decodable message. The Ctrl/Cmd/Protocol values mirror
+// the ones hard-coded in each message's constructor.
+var registry = []registration{
+	{"MsgC2SLogin", noProtocol(DirectionC2S, 0x01, 0xE0), func() Message { return new(MsgC2SLogin) }},
+	{"MsgC2SSelectServer", noProtocol(DirectionC2S, 0x01, 0xE1), func() Message { return new(MsgC2SSelectServer) }},
+	{"MsgC2SGateLogin", noProtocol(DirectionC2S, 0x01, 0xE2), func() Message { return new(MsgC2SGateLogin) }},
+	{"MsgZACLChkTimeTick", noProtocol(DirectionC2S, 0x01, 0xF0), func() Message { return new(MsgZACLChkTimeTick) }},
+	{"MsgC2SCharacterLogin", withProtocol(DirectionC2S, C2SCharacterLogin), func() Message { return new(MsgC2SCharacterLogin) }},
+	{"MsgC2SWorldLogin", withProtocol(DirectionC2S, C2SWorldLogin), func() Message { return new(MsgC2SWorldLogin) }},
+	{"MsgC2SCharacterLogout", withProtocol(DirectionC2S, C2SCharacterLogout), func() Message { return new(MsgC2SCharacterLogout) }},
+	{"MsgC2SOpenMarket", withProtocol(DirectionC2S, C2SOpenMarket), func() Message { return new(MsgC2SOpenMarket) }},
+	{"MsgC2SSay", withProtocol(DirectionC2S, C2SSay), func() Message { return new(MsgC2SSay) }},
+	{"MsgC2SReqClanInfo", withProtocol(DirectionC2S, C2SReqClanInfo), func() Message { return new(MsgC2SReqClanInfo) }},
+	{"MsgC2SAskDeletePlayer", withProtocol(DirectionC2S, C2SAskDeletePlayer), func() Message { return new(MsgC2SAskDeletePlayer) }},
+
+	{"MsgLs2ClSay", noProtocol(DirectionS2C, 0x01, 0xE0), func() Message { return new(MsgLs2ClSay) }},
+	{"MsgS2CGateInfo", noProtocol(DirectionS2C, 0x01, 0xE2), func() Message { return new(MsgS2CGateInfo) }},
+	{"MsgZACLChkTimeTick", noProtocol(DirectionS2C, 0x01, 0xF0), func() Message { return new(MsgZACLChkTimeTick) }},
+	{"MsgS2CError", withProtocol(DirectionS2C, S2CError), func() Message { return new(MsgS2CError) }},
+	{"MsgS2CCharacterList", withProtocol(DirectionS2C, S2CCharacterList), func() Message { return new(MsgS2CCharacterList) }},
+	{"MsgS2CCharacterLogin", withProtocol(DirectionS2C, S2CCharacterLoginOk), func() Message { return new(MsgS2CCharacterLogin) }},
+	{"MsgS2CWorldLogin", withProtocol(DirectionS2C, S2CWorldLogin), func() Message { return new(MsgS2CWorldLogin) }},
+	{"MsgS2CLevelUp", withProtocol(DirectionS2C, S2CLevelUp), func() Message { return new(MsgS2CLevelUp) }},
+	{"MsgS2CSay", withProtocol(DirectionS2C, S2CSay), func() Message { return new(MsgS2CSay) }},
+	{"MsgS2CClanInfo", withProtocol(DirectionS2C, S2CClanInfo), func() Message { return new(MsgS2CClanInfo) }},
+
+	{"MsgGate2LsConnect", noProtocol(DirectionS2S, 0x02, 0xE0), func() Message { return new(MsgGate2LsConnect) }},
+	{"MsgGate2LsAccLogout", noProtocol(DirectionS2S, 0x02, 0xE2), func() Message { return new(MsgGate2LsAccLogout) }},
+	{"MsgGate2LsPreparedAccLogin", noProtocol(DirectionS2S, 0x02, 0xE3), func() Message { return new(MsgGate2LsPreparedAccLogin) }},
+	{"MsgGate2ZsConnect", noProtocol(DirectionS2S, 0x01, 0xE0), func() Message { return new(MsgGate2ZsConnect) }},
+	{"MsgLs2GateLogin", noProtocol(DirectionS2S, 0x01, 0xE1), func() Message { return new(MsgLs2GateLogin) }},
+	{"MsgZa2ZsAccLogout", noProtocol(DirectionS2S, 0x01, 0xE2), func() Message { return new(MsgZa2ZsAccLogout) }},
+	{"MsgLs2ZaDisconnect", noProtocol(DirectionS2S, 0x01, 0xE3), func() Message { return new(MsgLs2ZaDisconnect) }},
+}
+
+// ErrDuplicateRoutingKey is returned by CheckRoutingUniqueness when two
+// registered messages cannot be told apart by Decode.
+var ErrDuplicateRoutingKey = errors.New("protocol: duplicate routing key")
+
+// CheckRoutingUniqueness verifies that Decode can map every frame to exactly
+// one registered message type. It reports two kinds of conflict, each naming
+// both messages: two messages registered under the same routing key in the
+// same direction, and a message without a Protocol field registered on
+// Ctrl 0x03 / Cmd 0xFF, which Decode would match before any Protocol-routed
+// message in that direction. Messages that reuse Ctrl/Cmd values in different
+// directions, or the same Cmd under a different Ctrl, do not conflict. All
+// conflicts are returned joined with errors.Join, each wrapping
+// ErrDuplicateRoutingKey.
+func CheckRoutingUniqueness() error {
+	return checkRoutingUniqueness(registry)
+}
+
+func checkRoutingUniqueness(regs []registration) error {
+	var errs []error
+	seen := make(map[RoutingKey]string, len(regs))
+	for _, reg := range regs {
+		if other, ok := seen[reg.key]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s and %s both use %s", ErrDuplicateRoutingKey, other, reg.name, reg.key))
+			continue
+		}
+
+		seen[reg.key] = reg.name
+	}
+
+	for _, reg := range regs {
+		if !reg.key.HasProtocol {
+			continue
+		}
+
+		shadow := RoutingKey{Direction: reg.key.Direction, Ctrl: reg.key.Ctrl, Cmd: reg.key.Cmd}
+		if other, ok := seen[shadow]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s (%s) shadows %s (%s)", ErrDuplicateRoutingKey, other, shadow, reg.name, reg.key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// String formats the key as it appears in error messages, for example
+// "C2S ctrl=0x03 cmd=0xFF protocol=0x1106".
+func (k RoutingKey) String() string {
+	s := fmt.Sprintf("%s ctrl=0x%02X cmd=0x%02X", k.Direction, k.Ctrl, k.Cmd)
+	if k.HasProtocol {
+		s += fmt.Sprintf(" protocol=0x%04X", k.Protocol)
+	}
+
+	return s
+}
+
+// registered is a registry entry as looked up by routing key: the
+// registration plus the per-message settings that can change at run time.
+// minClientVersion is set by SetMinClientVersion and guarded by versionMu.
+type registered struct {
+	*registration
+	minClientVersion uint32
+}
+
+// registryIndex maps each routing key to its registration for Decode.
+var registryIndex = buildRegistryIndex()
+
+func buildRegistryIndex() map[RoutingKey]*registered {
+	index := make(map[RoutingKey]*registered, len(registry))
+	for i := range registry {
+		if _, ok := index[registry[i].key]; !ok {
+			index[registry[i].key] = &registered{registration: &registry[i]}
+		}
+	}
+
+	return index
+}
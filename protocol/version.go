@@ -0,0 +1,63 @@
+package protocol
+
+import (
+	"fmt"
+	"sync"
+)
+
+// versionMu guards the minClientVersion field of every registration.
+var versionMu sync.RWMutex
+
+// SetMinClientVersion records version as the lowest client version, as sent
+// in MsgC2SCharacterLogin.ClientVersion, that supports the message registered
+// under key. A version of 0 removes the requirement. The minimum is stored in
+// the message registry and reported by MessageDescriptors. It returns an
+// error wrapping ErrUnknownMessage if no message is registered under key.
+// SetMinClientVersion is safe for concurrent use with the other functions in
+// this file.
+func SetMinClientVersion(key RoutingKey, version uint32) error {
+	reg, ok := registryIndex[key]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownMessage, key)
+	}
+
+	versionMu.Lock()
+	reg.minClientVersion = version
+	versionMu.Unlock()
+	return nil
+}
+
+// MinClientVersion returns the lowest client version registered with
+// SetMinClientVersion for the message registered under key, or 0 if any
+// version supports it or no message is registered under key.
+func MinClientVersion(key RoutingKey) uint32 {
+	reg, ok := registryIndex[key]
+	if !ok {
+		return 0
+	}
+
+	versionMu.RLock()
+	defer versionMu.RUnlock()
+	return reg.minClientVersion
+}
+
+// AcceptsClientVersion reports whether a client at version supports the
+// message with header head. The header is resolved to a registered message
+// the way Decode resolves a frame: by Ctrl and Cmd, then by Protocol. A header
+// carries no direction, so both client directions are considered and version
+// must meet the requirement of the C2S and of the S2C message. Headers that
+// match no registered message are accepted.
+func AcceptsClientVersion(head MsgHead, version uint32) bool {
+	for _, dir := range [...]Direction{DirectionC2S, DirectionS2C} {
+		key := RoutingKey{Direction: dir, Ctrl: head.Ctrl, Cmd: head.Cmd}
+		if _, ok := registryIndex[key]; !ok {
+			key.HasProtocol, key.Protocol = true, head.Protocol
+		}
+
+		if version < MinClientVersion(key) {
+			return false
+		}
+	}
+
+	return true
+}
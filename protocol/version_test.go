@@ -0,0 +1,53 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinClientVersion(t *testing.T) {
+	key := withProtocol(DirectionS2C, S2CLevelUp)
+	defer func() { _ = SetMinClientVersion(key, 0) }()
+
+	msg := NewMsgS2CLevelUp(10)
+	assert.Equal(t, uint32(0), MinClientVersion(key))
+	assert.True(t, AcceptsClientVersion(msg.MsgHead, 0))
+
+	require.NoError(t, SetMinClientVersion(key, 562))
+	assert.Equal(t, uint32(562), MinClientVersion(key))
+	assert.False(t, AcceptsClientVersion(msg.MsgHead, 561))
+	assert.True(t, AcceptsClientVersion(msg.MsgHead, 562))
+	assert.True(t, AcceptsClientVersion(msg.MsgHead, 600))
+
+	// Other messages are unaffected.
+	say := NewMsgS2CSay(1, General, "Hero", "hi")
+	assert.True(t, AcceptsClientVersion(say.MsgHead, 0))
+
+	require.NoError(t, SetMinClientVersion(key, 0))
+	assert.True(t, AcceptsClientVersion(msg.MsgHead, 0))
+}
+
+func TestMinClientVersion_InDescriptors(t *testing.T) {
+	key := noProtocol(DirectionC2S, 0x01, 0xE0)
+	defer func() { _ = SetMinClientVersion(key, 0) }()
+
+	require.NoError(t, SetMinClientVersion(key, 562))
+	for _, d := range MessageDescriptors() {
+		if d.RoutingKey == key {
+			assert.Equal(t, uint32(562), d.MinClientVersion, d.Name)
+		} else {
+			assert.Zero(t, d.MinClientVersion, d.Name)
+		}
+	}
+
+	// The no-protocol key is matched before Protocol, as Decode does.
+	login := NewMsgC2SLogin("user", "pass")
+	assert.False(t, AcceptsClientVersion(MsgHead{MsgHeadNoProtocol: login.MsgHeadNoProtocol}, 561))
+}
+
+func TestSetMinClientVersion_UnknownKey(t *testing.T) {
+	err := SetMinClientVersion(withProtocol(DirectionS2C, 0xFFFF), 562)
+	assert.ErrorIs(t, err, ErrUnknownMessage)
+}
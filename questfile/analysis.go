@@ -0,0 +1,191 @@
+package questfile
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// NPCQuestMap maps each given-NPC ID to the IDs of the quests it offers, in
+// the order the quests appear in quests. A quest ID listed under more than
+// one NPC usually means a quest was attached to the wrong NPC.
+func NPCQuestMap(quests []QuestFile) map[uint16][]uint16 {
+	m := make(map[uint16][]uint16)
+	for i := range quests {
+		npcID := quests[i].Header.GivenNPCID()
+		m[npcID] = append(m[npcID], quests[i].Header.QuestID())
+	}
+
+	return m
+}
+
+// Filter returns the quests for which pred returns true, in their original
+// order. The quests are copied; quests itself is not modified. Predicates
+// such as ByGivenNPC, ByLevelRange and RewardsItem can be combined inside a
+// closure.
+func Filter(quests []QuestFile, pred func(*QuestFile) bool) []QuestFile {
+	var matched []QuestFile
+	for i := range quests {
+		if pred(&quests[i]) {
+			matched = append(matched, quests[i])
+		}
+	}
+
+	return matched
+}
+
+// ByGivenNPC returns a predicate matching quests offered by NPC id.
+func ByGivenNPC(id uint16) func(*QuestFile) bool {
+	return func(q *QuestFile) bool {
+		return q.Header.GivenNPCID() == id
+	}
+}
+
+// ByLevelRange returns a predicate matching quests whose [MinLevel, MaxLevel]
+// range overlaps [minLevel, maxLevel]. ByLevelRange(n, n) matches every quest
+// available at level n.
+func ByLevelRange(minLevel, maxLevel uint8) func(*QuestFile) bool {
+	return func(q *QuestFile) bool {
+		return q.Header.MinLevel <= maxLevel && q.Header.MaxLevel >= minLevel
+	}
+}
+
+// RewardsItem returns a predicate matching quests with code in one of their
+// three reward slots. UnusedRewardItemCode marks an empty slot and never
+// matches.
+func RewardsItem(code uint16) func(*QuestFile) bool {
+	return func(q *QuestFile) bool {
+		if code == UnusedRewardItemCode {
+			return false
+		}
+
+		for _, r := range rewardSlots(&q.Header) {
+			if r.code == code {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// QuestsTargeting returns the IDs of the quests with an active objective of
+// type kind whose TargetID is targetID, in the order the quests appear in
+// quests. Each quest is listed once, however many of its objectives match.
+// It answers questions such as "which quests break if monster X is
+// removed?". FIND objectives have no target ID and never match.
+func QuestsTargeting(quests []QuestFile, kind ObjectiveType, targetID uint16) []uint16 {
+	var ids []uint16
+	for i := range quests {
+		for j := range quests[i].Objectives {
+			o := &quests[i].Objectives[j]
+			if o.ObjectiveType() != kind {
+				continue
+			}
+
+			if id, ok := o.TargetID(); ok && id == targetID {
+				ids = append(ids, quests[i].Header.QuestID())
+				break
+			}
+		}
+	}
+
+	return ids
+}
+
+// RewardItemUsage counts how many reward slots across quests hand out each
+// item code. A quest rewarding the same item in two slots counts twice.
+// Empty slots (UnusedRewardItemCode) are not counted.
+func RewardItemUsage(quests []QuestFile) map[uint16]int {
+	usage := make(map[uint16]int)
+	for i := range quests {
+		for _, r := range rewardSlots(&quests[i].Header) {
+			if r.code != UnusedRewardItemCode {
+				usage[r.code]++
+			}
+		}
+	}
+
+	return usage
+}
+
+// RewardItemQuantities is like RewardItemUsage but weights each slot by its
+// count (Count1 to Count3), giving the total quantity of each item handed out
+// if every quest is completed once.
+func RewardItemQuantities(quests []QuestFile) map[uint16]int {
+	quantities := make(map[uint16]int)
+	for i := range quests {
+		for _, r := range rewardSlots(&quests[i].Header) {
+			if r.code != UnusedRewardItemCode {
+				quantities[r.code] += int(r.count)
+			}
+		}
+	}
+
+	return quantities
+}
+
+// RewardItem is one used reward slot: the item code handed out and how many.
+type RewardItem struct {
+	Code  uint16
+	Count uint8
+}
+
+// RewardSummary gathers the reward-facing numbers of one quest.
+type RewardSummary struct {
+	EXP   uint32
+	Woonz uint32
+	Lore  uint32
+
+	// Items lists the used reward slots in slot order; empty slots
+	// (UnusedRewardItemCode) are left out.
+	Items []RewardItem
+
+	// EXPPerObjective is EXP divided by the number of active objectives, or
+	// zero for a quest without any.
+	EXPPerObjective float64
+}
+
+// RewardSummary returns the quest's EXP, Woonz, Lore and reward items
+// together with the EXP earned per active objective.
+func (q *QuestFile) RewardSummary() RewardSummary {
+	s := RewardSummary{
+		EXP:   q.Header.EXP,
+		Woonz: q.Header.Woonz,
+		Lore:  q.Header.Lore,
+		Items: q.Header.Rewards(),
+	}
+
+	if n := q.ObjectiveCount(); n > 0 {
+		s.EXPPerObjective = float64(q.Header.EXP) / float64(n)
+	}
+
+	return s
+}
+
+// rewardSlot is the item code and count of one reward slot.
+type rewardSlot struct {
+	code  uint16
+	count uint8
+}
+
+// rewardSlots returns the three reward slots of h with their counts.
+func rewardSlots(h *QuestHeader) [3]rewardSlot {
+	return [3]rewardSlot{
+		{binary.LittleEndian.Uint16(h.RewardSlot1[:2]), h.Count1},
+		{binary.LittleEndian.Uint16(h.RewardSlot2[:2]), h.Count2},
+		{binary.LittleEndian.Uint16(h.RewardSlot3[:2]), h.Count3},
+	}
+}
+
+// MemSize returns the approximate number of bytes q occupies in memory: the
+// fixed size of the QuestFile struct plus the capacity of every objective's
+// Name slice. Summed over a loaded dataset it gives a figure for capacity
+// planning; allocator overhead is not included.
+func (q *QuestFile) MemSize() int {
+	n := int(unsafe.Sizeof(*q))
+	for i := range q.Objectives {
+		n += cap(q.Objectives[i].Name)
+	}
+
+	return n
+}
@@ -0,0 +1,177 @@
+package questfile
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func questGivenBy(questID, npcID uint16) QuestFile {
+	q := minimalValidQuestFile()
+	q.Header.SetQuestID(questID)
+	q.Header.SetGivenNPCID(npcID)
+	return q
+}
+
+func TestNPCQuestMap(t *testing.T) {
+	quests := []QuestFile{
+		questGivenBy(1, 100),
+		questGivenBy(2, 200),
+		questGivenBy(3, 100),
+		questGivenBy(2, 100), // quest 2 also offered by NPC 100
+	}
+
+	assert.Equal(t, map[uint16][]uint16{
+		100: {1, 3, 2},
+		200: {2},
+	}, NPCQuestMap(quests))
+}
+
+func TestNPCQuestMap_Empty(t *testing.T) {
+	assert.Empty(t, NPCQuestMap(nil))
+}
+
+func questIDs(quests []QuestFile) []uint16 {
+	var ids []uint16
+	for i := range quests {
+		ids = append(ids, quests[i].Header.QuestID())
+	}
+
+	return ids
+}
+
+func questWithLevels(id uint16, minLevel, maxLevel uint8) QuestFile {
+	q := questGivenBy(id, 1)
+	q.Header.MinLevel = minLevel
+	q.Header.MaxLevel = maxLevel
+	return q
+}
+
+func TestFilter_ByGivenNPC(t *testing.T) {
+	quests := []QuestFile{questGivenBy(1, 100), questGivenBy(2, 200), questGivenBy(3, 100)}
+	assert.Equal(t, []uint16{1, 3}, questIDs(Filter(quests, ByGivenNPC(100))))
+	assert.Empty(t, Filter(quests, ByGivenNPC(300)))
+}
+
+func TestFilter_ByLevelRange(t *testing.T) {
+	quests := []QuestFile{
+		questWithLevels(1, 1, 10),
+		questWithLevels(2, 10, 20),
+		questWithLevels(3, 21, 30),
+		questWithLevels(4, 5, 50),
+	}
+	assert.Equal(t, []uint16{1, 2, 4}, questIDs(Filter(quests, ByLevelRange(10, 10))))
+	assert.Equal(t, []uint16{2, 3, 4}, questIDs(Filter(quests, ByLevelRange(15, 25))))
+	assert.Equal(t, []uint16{4}, questIDs(Filter(quests, ByLevelRange(31, 99))))
+}
+
+func TestFilter_RewardsItem(t *testing.T) {
+	a := questGivenBy(1, 1)
+	binary.LittleEndian.PutUint16(a.Header.RewardSlot2[:2], 500)
+	b := questGivenBy(2, 1)
+	binary.LittleEndian.PutUint16(b.Header.RewardSlot3[:2], 500)
+	c := questGivenBy(3, 1)
+	binary.LittleEndian.PutUint16(c.Header.RewardSlot1[:2], 501)
+	quests := []QuestFile{a, b, c}
+
+	assert.Equal(t, []uint16{1, 2}, questIDs(Filter(quests, RewardsItem(500))))
+	assert.Empty(t, Filter(quests, RewardsItem(UnusedRewardItemCode)), "empty slots never match")
+}
+
+func TestFilter_Compose(t *testing.T) {
+	quests := []QuestFile{questWithLevels(1, 1, 10), questWithLevels(2, 1, 10)}
+	quests[1].Header.SetGivenNPCID(7)
+
+	byNPC, byLevel := ByGivenNPC(7), ByLevelRange(5, 5)
+	got := Filter(quests, func(q *QuestFile) bool { return byNPC(q) && byLevel(q) })
+	assert.Equal(t, []uint16{2}, questIDs(got))
+}
+
+func TestRewardItemUsage(t *testing.T) {
+	a := questGivenBy(1, 1)
+	binary.LittleEndian.PutUint16(a.Header.RewardSlot1[:2], 500)
+	a.Header.Count1 = 3
+	binary.LittleEndian.PutUint16(a.Header.RewardSlot2[:2], 500)
+	a.Header.Count2 = 1
+	b := questGivenBy(2, 1)
+	binary.LittleEndian.PutUint16(b.Header.RewardSlot3[:2], 500)
+	b.Header.Count3 = 2
+	binary.LittleEndian.PutUint16(b.Header.RewardSlot1[:2], 777)
+	b.Header.Count1 = 10
+	quests := []QuestFile{a, b}
+
+	assert.Equal(t, map[uint16]int{500: 3, 777: 1}, RewardItemUsage(quests))
+	assert.Equal(t, map[uint16]int{500: 6, 777: 10}, RewardItemQuantities(quests))
+}
+
+func TestRewardItemUsage_IgnoresUnusedSlots(t *testing.T) {
+	q := questGivenBy(1, 1)
+	binary.LittleEndian.PutUint16(q.Header.RewardSlot1[:2], UnusedRewardItemCode)
+	binary.LittleEndian.PutUint16(q.Header.RewardSlot2[:2], UnusedRewardItemCode)
+	binary.LittleEndian.PutUint16(q.Header.RewardSlot3[:2], UnusedRewardItemCode)
+	q.Header.Count1 = 5
+
+	assert.Empty(t, RewardItemUsage([]QuestFile{q}))
+	assert.Empty(t, RewardItemQuantities([]QuestFile{q}))
+	assert.Empty(t, RewardItemUsage(nil))
+}
+
+func TestRewardSummary(t *testing.T) {
+	q := minimalValidQuestFile()
+	for i := 4; i < NumObjectives; i++ {
+		q.Objectives[i] = unusedObjective()
+	}
+	binary.LittleEndian.PutUint16(q.Header.RewardSlot2[:2], 500)
+	q.Header.Count2 = 3
+
+	assert.Equal(t, RewardSummary{
+		EXP:             1000,
+		Woonz:           500,
+		Lore:            100,
+		Items:           []RewardItem{{Code: 500, Count: 3}},
+		EXPPerObjective: 250,
+	}, q.RewardSummary())
+}
+
+func TestRewardSummary_NoObjectives(t *testing.T) {
+	q := minimalValidQuestFile()
+	for i := range q.Objectives {
+		q.Objectives[i] = unusedObjective()
+	}
+
+	s := q.RewardSummary()
+	assert.Zero(t, s.EXPPerObjective)
+	assert.Empty(t, s.Items)
+	assert.Equal(t, 0, q.ObjectiveCount())
+}
+
+func TestQuestsTargeting(t *testing.T) {
+	a := questGivenBy(1, 1)
+	require.NoError(t, a.Objectives[0].SetKillTarget(3, 500, 10))
+	require.NoError(t, a.Objectives[1].SetKillTarget(3, 500, 5)) // same monster twice
+	b := questGivenBy(2, 1)
+	require.NoError(t, b.Objectives[0].SetKillTarget(3, 600, 10))
+	b.Objectives[1].Block[OffType] = byte(TypeQUESTITEM)
+	binary.LittleEndian.PutUint16(b.Objectives[1].Block[OffQuestItemID:], 500)
+	c := questGivenBy(3, 1)
+	c.Objectives[0].Block[OffType] = byte(TypeBRINGNPC)
+	binary.LittleEndian.PutUint16(c.Objectives[0].Block[OffMonsterID:], 500)
+	quests := []QuestFile{a, b, c}
+
+	assert.Equal(t, []uint16{1}, QuestsTargeting(quests, TypeKILL, 500))
+	assert.Equal(t, []uint16{2}, QuestsTargeting(quests, TypeKILL, 600))
+	assert.Equal(t, []uint16{2}, QuestsTargeting(quests, TypeQUESTITEM, 500))
+	assert.Equal(t, []uint16{3}, QuestsTargeting(quests, TypeBRINGNPC, 500))
+	assert.Empty(t, QuestsTargeting(quests, TypeDROP, 500))
+}
+
+func TestMemSize(t *testing.T) {
+	q := minimalValidQuestFile()
+	base := q.MemSize()
+	q.Objectives[0].Name = make([]byte, 5, 16)
+	q.Objectives[3].Name = []byte("Cave")
+	assert.Equal(t, base+16+cap(q.Objectives[3].Name), q.MemSize())
+	assert.Greater(t, base, MinFileSize, "the decoded struct is at least as large as its wire form")
+}
@@ -0,0 +1,55 @@
+package questfile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// objectiveFields names the documented objective block fields and their sizes
+// in bytes. Each starts a 4-byte slot whose remaining bytes are padding.
+var objectiveFields = map[int]struct {
+	name string
+	size int
+}{
+	OffType:        {"Type", 1},
+	OffMapID:       {"MapID", 2},
+	OffLocationID:  {"LocationID", 2},
+	OffRadius:      {"Radius", 1},
+	OffMonsterID:   {"MonsterID", 2},
+	OffKillCount:   {"KillCount", 2},
+	OffQuestItemID: {"QuestItemID", 2},
+	OffItemCount:   {"ItemCount", 2},
+	OffDropRate1:   {"DropRate1", 1},
+	OffDropRate2:   {"DropRate2", 1},
+	OffDropRate3:   {"DropRate3", 1},
+	OffNameLen:     {"NameLen", 1},
+}
+
+// Annotate returns a hex dump of o's block as a research aid for the regions
+// whose meaning is not yet known. The block is printed one 4-byte slot per
+// line: the decimal offset, the four bytes in hex and, for a slot holding a
+// documented field, its name and decoded value (Type=KILL, MapID=12, ...).
+// Every other slot is labeled "unknown". The format is stable, so dumps of
+// many quests can be compared line by line.
+func (o *Objective) Annotate() string {
+	var b strings.Builder
+	for off := 0; off < ObjectiveBlockSize; off += 4 {
+		slot := o.Block[off : off+4]
+		label := "unknown"
+		if f, ok := objectiveFields[off]; ok {
+			switch {
+			case off == OffType:
+				label = fmt.Sprintf("%s=%s", f.name, ObjectiveType(slot[0]))
+			case f.size == 2:
+				label = fmt.Sprintf("%s=%d", f.name, binary.LittleEndian.Uint16(slot))
+			default:
+				label = fmt.Sprintf("%s=%d", f.name, slot[0])
+			}
+		}
+
+		fmt.Fprintf(&b, "%2d  % x  %s\n", off, slot, label)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,29 @@
+package questfile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjective_Annotate(t *testing.T) {
+	var o Objective
+	require.NoError(t, o.SetKillTarget(12, 300, 5))
+	o.Block[40] = 0xAB
+
+	lines := strings.Split(strings.TrimSuffix(o.Annotate(), "\n"), "\n")
+	require.Len(t, lines, ObjectiveBlockSize/4)
+	assert.Equal(t, " 0  00 00 00 00  Type=KILL", lines[0])
+	assert.Equal(t, " 4  0c 00 00 00  MapID=12", lines[1])
+	assert.Equal(t, "16  2c 01 00 00  MonsterID=300", lines[4])
+	assert.Equal(t, "20  05 00 00 00  KillCount=5", lines[5])
+	assert.Equal(t, "40  ab 00 00 00  unknown", lines[10])
+	assert.Equal(t, "92  00 00 00 00  NameLen=0", lines[23])
+}
+
+func TestObjective_Annotate_Unused(t *testing.T) {
+	o := unusedObjective()
+	assert.True(t, strings.HasPrefix(o.Annotate(), " 0  ff ff ff ff  Type=UNUSED\n"))
+}
@@ -0,0 +1,246 @@
+package questfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ErrDanglingLink is wrapped by the errors ValidateLinks returns for a
+// continuation that points at a quest missing from the archive.
+var ErrDanglingLink = errors.New("questfile: continuation target not in archive")
+
+// Archive is a set of quests keyed by quest ID.
+type Archive map[uint16]QuestFile
+
+// NewArchive returns an Archive holding quests keyed by QuestID. When two
+// quests share an ID the later one wins.
+func NewArchive(quests []QuestFile) Archive {
+	a := make(Archive, len(quests))
+	for _, q := range quests {
+		a[q.Header.QuestID()] = q
+	}
+
+	return a
+}
+
+// LinkError describes a dangling continuation: slot Slot of quest QuestID
+// points at Target, which is not in the archive.
+type LinkError struct {
+	QuestID uint16
+	Slot    int
+	Target  uint16
+}
+
+func (e *LinkError) Error() string {
+	return fmt.Sprintf("quest %d continuation %d: %v: %d", e.QuestID, e.Slot, ErrDanglingLink, e.Target)
+}
+
+func (e *LinkError) Unwrap() error {
+	return ErrDanglingLink
+}
+
+// ValidateLinks checks that every continuation used by a quest in a resolves
+// to a quest in a, so no quest chain dead-ends in a missing quest. It returns
+// one *LinkError per dangling link, ordered by source quest ID and slot, or
+// nil if every link resolves. Like NextQuestIDs, each target is the lower 16
+// bits of its slot.
+func (a Archive) ValidateLinks() []error {
+	ids := make([]uint16, 0, len(a))
+	for id := range a {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	var errs []error
+	for _, id := range ids {
+		q := a[id]
+		for slot, c := range q.Continuation {
+			if c == UnusedContinuation {
+				continue
+			}
+
+			if _, ok := a[uint16(c)]; !ok {
+				errs = append(errs, &LinkError{QuestID: id, Slot: slot, Target: uint16(c)})
+			}
+		}
+	}
+
+	return errs
+}
+
+// ArchiveError reports where an archive stopped being structurally valid:
+// the zero-based index of the quest being read and the byte offset at which
+// that quest starts.
+type ArchiveError struct {
+	Index  int
+	Offset int64
+	Err    error
+}
+
+func (e *ArchiveError) Error() string {
+	return fmt.Sprintf("quest %d at offset %d: %v", e.Index, e.Offset, e.Err)
+}
+
+func (e *ArchiveError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyArchive checks that r holds a structurally complete quest archive: a
+// little-endian uint32 quest count followed by that many quest files back to
+// back. Each quest is walked using sizes only (header, seven objective blocks
+// with the name bytes announced by each name-length byte, and continuation),
+// without decoding it into a QuestFile, so the check is fast and allocates
+// almost nothing. Objective types and other field values are not checked; use
+// Read for that.
+//
+// It returns the number of complete quests. If the archive is truncated the
+// error is an *ArchiveError wrapping io.ErrUnexpectedEOF whose Index and
+// Offset identify the first incomplete quest; data after the last quest is
+// reported the same way with ErrTrailingBytes and Index equal to the count.
+// Errors from r are wrapped unchanged.
+func VerifyArchive(r io.Reader) (int, error) {
+	var block [ObjectiveBlockSize]byte
+	return walkArchive(r, func(r io.Reader) (int64, error) {
+		return skipQuest(r, &block)
+	})
+}
+
+// ReadArchive reads a quest archive, the format VerifyArchive checks: a
+// little-endian uint32 quest count followed by that many quest files back to
+// back. Each quest is decoded as Read does; its length follows from the
+// name-length byte of each objective, so the next quest starts right after
+// its continuation section. The count is not trusted for preallocation.
+//
+// Errors are *ArchiveError values naming the index and starting offset of
+// the quest that failed, wrapping io.ErrUnexpectedEOF on truncation or the
+// error Read would return. Data after the last quest is reported with
+// ErrTrailingBytes and Index equal to the count, as VerifyArchive does.
+func ReadArchive(r io.Reader) ([]QuestFile, error) {
+	var quests []QuestFile
+	_, err := walkArchive(r, func(r io.Reader) (int64, error) {
+		q, _, err := read(r, false, true)
+		if err != nil {
+			return 0, err
+		}
+
+		quests = append(quests, q)
+		return int64(q.Size()), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return quests, nil
+}
+
+// walkArchive reads the quest count of the archive in r and calls quest once
+// per quest, with r positioned at its start; quest consumes it and returns
+// its length. It implements the rules VerifyArchive and ReadArchive share:
+// each failure is wrapped in an *ArchiveError naming the quest and its offset,
+// with io.EOF turned into io.ErrUnexpectedEOF, and any byte after the last
+// quest is ErrTrailingBytes. It returns the number of complete quests.
+func walkArchive(r io.Reader, quest func(io.Reader) (int64, error)) (int, error) {
+	var countBuf [4]byte
+	if _, err := io.ReadFull(r, countBuf[:]); err != nil {
+		return 0, &ArchiveError{Err: unexpectedEOF(err)}
+	}
+
+	count := int(binary.LittleEndian.Uint32(countBuf[:]))
+	offset := int64(len(countBuf))
+	for i := 0; i < count; i++ {
+		n, err := quest(r)
+		if err != nil {
+			return i, &ArchiveError{Index: i, Offset: offset, Err: unexpectedEOF(err)}
+		}
+
+		offset += n
+	}
+
+	if n, _ := r.Read(countBuf[:1]); n > 0 {
+		return count, &ArchiveError{Index: count, Offset: offset, Err: ErrTrailingBytes}
+	}
+
+	return count, nil
+}
+
+// ReadAt decodes the quest that starts at offset off of r, as Read would,
+// and returns it together with the offset just past it, where the next quest
+// of a pack starts. Quests vary in length with their names, so the end
+// offset is only known after decoding. Nothing is read beyond the quest and
+// data after it is not checked. A quest cut short by the end of r returns
+// io.ErrUnexpectedEOF.
+func ReadAt(r io.ReaderAt, off int64) (QuestFile, int64, error) {
+	q, _, err := read(io.NewSectionReader(r, off, MaxFileSize), false, true)
+	if err != nil {
+		return QuestFile{}, 0, err
+	}
+
+	return q, off + int64(q.Size()), nil
+}
+
+// WriteArchive writes quests as an archive ReadArchive and VerifyArchive
+// accept: their count as a little-endian uint32, then each quest encoded with
+// Write. It stops at the first error, which is an *ArchiveError naming the
+// quest being written.
+func WriteArchive(w io.Writer, quests []QuestFile) error {
+	if _, err := w.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(quests)))); err != nil {
+		return &ArchiveError{Err: err}
+	}
+
+	offset := int64(4)
+	for i := range quests {
+		if err := Write(w, quests[i]); err != nil {
+			return &ArchiveError{Index: i, Offset: offset, Err: err}
+		}
+
+		offset += int64(quests[i].Size())
+	}
+
+	return nil
+}
+
+// skipQuest advances r past one quest file, using block as scratch space for
+// the objective blocks, and returns the number of bytes consumed.
+func skipQuest(r io.Reader, block *[ObjectiveBlockSize]byte) (int64, error) {
+	if err := skip(r, HeaderSize, block[:]); err != nil {
+		return 0, err
+	}
+
+	n := int64(HeaderSize)
+	for range NumObjectives {
+		if _, err := io.ReadFull(r, block[:]); err != nil {
+			return 0, err
+		}
+
+		nameLen := int64(block[OffNameLen])
+		if err := skip(r, nameLen, block[:]); err != nil {
+			return 0, err
+		}
+
+		n += ObjectiveBlockSize + nameLen
+	}
+
+	if err := skip(r, ContinuationSize, block[:]); err != nil {
+		return 0, err
+	}
+
+	return n + ContinuationSize, nil
+}
+
+// skip discards exactly n bytes from r, reading them into scratch in chunks.
+func skip(r io.Reader, n int64, scratch []byte) error {
+	for n > 0 {
+		chunk := min(n, int64(len(scratch)))
+		if _, err := io.ReadFull(r, scratch[:chunk]); err != nil {
+			return err
+		}
+
+		n -= chunk
+	}
+
+	return nil
+}
@@ -0,0 +1,208 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveBytes encodes quests as a count-prefixed archive.
+func archiveBytes(t *testing.T, quests ...QuestFile) []byte {
+	t.Helper()
+	buf := binary.LittleEndian.AppendUint32(nil, uint32(len(quests)))
+	for _, q := range quests {
+		buf = append(buf, encodeQuest(t, q)...)
+	}
+
+	return buf
+}
+
+func namedQuest(id uint16) QuestFile {
+	q := minimalValidQuestFile()
+	q.Header.SetQuestID(id)
+	q.Objectives[1].SetFindTarget(1, 2, 3)
+	q.Objectives[1].Name = []byte("Hidden Cave")
+	q.Objectives[1].Block[OffNameLen] = byte(len(q.Objectives[1].Name))
+	return q
+}
+
+func TestVerifyArchive(t *testing.T) {
+	data := archiveBytes(t, minimalValidQuestFile(), namedQuest(2), namedQuest(3))
+	n, err := VerifyArchive(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+}
+
+func TestVerifyArchive_Empty(t *testing.T) {
+	n, err := VerifyArchive(bytes.NewReader([]byte{0, 0, 0, 0}))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	_, err = VerifyArchive(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestVerifyArchive_TruncatedName(t *testing.T) {
+	data := archiveBytes(t, minimalValidQuestFile(), namedQuest(2))
+	data = data[:4+MinFileSize+HeaderSize+2*ObjectiveBlockSize+3]
+
+	n, err := VerifyArchive(bytes.NewReader(data))
+	assert.Equal(t, 1, n)
+	var archErr *ArchiveError
+	require.ErrorAs(t, err, &archErr)
+	assert.Equal(t, 1, archErr.Index)
+	assert.Equal(t, int64(4+MinFileSize), archErr.Offset)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestVerifyArchive_CountExceedsData(t *testing.T) {
+	data := archiveBytes(t, namedQuest(1))
+	binary.LittleEndian.PutUint32(data, 2)
+
+	n, err := VerifyArchive(bytes.NewReader(data))
+	assert.Equal(t, 1, n)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.EqualError(t, err, "quest 1 at offset 795: unexpected EOF")
+}
+
+func TestVerifyArchive_TrailingBytes(t *testing.T) {
+	data := append(archiveBytes(t, minimalValidQuestFile()), 0x00)
+	n, err := VerifyArchive(bytes.NewReader(data))
+	assert.Equal(t, 1, n)
+	assert.ErrorIs(t, err, ErrTrailingBytes)
+}
+
+func TestVerifyArchive_DoesNotAllocatePerQuest(t *testing.T) {
+	data := archiveBytes(t, namedQuest(1), namedQuest(2), namedQuest(3), namedQuest(4))
+	r := bytes.NewReader(data)
+	allocs := testing.AllocsPerRun(10, func() {
+		r.Reset(data)
+		_, _ = VerifyArchive(r)
+	})
+	assert.LessOrEqual(t, allocs, 2.0)
+}
+
+// linkedQuest returns a quest continuing to next; unlisted slots are unused.
+func linkedQuest(id uint16, next ...uint32) QuestFile {
+	q := minimalValidQuestFile()
+	q.Header.SetQuestID(id)
+	copy(q.Continuation[:], next)
+	return q
+}
+
+func TestReadArchive_RoundTrip(t *testing.T) {
+	quests := []QuestFile{minimalValidQuestFile(), namedQuest(2), namedQuest(3)}
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, quests))
+	assert.Equal(t, archiveBytes(t, quests...), buf.Bytes())
+
+	got, err := ReadArchive(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, quests, got)
+}
+
+func TestReadArchive_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, nil))
+	got, err := ReadArchive(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = ReadArchive(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReadArchive_TruncatedName(t *testing.T) {
+	data := archiveBytes(t, minimalValidQuestFile(), namedQuest(2))
+	data = data[:4+MinFileSize+HeaderSize+2*ObjectiveBlockSize+3]
+
+	got, err := ReadArchive(bytes.NewReader(data))
+	assert.Nil(t, got)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	var archErr *ArchiveError
+	require.ErrorAs(t, err, &archErr)
+	assert.Equal(t, 1, archErr.Index)
+	assert.Equal(t, int64(4+MinFileSize), archErr.Offset)
+}
+
+func TestReadArchive_InvalidQuest(t *testing.T) {
+	bad := namedQuest(2)
+	bad.Objectives[3].Block[OffType] = 9
+	_, err := ReadArchive(bytes.NewReader(archiveBytes(t, namedQuest(1), bad)))
+	assert.ErrorIs(t, err, ErrInvalidObjectiveType)
+	assert.EqualError(t, err, "quest 1 at offset 795: questfile: invalid objective type")
+}
+
+func TestReadArchive_TrailingBytes(t *testing.T) {
+	data := append(archiveBytes(t, namedQuest(1)), 0x00)
+	_, err := ReadArchive(bytes.NewReader(data))
+	var archErr *ArchiveError
+	require.ErrorAs(t, err, &archErr)
+	assert.Equal(t, 1, archErr.Index)
+}
+
+func TestReadArchive_AgreesWithVerifyArchive(t *testing.T) {
+	data := archiveBytes(t, minimalValidQuestFile(), namedQuest(2))
+	for _, input := range [][]byte{
+		data,
+		data[:2],
+		data[:4+MinFileSize+HeaderSize+2*ObjectiveBlockSize+3],
+		append(bytes.Clone(data), 0),
+	} {
+		n, verifyErr := VerifyArchive(bytes.NewReader(input))
+		quests, readErr := ReadArchive(bytes.NewReader(input))
+		assert.Equal(t, verifyErr, readErr)
+		if readErr == nil {
+			assert.Len(t, quests, n)
+		}
+	}
+}
+
+func TestReadAt(t *testing.T) {
+	quests := []QuestFile{namedQuest(1), minimalValidQuestFile(), namedQuest(3)}
+	r := bytes.NewReader(archiveBytes(t, quests...))
+
+	off := int64(4)
+	for _, want := range quests {
+		q, next, err := ReadAt(r, off)
+		require.NoError(t, err)
+		assert.Equal(t, want, q)
+		assert.Equal(t, off+int64(want.Size()), next)
+		off = next
+	}
+	assert.Equal(t, r.Size(), off)
+
+	_, _, err := ReadAt(r, off)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	_, _, err = ReadAt(r, off-10)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestArchive_ValidateLinks(t *testing.T) {
+	a := NewArchive([]QuestFile{
+		linkedQuest(1, 2),
+		linkedQuest(2, 3, 9, UnusedContinuation),
+		linkedQuest(3),
+		linkedQuest(5, UnusedContinuation, UnusedContinuation, 8),
+	})
+
+	errs := a.ValidateLinks()
+	require.Len(t, errs, 2)
+	assert.Equal(t, &LinkError{QuestID: 2, Slot: 1, Target: 9}, errs[0])
+	assert.Equal(t, &LinkError{QuestID: 5, Slot: 2, Target: 8}, errs[1])
+	assert.ErrorIs(t, errs[0], ErrDanglingLink)
+	assert.EqualError(t, errs[0], "quest 2 continuation 1: questfile: continuation target not in archive: 9")
+}
+
+func TestArchive_ValidateLinks_AllResolved(t *testing.T) {
+	a := NewArchive([]QuestFile{
+		linkedQuest(1, 2, 3, UnusedContinuation),
+		linkedQuest(2),
+		linkedQuest(3, 1, UnusedContinuation, UnusedContinuation),
+	})
+	assert.Nil(t, a.ValidateLinks())
+}
@@ -0,0 +1,74 @@
+package questfile
+
+import "bytes"
+
+// objectivePadding lists the objective block byte ranges [start, end) that
+// pad documented fields to 4 bytes. The regions that are not yet understood
+// are not included.
+var objectivePadding = [][2]int{
+	{OffType + 1, OffType + 4},
+	{OffMapID + 2, OffMapID + 4},
+	{OffLocationID + 2, OffLocationID + 4},
+	{OffRadius + 1, OffRadius + 4},
+	{OffMonsterID + 2, OffMonsterID + 4},
+	{OffKillCount + 2, OffKillCount + 4},
+	{OffQuestItemID + 2, OffQuestItemID + 4},
+	{OffItemCount + 2, OffItemCount + 4},
+	{OffDropRate1 + 1, OffDropRate1 + 4},
+	{OffDropRate2 + 1, OffDropRate2 + 4},
+	{OffDropRate3 + 1, OffDropRate3 + 4},
+	{OffNameLen + 1, OffNameLen + 4},
+}
+
+// CanonicalBytes returns a deterministic encoding of q for signing. It is the
+// Write encoding of a copy of q in which every unused objective slot is in
+// canonical form (see Canonicalize) and the padding bytes of the header and
+// of active objective blocks are zeroed, so quests that differ only in
+// incidental padding produce the same bytes. Regions whose meaning is not yet
+// known are kept as they are. q must pass Validate, whose error is returned
+// otherwise. CanonicalBytes does not modify q.
+func (q *QuestFile) CanonicalBytes() ([]byte, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := *q
+	c.Canonicalize()
+	clearHeaderPadding(&c.Header)
+
+	for i := range c.Objectives {
+		if c.Objectives[i].IsUnused() {
+			continue
+		}
+
+		for _, r := range objectivePadding {
+			clear(c.Objectives[i].Block[r[0]:r[1]])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, c); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// clearHeaderPadding zeroes the header bytes that carry no data: the upper
+// halves of the 16-bit ID and reward fields and the padding after byte
+// fields. TargetNPCBlock and HeaderTail are kept whole since they are not
+// fully understood (see the TargetOff constants).
+func clearHeaderPadding(h *QuestHeader) {
+	clear(h.QuestIDRaw[2:])
+	clear(h.GivenNPCRaw[2:])
+	h.MinLevelPad = [3]byte{}
+	h.MaxLevelPad = [3]byte{}
+	clear(h.RewardSlot1[2:])
+	clear(h.RewardSlot2[2:])
+	clear(h.RewardSlot3[2:])
+	h.RewardSlot4Pad = [4]byte{}
+	h.RewardAreaPad = [8]byte{}
+	h.Count1Pad = [3]byte{}
+	h.Count2Pad = [3]byte{}
+	h.Count3Pad = [3]byte{}
+}
@@ -0,0 +1,78 @@
+package questfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalBytes_IgnoresPadding(t *testing.T) {
+	a := minimalValidQuestFile()
+	a.Objectives[6] = unusedObjective()
+
+	b := a
+	b.Header.QuestIDRaw[3] = 0x12
+	b.Header.MinLevelPad = [3]byte{1, 2, 3}
+	b.Header.RewardSlot2[2] = 0x7F
+	b.Header.RewardAreaPad[5] = 0x01
+	b.Objectives[0].Block[OffMapID+3] = 0x55
+	b.Objectives[1].Block[OffDropRate2+1] = 0x66
+	b.Objectives[6].Block[OffMapID] = 0x00 // non-canonical unused slot
+	b.Objectives[6].Block[OffNameLen+2] = 0x01
+
+	canonA, err := a.CanonicalBytes()
+	require.NoError(t, err)
+	canonB, err := b.CanonicalBytes()
+	require.NoError(t, err)
+	assert.Equal(t, canonA, canonB)
+
+	var plainA, plainB bytes.Buffer
+	require.NoError(t, Write(&plainA, a))
+	require.NoError(t, Write(&plainB, b))
+	assert.NotEqual(t, plainA.Bytes(), plainB.Bytes(), "Write preserves padding")
+}
+
+func TestCanonicalBytes_KeepsData(t *testing.T) {
+	a := minimalValidQuestFile()
+	canonA, err := a.CanonicalBytes()
+	require.NoError(t, err)
+
+	for name, mutate := range map[string]func(q *QuestFile){
+		"EXP":             func(q *QuestFile) { q.Header.EXP++ },
+		"TargetNPCBlock":  func(q *QuestFile) { q.Header.TargetNPCBlock[10] = 1 },
+		"HeaderTail":      func(q *QuestFile) { q.Header.HeaderTail[2] = 1 },
+		"objective field": func(q *QuestFile) { q.Objectives[2].Block[OffKillCount] = 9 },
+		"unknown region":  func(q *QuestFile) { q.Objectives[2].Block[44] = 9 },
+		"continuation":    func(q *QuestFile) { q.Continuation[0] = 42 },
+	} {
+		b := minimalValidQuestFile()
+		mutate(&b)
+		canonB, err := b.CanonicalBytes()
+		require.NoError(t, err, name)
+		assert.NotEqual(t, canonA, canonB, name)
+	}
+}
+
+func TestCanonicalBytes_ReadsBack(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Header.MinLevelPad = [3]byte{1, 2, 3}
+	q.Header.HeaderTail = [4]byte{1, 2, 3, 4}
+	data, err := q.CanonicalBytes()
+	require.NoError(t, err)
+	assert.Equal(t, [3]byte{1, 2, 3}, q.Header.MinLevelPad, "q must not be modified")
+
+	got, err := Read(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, [3]byte{}, got.Header.MinLevelPad)
+	assert.Equal(t, [4]byte{1, 2, 3, 4}, got.Header.HeaderTail, "unknown regions are kept")
+	assert.Equal(t, q.Header.QuestID(), got.Header.QuestID())
+}
+
+func TestCanonicalBytes_Invalid(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block[OffType] = 9
+	_, err := q.CanonicalBytes()
+	assert.ErrorIs(t, err, ErrInvalidObjectiveType)
+}
@@ -0,0 +1,89 @@
+package questfile
+
+// Continuation kinds returned by ContinuationKind.
+const (
+	ContinuationTerminal  = "terminal"  // no continuation is used
+	ContinuationLinear    = "linear"    // exactly one continuation is used
+	ContinuationBranching = "branching" // more than one continuation is used
+)
+
+// NextQuestIDs returns the IDs of the quests q continues to, in slot order.
+// Slots holding UnusedContinuation are skipped, so a terminal quest yields an
+// empty slice. Like QuestID, each ID is the lower 16 bits of its slot.
+func (q *QuestFile) NextQuestIDs() []uint16 {
+	ids := make([]uint16, 0, len(q.Continuation))
+	for _, c := range q.Continuation {
+		if c != UnusedContinuation {
+			ids = append(ids, uint16(c))
+		}
+	}
+
+	return ids
+}
+
+// ContinuationQuests returns the used continuation slots of q in slot order,
+// as stored: slots holding UnusedContinuation are skipped, so a terminal quest
+// yields an empty slice, and unlike NextQuestIDs the full uint32 is kept.
+func (q *QuestFile) ContinuationQuests() []uint32 {
+	ids := make([]uint32, 0, len(q.Continuation))
+	for _, c := range q.Continuation {
+		if c != UnusedContinuation {
+			ids = append(ids, c)
+		}
+	}
+
+	return ids
+}
+
+// SetContinuation stores questID in continuation slot index (0, 1 or 2). It
+// returns ErrContinuationSlot, leaving q untouched, if index is out of range.
+func (q *QuestFile) SetContinuation(index int, questID uint32) error {
+	if index < 0 || index >= len(q.Continuation) {
+		return ErrContinuationSlot
+	}
+
+	q.Continuation[index] = questID
+	return nil
+}
+
+// ClearContinuation marks continuation slot index (0, 1 or 2) as unused by
+// storing UnusedContinuation. It returns ErrContinuationSlot if index is out
+// of range.
+func (q *QuestFile) ClearContinuation(index int) error {
+	return q.SetContinuation(index, UnusedContinuation)
+}
+
+// ContinuationKind classifies q by how many continuation slots it uses:
+// ContinuationTerminal, ContinuationLinear or ContinuationBranching.
+func (q *QuestFile) ContinuationKind() string {
+	switch len(q.NextQuestIDs()) {
+	case 0:
+		return ContinuationTerminal
+	case 1:
+		return ContinuationLinear
+	default:
+		return ContinuationBranching
+	}
+}
+
+// HasSelfContinuation reports whether any used continuation slot names q's
+// own QuestID, which makes the client loop on quest completion. Validate
+// reports each such slot as a *ContinuationError wrapping
+// ErrSelfContinuation.
+func (q *QuestFile) HasSelfContinuation() bool {
+	return len(q.selfContinuationSlots()) > 0
+}
+
+// selfContinuationSlots returns the indexes of the used continuation slots
+// that point back at q.
+func (q *QuestFile) selfContinuationSlots() []int {
+	var slots []int
+	id := q.Header.QuestID()
+	for i, c := range q.Continuation {
+		if c != UnusedContinuation && uint16(c) == id {
+			slots = append(slots, i)
+		}
+	}
+
+	return slots
+}
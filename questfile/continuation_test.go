@@ -0,0 +1,48 @@
+package questfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextQuestIDs_Terminal(t *testing.T) {
+	q := minimalValidQuestFile()
+	assert.Empty(t, q.NextQuestIDs())
+	assert.Equal(t, ContinuationTerminal, q.ContinuationKind())
+}
+
+func TestNextQuestIDs_Linear(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Continuation[1] = 2001
+	assert.Equal(t, []uint16{2001}, q.NextQuestIDs())
+	assert.Equal(t, ContinuationLinear, q.ContinuationKind())
+}
+
+func TestNextQuestIDs_Branching(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Continuation = [3]uint32{10, UnusedContinuation, 30}
+	assert.Equal(t, []uint16{10, 30}, q.NextQuestIDs())
+	assert.Equal(t, ContinuationBranching, q.ContinuationKind())
+}
+
+func TestContinuationQuests(t *testing.T) {
+	q := minimalValidQuestFile()
+	assert.NotNil(t, q.ContinuationQuests())
+	assert.Empty(t, q.ContinuationQuests())
+
+	require.NoError(t, q.SetContinuation(2, 0x00010030))
+	require.NoError(t, q.SetContinuation(0, 10))
+	assert.Equal(t, []uint32{10, 0x00010030}, q.ContinuationQuests())
+
+	require.NoError(t, q.ClearContinuation(0))
+	assert.Equal(t, [3]uint32{UnusedContinuation, UnusedContinuation, 0x00010030}, q.Continuation)
+}
+
+func TestSetContinuation_SlotOutOfRange(t *testing.T) {
+	q := minimalValidQuestFile()
+	assert.ErrorIs(t, q.SetContinuation(3, 10), ErrContinuationSlot)
+	assert.ErrorIs(t, q.ClearContinuation(-1), ErrContinuationSlot)
+	assert.Equal(t, minimalValidQuestFile(), q)
+}
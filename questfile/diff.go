@@ -0,0 +1,170 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// DiffOptions controls DiffWith.
+type DiffOptions struct {
+	// IncludePadding also reports bytes that belong to no decoded field: the
+	// padding and unknown regions of the header and of each objective block.
+	IncludePadding bool
+}
+
+// headerFields marks the header bytes covered by a decoded field; the others
+// are padding or not yet understood. The offsets follow QuestHeader.
+var headerFields = func() (mask [HeaderSize]bool) {
+	ranges := [][2]int{
+		{0, 2}, {4, 6}, // QuestID, GivenNPCID
+		{8 + TargetOffNPCID, 8 + TargetOffNPCID + 2},
+		{8 + TargetOffMapID, 8 + TargetOffMapID + 2},
+		{8 + TargetOffX, 8 + TargetOffY + 2},
+		{8 + TargetOffFlag, 8 + TargetOffFlag + 1},
+		{32, 33}, {36, 37}, {40, 44}, // MinLevel, MaxLevel, QuestFlags
+		{44, 46}, {48, 50}, {52, 54}, // reward item codes
+		{68, 69}, {72, 73}, {76, 77}, // reward counts
+		{80, 92}, // EXP, Woonz, Lore
+	}
+	for _, r := range ranges {
+		for i := r[0]; i < r[1]; i++ {
+			mask[i] = true
+		}
+	}
+
+	return mask
+}()
+
+// Diff returns the differences between a and b as human-readable lines, one
+// per changed field, in file order: "MinLevel: 10 -> 12", "Reward slot 1
+// count: 1 -> 3", "Objective 2 type: KILL -> DROP". Fields are compared
+// decoded, so only the documented fields are reported; padding and unknown
+// bytes are ignored. Reward slots, objectives and continuations are numbered
+// from zero. It returns nil when the decoded fields are equal.
+func Diff(a, b QuestFile) []string {
+	return DiffWith(a, b, DiffOptions{})
+}
+
+// DiffWith is Diff with options. With opts.IncludePadding set, every other
+// differing byte of the header and objective blocks is reported too, as
+// "Header byte 94: 0x00 -> 0x01" or "Objective 3 byte 41: 0x00 -> 0x12".
+func DiffWith(a, b QuestFile, opts DiffOptions) []string {
+	var d differ
+	ha, hb := &a.Header, &b.Header
+	d.add("QuestID", ha.QuestID(), hb.QuestID())
+	d.add("GivenNPCID", ha.GivenNPCID(), hb.GivenNPCID())
+	d.add("TargetNPCID", ha.TargetNPCID(), hb.TargetNPCID())
+	d.add("TargetNPCMap", ha.TargetNPCMap(), hb.TargetNPCMap())
+	xa, ya := ha.TargetNPCXY()
+	xb, yb := hb.TargetNPCXY()
+	d.add("TargetNPCX", xa, xb)
+	d.add("TargetNPCY", ya, yb)
+	d.add("TargetNPCFlag", ha.TargetNPCFlag(), hb.TargetNPCFlag())
+	d.add("MinLevel", ha.MinLevel, hb.MinLevel)
+	d.add("MaxLevel", ha.MaxLevel, hb.MaxLevel)
+	d.add("QuestFlags", fmt.Sprintf("0x%08x", ha.QuestFlags), fmt.Sprintf("0x%08x", hb.QuestFlags))
+
+	ra, rb := rewardSlots(ha), rewardSlots(hb)
+	for i := range ra {
+		d.add(fmt.Sprintf("Reward slot %d item", i), rewardCode(ra[i].code), rewardCode(rb[i].code))
+		d.add(fmt.Sprintf("Reward slot %d count", i), ra[i].count, rb[i].count)
+	}
+
+	d.add("EXP", ha.EXP, hb.EXP)
+	d.add("Woonz", ha.Woonz, hb.Woonz)
+	d.add("Lore", ha.Lore, hb.Lore)
+
+	if opts.IncludePadding {
+		var ba, bb bytes.Buffer
+		_ = binary.Write(&ba, binary.LittleEndian, ha)
+		_ = binary.Write(&bb, binary.LittleEndian, hb)
+		d.bytes("Header", ba.Bytes(), bb.Bytes(), headerFields[:])
+	}
+
+	for i := range a.Objectives {
+		d.objective(i, &a.Objectives[i], &b.Objectives[i], opts)
+	}
+
+	for i := range a.Continuation {
+		d.add(fmt.Sprintf("Continuation %d", i), continuationTarget(a.Continuation[i]), continuationTarget(b.Continuation[i]))
+	}
+
+	return d.lines
+}
+
+// differ collects the lines of a diff.
+type differ struct {
+	lines []string
+}
+
+// add records field if a and b differ.
+func (d *differ) add(field string, a, b any) {
+	if a != b {
+		d.lines = append(d.lines, fmt.Sprintf("%s: %v -> %v", field, a, b))
+	}
+}
+
+// bytes records every differing byte of a and b that decoded does not mark.
+func (d *differ) bytes(prefix string, a, b []byte, decoded []bool) {
+	for i := range a {
+		if !decoded[i] && a[i] != b[i] {
+			d.lines = append(d.lines, fmt.Sprintf("%s byte %d: 0x%02x -> 0x%02x", prefix, i, a[i], b[i]))
+		}
+	}
+}
+
+// objective records the differences between objective slot i of two quests.
+func (d *differ) objective(i int, a, b *Objective, opts DiffOptions) {
+	prefix := fmt.Sprintf("Objective %d", i)
+	d.add(prefix+" type", a.ObjectiveType(), b.ObjectiveType())
+
+	var decoded [ObjectiveBlockSize]bool
+	for off := 0; off < ObjectiveBlockSize; off += 4 {
+		f, ok := objectiveFields[off]
+		if !ok {
+			continue
+		}
+
+		for j := range f.size {
+			decoded[off+j] = true
+		}
+
+		if off == OffType || off == OffNameLen {
+			continue
+		}
+
+		if f.size == 2 {
+			d.add(prefix+" "+f.name, binary.LittleEndian.Uint16(a.Block[off:]), binary.LittleEndian.Uint16(b.Block[off:]))
+		} else {
+			d.add(prefix+" "+f.name, a.Block[off], b.Block[off])
+		}
+	}
+
+	if !bytes.Equal(a.Name, b.Name) {
+		d.lines = append(d.lines, fmt.Sprintf("%s name: %q -> %q", prefix, displayName(a.Name), displayName(b.Name)))
+	}
+
+	if opts.IncludePadding {
+		d.bytes(prefix, a.Block[:], b.Block[:], decoded[:])
+	}
+}
+
+// rewardCode formats a reward item code for Diff, naming the unused sentinel.
+func rewardCode(code uint16) string {
+	if code == UnusedRewardItemCode {
+		return "unused"
+	}
+
+	return fmt.Sprint(code)
+}
+
+// continuationTarget formats a continuation slot for Diff, naming the unused
+// sentinel.
+func continuationTarget(c uint32) string {
+	if c == UnusedContinuation {
+		return "unused"
+	}
+
+	return fmt.Sprint(c)
+}
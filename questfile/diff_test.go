@@ -0,0 +1,50 @@
+package questfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_Equal(t *testing.T) {
+	assert.Nil(t, Diff(namedQuest(1), namedQuest(1)))
+}
+
+func TestDiff_Fields(t *testing.T) {
+	a := namedQuest(1)
+	b := namedQuest(1)
+	b.Header.MinLevel = 12
+	b.Header.QuestFlags = FlagBit0
+	require.NoError(t, b.Header.SetReward(1, 501, 3))
+	b.Objectives[2].Block[OffType] = byte(TypeDROP)
+	require.NoError(t, b.Objectives[1].SetName("Deep Cave"))
+	require.NoError(t, b.SetContinuation(0, 2001))
+
+	assert.Equal(t, []string{
+		"MinLevel: 10 -> 12",
+		"QuestFlags: 0x00000000 -> 0x00000001",
+		"Reward slot 1 item: unused -> 501",
+		"Reward slot 1 count: 0 -> 3",
+		`Objective 1 name: "Hidden Cave" -> "Deep Cave"`,
+		"Objective 2 type: KILL -> DROP",
+		"Continuation 0: unused -> 2001",
+	}, Diff(a, b))
+}
+
+func TestDiff_Padding(t *testing.T) {
+	a := namedQuest(1)
+	b := namedQuest(1)
+	b.Header.QuestIDRaw[3] = 0x01
+	b.Header.HeaderTail[2] = 0x02
+	b.Objectives[3].Block[41] = 0x12
+	b.Objectives[3].Block[OffMapID+2] = 0x34
+
+	assert.Nil(t, Diff(a, b), "padding is ignored by default")
+	assert.Equal(t, []string{
+		"Header byte 3: 0x00 -> 0x01",
+		"Header byte 94: 0x00 -> 0x02",
+		"Objective 3 byte 6: 0x00 -> 0x34",
+		"Objective 3 byte 41: 0x00 -> 0x12",
+	}, DiffWith(a, b, DiffOptions{IncludePadding: true}))
+}
@@ -0,0 +1,114 @@
+package questfile
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/project-agonyl/agonyl-utils-go/utils"
+)
+
+// String returns the dump Dump writes for q.
+func (q *QuestFile) String() string {
+	var b strings.Builder
+	_ = q.Dump(&b)
+	return b.String()
+}
+
+// Dump writes a human-readable rendering of q to w for CLI inspection: the
+// quest and NPC IDs, level range, EXP, Woonz, Lore, flags and rewards, then
+// one line per active objective with its type name, the fields that type
+// uses and its name, and finally the continuation. Unused reward slots,
+// objective slots and continuations are summarized in a single line each
+// instead of being listed. Names are decoded from CP949, falling back to the
+// raw bytes. The output is meant for people; its layout may change.
+func (q *QuestFile) Dump(w io.Writer) error {
+	h := &q.Header
+	var b strings.Builder
+	fmt.Fprintf(&b, "quest %d: given by NPC %d, target NPC %d\n", h.QuestID(), h.GivenNPCID(), h.TargetNPCID())
+	fmt.Fprintf(&b, "levels %d-%d\n", h.MinLevel, h.MaxLevel)
+	fmt.Fprintf(&b, "exp %d, woonz %d, lore %d\n", h.EXP, h.Woonz, h.Lore)
+	fmt.Fprintf(&b, "flags 0x%08x\n", h.QuestFlags)
+
+	if len(h.Rewards()) == 0 {
+		b.WriteString("rewards: none\n")
+	}
+
+	for i, r := range rewardSlots(h) {
+		if r.code != UnusedRewardItemCode {
+			fmt.Fprintf(&b, "reward %d: item %d x%d\n", i, r.code, r.count)
+		}
+	}
+
+	var unused []string
+	for i := range q.Objectives {
+		o := &q.Objectives[i]
+		if o.IsUnused() {
+			unused = append(unused, strconv.Itoa(i))
+			continue
+		}
+
+		fmt.Fprintf(&b, "objective %d: %s%s\n", i, o.TypeName(), objectiveFieldsSummary(o))
+	}
+
+	if len(unused) > 0 {
+		fmt.Fprintf(&b, "unused objectives: %s\n", strings.Join(unused, ", "))
+	}
+
+	next := q.ContinuationQuests()
+	if len(next) == 0 {
+		b.WriteString("continuation: none\n")
+	} else {
+		ids := make([]string, len(next))
+		for i, id := range next {
+			ids[i] = strconv.FormatUint(uint64(id), 10)
+		}
+
+		fmt.Fprintf(&b, "continuation: %s\n", strings.Join(ids, ", "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// objectiveFieldsSummary renders the fields o's type uses, each preceded by a
+// space, followed by its name if it has one.
+func objectiveFieldsSummary(o *Objective) string {
+	u16 := func(off int) uint16 { return binary.LittleEndian.Uint16(o.Block[off:]) }
+
+	var s string
+	switch o.ObjectiveType() {
+	case TypeKILL:
+		s = fmt.Sprintf(" map %d location %d radius %d monster %d count %d",
+			o.MapID(), o.LocationID(), o.Radius(), o.MonsterID(), o.KillCount())
+	case TypeQUESTITEM:
+		s = fmt.Sprintf(" item %d count %d", u16(OffQuestItemID), u16(OffItemCount))
+	case TypeBRINGNPC:
+		s = fmt.Sprintf(" npc %d", o.MonsterID())
+	case TypeDROP:
+		s = fmt.Sprintf(" monster %d item %d count %d rates %d/%d/%d",
+			o.MonsterID(), u16(OffQuestItemID), u16(OffItemCount),
+			o.Block[OffDropRate1], o.Block[OffDropRate2], o.Block[OffDropRate3])
+	case TypeFIND:
+		s = fmt.Sprintf(" map %d location %d radius %d", o.MapID(), o.LocationID(), o.Radius())
+	}
+
+	if len(o.Name) > 0 {
+		s += fmt.Sprintf(" name %q", displayName(o.Name))
+	}
+
+	return s
+}
+
+// displayName decodes an objective name from CP949 for display, falling back
+// to the raw bytes if they do not decode.
+func displayName(name []byte) string {
+	s, err := utils.DecodeCP949(name)
+	if err != nil {
+		return string(name)
+	}
+
+	return s
+}
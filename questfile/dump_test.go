@@ -0,0 +1,41 @@
+package questfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDump(t *testing.T) {
+	q := NewQuestFile()
+	q.Header.SetQuestID(12)
+	q.Header.SetGivenNPCID(100)
+	q.Header.SetTargetNPCID(55)
+	q.Header.MinLevel, q.Header.MaxLevel = 10, 50
+	q.Header.EXP, q.Header.Woonz, q.Header.Lore = 1000, 500, 100
+	q.Header.QuestFlags = FlagBit0 | FlagBit2 | 0x100
+	require.NoError(t, q.Header.SetReward(1, 501, 3))
+	require.NoError(t, q.Objectives[0].SetKillTarget(3, 7, 5))
+	q.Objectives[1].SetFindTarget(1, 2, 3)
+	require.NoError(t, q.Objectives[1].SetName("Hidden Cave"))
+	require.NoError(t, q.SetContinuation(0, 2001))
+
+	want := `quest 12: given by NPC 100, target NPC 55
+levels 10-50
+exp 1000, woonz 500, lore 100
+flags 0x00000105
+reward 1: item 501 x3
+objective 0: KILL map 3 location 65535 radius 255 monster 7 count 5
+objective 1: FIND map 1 location 2 radius 3 name "Hidden Cave"
+unused objectives: 2, 3, 4, 5, 6
+continuation: 2001
+`
+	assert.Equal(t, want, q.String())
+}
+
+func TestDump_Empty(t *testing.T) {
+	q := NewQuestFile()
+	assert.Contains(t, q.String(), "flags 0x00000000\nrewards: none\n")
+	assert.Contains(t, q.String(), "unused objectives: 0, 1, 2, 3, 4, 5, 6\ncontinuation: none\n")
+}
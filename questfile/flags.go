@@ -0,0 +1,56 @@
+package questfile
+
+// Masks for the low bits of QuestHeader.QuestFlags, for use with HasFlag,
+// SetFlag and ClearFlag. No bit of QuestFlags has a confirmed meaning yet, so
+// the masks are named by position only; give a bit a descriptive name, and a
+// field in QuestFlagSet, once its effect has been confirmed in the client or
+// server.
+const (
+	FlagBit0 uint32 = 1 << 0
+	FlagBit1 uint32 = 1 << 1
+	FlagBit2 uint32 = 1 << 2
+
+	// knownFlags holds the bits QuestFlagSet decodes into named fields. It is
+	// empty until a bit's meaning is confirmed.
+	knownFlags uint32 = 0
+)
+
+// QuestFlagSet is a named view of QuestHeader.QuestFlags for editors. No bit
+// is understood yet, so every bit is carried in RawUnknown; confirmed bits
+// will get named fields and leave RawUnknown.
+type QuestFlagSet struct {
+	RawUnknown uint32 // every bit not decoded into a named field, as stored
+}
+
+// DecodeFlags splits QuestFlags into the known named bits and the raw
+// remainder.
+func (h *QuestHeader) DecodeFlags() QuestFlagSet {
+	return QuestFlagSet{
+		RawUnknown: h.QuestFlags &^ knownFlags,
+	}
+}
+
+// ApplyFlags rebuilds QuestFlags from f. Known bits set in f.RawUnknown are
+// ignored, so the named fields always decide them; all other bits of
+// RawUnknown are stored as-is. ApplyFlags(DecodeFlags()) leaves QuestFlags
+// unchanged.
+func (h *QuestHeader) ApplyFlags(f QuestFlagSet) {
+	h.QuestFlags = f.RawUnknown &^ knownFlags
+}
+
+// HasFlag reports whether every bit of mask is set in QuestFlags.
+func (h *QuestHeader) HasFlag(mask uint32) bool {
+	return h.QuestFlags&mask == mask
+}
+
+// SetFlag sets the bits of mask in QuestFlags, leaving the other bits
+// unchanged.
+func (h *QuestHeader) SetFlag(mask uint32) {
+	h.QuestFlags |= mask
+}
+
+// ClearFlag clears the bits of mask in QuestFlags, leaving the other bits
+// unchanged.
+func (h *QuestHeader) ClearFlag(mask uint32) {
+	h.QuestFlags &^= mask
+}
@@ -0,0 +1,44 @@
+package questfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeFlags(t *testing.T) {
+	h := QuestHeader{QuestFlags: FlagBit0 | FlagBit2 | 0x80000010}
+	assert.Equal(t, QuestFlagSet{
+		RawUnknown: FlagBit0 | FlagBit2 | 0x80000010,
+	}, h.DecodeFlags(), "bits without a confirmed meaning stay in RawUnknown")
+}
+
+func TestApplyFlags_RoundTrip(t *testing.T) {
+	for _, flags := range []uint32{0, 0xFFFFFFFF, FlagBit1, 0xDEADBEEF} {
+		h := QuestHeader{QuestFlags: flags}
+		h.ApplyFlags(h.DecodeFlags())
+		assert.Equal(t, flags, h.QuestFlags, "flags 0x%08X", flags)
+	}
+}
+
+func TestApplyFlags_PreservesUnknownBits(t *testing.T) {
+	h := QuestHeader{QuestFlags: 0x00F00000 | FlagBit0}
+	f := h.DecodeFlags()
+	f.RawUnknown = f.RawUnknown&^FlagBit0 | FlagBit1
+	h.ApplyFlags(f)
+	assert.Equal(t, 0x00F00000|FlagBit1, h.QuestFlags)
+}
+
+func TestFlagHelpers(t *testing.T) {
+	h := QuestHeader{QuestFlags: 0x80000000}
+	h.SetFlag(FlagBit1)
+	assert.True(t, h.HasFlag(FlagBit1))
+	assert.False(t, h.HasFlag(FlagBit0))
+	assert.False(t, h.HasFlag(FlagBit1|FlagBit0), "every bit of the mask must be set")
+
+	h.SetFlag(FlagBit0)
+	assert.True(t, h.HasFlag(FlagBit1|FlagBit0))
+
+	h.ClearFlag(FlagBit1)
+	assert.Equal(t, 0x80000000|FlagBit0, h.QuestFlags, "other bits are preserved")
+}
@@ -0,0 +1,136 @@
+package questfile
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gzipMagic is the two-byte signature every gzip stream starts with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ReadGzip reads a gzip-compressed quest file from r. The decompressed data
+// must be exactly one quest file: data after the continuation is reported as
+// ErrTrailingBytes, as with Read. Errors from the gzip layer, such as
+// gzip.ErrHeader for data that is not gzip or gzip.ErrChecksum for a corrupt
+// stream, are returned unchanged.
+func ReadGzip(r io.Reader) (QuestFile, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		if err == io.EOF {
+			return QuestFile{}, io.ErrUnexpectedEOF
+		}
+
+		return QuestFile{}, err
+	}
+
+	q, err := Read(zr)
+	if err != nil {
+		return QuestFile{}, err
+	}
+
+	// Read ignores the error of its final probe for trailing bytes, which is
+	// where the gzip reader reports a bad checksum or a truncated trailer.
+	// The error is sticky, so probing again surfaces it.
+	var probe [1]byte
+	n, err := zr.Read(probe[:])
+	if n > 0 {
+		return QuestFile{}, ErrTrailingBytes
+	}
+
+	if err != nil && err != io.EOF {
+		return QuestFile{}, err
+	}
+
+	return q, nil
+}
+
+// WriteGzip writes q to w as a gzip-compressed quest file, the inverse of
+// ReadGzip.
+func WriteGzip(w io.Writer, q QuestFile) error {
+	zw := gzip.NewWriter(w)
+	if err := Write(zw, q); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// ReadFile reads the quest file at path. The file is decompressed with
+// ReadGzip if path ends in ".gz" or the data starts with a valid gzip header;
+// otherwise it is read with Read. A plain quest file whose first two bytes
+// happen to match the gzip signature is still read as plain.
+func ReadFile(path string) (QuestFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return QuestFile{}, err
+	}
+
+	if strings.HasSuffix(path, ".gz") {
+		return ReadGzip(bytes.NewReader(data))
+	}
+
+	if bytes.HasPrefix(data, gzipMagic) {
+		q, err := ReadGzip(bytes.NewReader(data))
+		if !errors.Is(err, gzip.ErrHeader) {
+			return q, err
+		}
+	}
+
+	return Read(bytes.NewReader(data))
+}
+
+// WriteFile writes q to the file at path, the inverse of ReadFile: the quest
+// is gzip-compressed with WriteGzip if path ends in ".gz" and written with
+// Write otherwise. The data goes through a buffered writer into a temporary
+// file in the same directory, which is synced and then renamed over path, so
+// a crash or error mid-write never leaves a truncated quest behind; on error
+// the temporary file is removed and any existing file at path is untouched.
+// The file is given mode 0644.
+func WriteFile(path string, q QuestFile) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	bw := bufio.NewWriter(f)
+	if strings.HasSuffix(path, ".gz") {
+		err = WriteGzip(bw, q)
+	} else {
+		err = Write(bw, q)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+
+	if err = f.Chmod(0o644); err != nil {
+		return err
+	}
+
+	if err = f.Sync(); err != nil {
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,132 @@
+package questfile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteGzip_ReadGzip_RoundTrip(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[3].Block[OffType] = byte(TypeDROP)
+	q.Objectives[3].Block[OffNameLen] = 4
+	q.Objectives[3].Name = []byte("Wolf")
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteGzip(&buf, q))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), gzipMagic))
+
+	got, err := ReadGzip(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, q, got)
+}
+
+func TestReadGzip_TrailingBytesInsideStream(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	require.NoError(t, Write(zw, minimalValidQuestFile()))
+	_, err := zw.Write([]byte{0x00})
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	_, err = ReadGzip(&buf)
+	assert.ErrorIs(t, err, ErrTrailingBytes)
+}
+
+func TestReadGzip_Errors(t *testing.T) {
+	_, err := ReadGzip(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	var plain bytes.Buffer
+	require.NoError(t, Write(&plain, minimalValidQuestFile()))
+	_, err = ReadGzip(&plain)
+	assert.ErrorIs(t, err, gzip.ErrHeader)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteGzip(&buf, minimalValidQuestFile()))
+	data := buf.Bytes()
+	data[len(data)-8] ^= 0xFF // CRC-32 in the gzip trailer
+	_, err = ReadGzip(bytes.NewReader(data))
+	assert.ErrorIs(t, err, gzip.ErrChecksum)
+
+	_, err = ReadGzip(bytes.NewReader(buf.Bytes()[:buf.Len()/2]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReadFile_DetectsGzip(t *testing.T) {
+	q := minimalValidQuestFile()
+	dir := t.TempDir()
+
+	var plain, compressed bytes.Buffer
+	require.NoError(t, Write(&plain, q))
+	require.NoError(t, WriteGzip(&compressed, q))
+
+	files := map[string][]byte{
+		"plain.dat":    plain.Bytes(),
+		"quest.dat.gz": compressed.Bytes(),
+		"magic.dat":    compressed.Bytes(), // no .gz suffix, detected by magic
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+		got, err := ReadFile(path)
+		require.NoError(t, err, name)
+		assert.Equal(t, q, got, name)
+	}
+}
+
+func TestReadFile_PlainWithGzipSignature(t *testing.T) {
+	// Quest ID 0x8B1F encodes as 1F 8B, the gzip magic, but the padding bytes
+	// after it are not a gzip header.
+	q := minimalValidQuestFile()
+	q.Header.SetQuestID(0x8B1F)
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+
+	path := filepath.Join(t.TempDir(), "quest.dat")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	got, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, q, got)
+}
+
+func TestReadFile_Missing(t *testing.T) {
+	_, err := ReadFile(filepath.Join(t.TempDir(), "missing.dat"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestWriteFile_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	q := namedQuest(7)
+	for _, name := range []string{"quest.dat", "quest.dat.gz"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("old contents"), 0o600))
+		require.NoError(t, WriteFile(path, q), name)
+		got, err := ReadFile(path)
+		require.NoError(t, err, name)
+		assert.Equal(t, q, got, name)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o644), info.Mode().Perm(), name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "quest.dat.gz"))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, gzipMagic), ".gz paths are compressed")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "no temporary files are left behind")
+}
+
+func TestWriteFile_MissingDir(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "quest.dat")
+	assert.ErrorIs(t, WriteFile(path, minimalValidQuestFile()), os.ErrNotExist)
+}
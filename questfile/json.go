@@ -0,0 +1,211 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/project-agonyl/agonyl-utils-go/utils"
+)
+
+// questJSON is the JSON form of a QuestFile. The friendly fields decode the
+// documented header values; HeaderRaw and each objective's Block carry every
+// byte, padding and unknown regions included, as hex.
+type questJSON struct {
+	QuestID      uint16                       `json:"questId"`
+	GivenNPCID   uint16                       `json:"givenNpcId"`
+	MinLevel     uint8                        `json:"minLevel"`
+	MaxLevel     uint8                        `json:"maxLevel"`
+	Flags        uint32                       `json:"flags"`
+	Rewards      [3]RewardItem                `json:"rewards"`
+	EXP          uint32                       `json:"exp"`
+	Woonz        uint32                       `json:"woonz"`
+	Lore         uint32                       `json:"lore"`
+	HeaderRaw    string                       `json:"headerRaw"`
+	Objectives   [NumObjectives]objectiveJSON `json:"objectives"`
+	Continuation [3]uint32                    `json:"continuation"`
+}
+
+// objectiveJSON is the JSON form of an Objective. NameHex is only set when
+// Name cannot reproduce the stored bytes.
+type objectiveJSON struct {
+	Type    string `json:"type"`
+	Name    string `json:"name,omitempty"`
+	NameHex string `json:"nameHex,omitempty"`
+	Block   string `json:"block"`
+}
+
+// MarshalJSON encodes q for editors and web tools. Quest and NPC IDs, levels,
+// flags, reward codes and counts, EXP, Woonz, Lore and the continuation are
+// emitted as plain fields, each objective's type by name and its name decoded
+// from CP949. Every byte of the header and of each objective block is also
+// emitted as hex ("headerRaw", "block"), so padding and regions whose meaning
+// is not yet known survive. A name that does not survive CP949 decoding is
+// emitted as "nameHex" as well. Decoding the result with UnmarshalJSON and
+// writing it with Write produces the same bytes as writing q.
+func (q QuestFile) MarshalJSON() ([]byte, error) {
+	var header bytes.Buffer
+	if err := binary.Write(&header, binary.LittleEndian, &q.Header); err != nil {
+		return nil, err
+	}
+
+	h := &q.Header
+	v := questJSON{
+		QuestID:      h.QuestID(),
+		GivenNPCID:   h.GivenNPCID(),
+		MinLevel:     h.MinLevel,
+		MaxLevel:     h.MaxLevel,
+		Flags:        h.QuestFlags,
+		EXP:          h.EXP,
+		Woonz:        h.Woonz,
+		Lore:         h.Lore,
+		HeaderRaw:    hex.EncodeToString(header.Bytes()),
+		Continuation: q.Continuation,
+	}
+
+	for i, s := range rewardSlots(h) {
+		v.Rewards[i] = RewardItem{Code: s.code, Count: s.count}
+	}
+
+	for i := range q.Objectives {
+		o := &q.Objectives[i]
+		oj := objectiveJSON{
+			Type:  o.ObjectiveType().String(),
+			Block: hex.EncodeToString(o.Block[:]),
+		}
+
+		if len(o.Name) > 0 {
+			name, err := utils.DecodeCP949(o.Name)
+			encoded, encErr := utils.EncodeCP949(name)
+			if err != nil || encErr != nil || !bytes.Equal(encoded, o.Name) {
+				oj.NameHex = hex.EncodeToString(o.Name)
+			}
+
+			oj.Name = name
+		}
+
+		v.Objectives[i] = oj
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes JSON produced by MarshalJSON into q. The raw bytes
+// are decoded first and the plain fields are then written over them, so an
+// edit to a plain field wins over the raw bytes it covers; "headerRaw",
+// "block" and "type" may be omitted, leaving those bytes zero or, for the
+// type, as the block has it. An objective's name is
+// taken from "nameHex" when present and otherwise encoded to CP949 from
+// "name", and its name-length byte is set to match. Malformed hex, a raw
+// field of the wrong length, an unknown objective type or a name longer than
+// MaxNameLength is an error.
+func (q *QuestFile) UnmarshalJSON(data []byte) error {
+	var v questJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var out QuestFile
+	if v.HeaderRaw != "" {
+		raw, err := decodeHexField("headerRaw", v.HeaderRaw, HeaderSize)
+		if err != nil {
+			return err
+		}
+
+		if err := binary.Read(bytes.NewReader(raw), binary.LittleEndian, &out.Header); err != nil {
+			return err
+		}
+	}
+
+	h := &out.Header
+	h.SetQuestID(v.QuestID)
+	h.SetGivenNPCID(v.GivenNPCID)
+	h.MinLevel, h.MaxLevel = v.MinLevel, v.MaxLevel
+	h.QuestFlags = v.Flags
+	h.EXP, h.Woonz, h.Lore = v.EXP, v.Woonz, v.Lore
+	binary.LittleEndian.PutUint16(h.RewardSlot1[:2], v.Rewards[0].Code)
+	binary.LittleEndian.PutUint16(h.RewardSlot2[:2], v.Rewards[1].Code)
+	binary.LittleEndian.PutUint16(h.RewardSlot3[:2], v.Rewards[2].Code)
+	h.Count1, h.Count2, h.Count3 = v.Rewards[0].Count, v.Rewards[1].Count, v.Rewards[2].Count
+
+	for i, oj := range v.Objectives {
+		o := &out.Objectives[i]
+		if oj.Block != "" {
+			raw, err := decodeHexField("block", oj.Block, ObjectiveBlockSize)
+			if err != nil {
+				return &ObjectiveError{Index: i, Err: err}
+			}
+
+			copy(o.Block[:], raw)
+		}
+
+		var err error
+		if oj.Type != "" {
+			var t ObjectiveType
+			if t, err = parseObjectiveType(oj.Type); err != nil {
+				return &ObjectiveError{Index: i, Err: err}
+			}
+
+			o.Block[OffType] = byte(t)
+		}
+
+		switch {
+		case oj.NameHex != "":
+			o.Name, err = hex.DecodeString(oj.NameHex)
+		case oj.Name != "":
+			o.Name, err = utils.EncodeCP949(oj.Name)
+		}
+
+		if err != nil {
+			return &ObjectiveError{Index: i, Err: err}
+		}
+
+		if len(o.Name) > MaxNameLength {
+			return &ObjectiveError{Index: i, Err: ErrNameTooLong}
+		}
+
+		o.Block[OffNameLen] = uint8(len(o.Name))
+	}
+
+	out.Continuation = v.Continuation
+	*q = out
+	return nil
+}
+
+// decodeHexField decodes the hex string s of the named JSON field, which must
+// hold exactly size bytes.
+func decodeHexField(field, s string, size int) ([]byte, error) {
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("questfile: %s: %w", field, err)
+	}
+
+	if len(raw) != size {
+		return nil, fmt.Errorf("questfile: %s: %d bytes, want %d", field, len(raw), size)
+	}
+
+	return raw, nil
+}
+
+// parseObjectiveType is the inverse of ObjectiveType.String.
+func parseObjectiveType(s string) (ObjectiveType, error) {
+	for _, t := range []ObjectiveType{TypeKILL, TypeQUESTITEM, TypeBRINGNPC, TypeDROP, TypeFIND, TypeUnused} {
+		if s == t.String() {
+			return t, nil
+		}
+	}
+
+	if n, ok := strings.CutPrefix(s, "ObjectiveType("); ok {
+		if n, ok := strings.CutSuffix(n, ")"); ok {
+			if v, err := strconv.ParseUint(n, 10, 8); err == nil {
+				return ObjectiveType(v), nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrInvalidObjectiveType, s)
+}
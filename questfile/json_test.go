@@ -0,0 +1,95 @@
+package questfile
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/project-agonyl/agonyl-utils-go/utils"
+)
+
+func TestJSON_RoundTripPreservesBytes(t *testing.T) {
+	q := namedQuest(7)
+	q.Header.QuestIDRaw[2], q.Header.QuestIDRaw[3] = 0xAB, 0xCD
+	q.Header.RewardSlot1[3] = 0x11
+	q.Header.HeaderTail[0] = 0x42
+	q.Objectives[0].Block[50] = 0x99
+	q.Objectives[2].Block[OffType] = 9
+	q.Continuation[0] = 12
+
+	data, err := json.Marshal(q)
+	require.NoError(t, err)
+
+	var got QuestFile
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, encodeQuest(t, q), encodeQuest(t, got))
+}
+
+func TestJSON_FriendlyFields(t *testing.T) {
+	q := namedQuest(7)
+	q.Objectives[1].Name, _ = utils.EncodeCP949("동굴")
+	q.Objectives[1].Block[OffNameLen] = byte(len(q.Objectives[1].Name))
+
+	data, err := json.Marshal(q)
+	require.NoError(t, err)
+
+	var v map[string]any
+	require.NoError(t, json.Unmarshal(data, &v))
+	assert.EqualValues(t, 7, v["questId"])
+	assert.EqualValues(t, 100, v["givenNpcId"])
+	objectives := v["objectives"].([]any)
+	assert.Equal(t, "KILL", objectives[0].(map[string]any)["type"])
+	assert.Equal(t, "FIND", objectives[1].(map[string]any)["type"])
+	assert.Equal(t, "동굴", objectives[1].(map[string]any)["name"])
+	assert.NotContains(t, objectives[1], "nameHex")
+}
+
+func TestJSON_FriendlyFieldEditWins(t *testing.T) {
+	q := namedQuest(7)
+	q.Header.QuestIDRaw[2] = 0xAB
+
+	data, err := json.Marshal(q)
+	require.NoError(t, err)
+
+	var v map[string]any
+	require.NoError(t, json.Unmarshal(data, &v))
+	v["questId"] = 8
+	v["objectives"].([]any)[1].(map[string]any)["name"] = "Deep Cave"
+	data, err = json.Marshal(v)
+	require.NoError(t, err)
+
+	var got QuestFile
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, uint16(8), got.Header.QuestID())
+	assert.Equal(t, byte(0xAB), got.Header.QuestIDRaw[2])
+	assert.Equal(t, []byte("Deep Cave"), got.Objectives[1].Name)
+	assert.Equal(t, byte(len("Deep Cave")), got.Objectives[1].Block[OffNameLen])
+}
+
+func TestJSON_UndecodableNameUsesHex(t *testing.T) {
+	q := namedQuest(7)
+	q.Objectives[1].Name = []byte{0xFF, 0xFE, 0x41}
+	q.Objectives[1].Block[OffNameLen] = 3
+
+	data, err := json.Marshal(q)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"nameHex":"fffe41"`)
+
+	var got QuestFile
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, q.Objectives[1].Name, got.Objectives[1].Name)
+}
+
+func TestJSON_UnmarshalErrors(t *testing.T) {
+	var q QuestFile
+	err := json.Unmarshal([]byte(`{"headerRaw":"00"}`), &q)
+	assert.ErrorContains(t, err, "headerRaw: 1 bytes, want 96")
+
+	err = json.Unmarshal([]byte(`{"objectives":[{"type":"BOGUS"}]}`), &q)
+	assert.ErrorIs(t, err, ErrInvalidObjectiveType)
+	var oe *ObjectiveError
+	require.ErrorAs(t, err, &oe)
+	assert.Equal(t, 0, oe.Index)
+}
@@ -0,0 +1,76 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxFileSize is the largest encoded quest file: MinFileSize plus a
+// MaxNameLength name on every objective.
+const MaxFileSize = MinFileSize + NumObjectives*MaxNameLength // 2565
+
+// Size returns the number of bytes Write produces for q: MinFileSize plus
+// the length of every objective's Name. It is computed without encoding, for
+// pre-allocating buffers and reporting file sizes.
+func (q *QuestFile) Size() int {
+	n := MinFileSize
+	for i := range q.Objectives {
+		n += len(q.Objectives[i].Name)
+	}
+
+	return n
+}
+
+// ErrLengthMismatch is returned by ReadWithLength when the length prefix does
+// not match the size of the quest that follows it.
+var ErrLengthMismatch = errors.New("questfile: length prefix does not match quest")
+
+// WriteWithLength writes q to w preceded by its encoded length as a
+// little-endian uint32. The quest is encoded before anything is written, so
+// the prefix is computed from the actual bytes and cannot drift from them. It
+// returns the total number of bytes written, prefix included.
+func WriteWithLength(w io.Writer, q QuestFile) (int, error) {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 4))
+	if err := Write(&buf, q); err != nil {
+		return 0, err
+	}
+
+	b := buf.Bytes()
+	binary.LittleEndian.PutUint32(b, uint32(len(b)-4))
+	return w.Write(b)
+}
+
+// ReadWithLength reads a quest written by WriteWithLength. The prefix bounds
+// the read: exactly that many bytes are consumed from r, so r is left at the
+// next record even when the quest inside is bad. It returns
+// ErrLengthMismatch if the prefix is below MinFileSize or above MaxFileSize,
+// or if the quest ends before or after the prefixed length, and
+// io.ErrUnexpectedEOF if r ends before the prefixed length. Other errors are
+// those of Read.
+func ReadWithLength(r io.Reader) (QuestFile, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return QuestFile{}, unexpectedEOF(err)
+	}
+
+	n := binary.LittleEndian.Uint32(prefix[:])
+	if n < MinFileSize || n > MaxFileSize {
+		return QuestFile{}, fmt.Errorf("%w: prefix %d outside [%d, %d]", ErrLengthMismatch, n, MinFileSize, MaxFileSize)
+	}
+
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return QuestFile{}, unexpectedEOF(err)
+	}
+
+	q, _, err := read(bytes.NewReader(payload), false, false)
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, ErrTrailingBytes) {
+		return QuestFile{}, fmt.Errorf("%w: prefix %d: %v", ErrLengthMismatch, n, err)
+	}
+
+	return q, err
+}
@@ -0,0 +1,90 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteWithLength_RoundTrip(t *testing.T) {
+	a, b := namedQuest(7), minimalValidQuestFile()
+	var buf bytes.Buffer
+	n, err := WriteWithLength(&buf, a)
+	require.NoError(t, err)
+	assert.Equal(t, 4+MinFileSize+len("Hidden Cave"), n)
+	assert.Equal(t, uint32(MinFileSize+len("Hidden Cave")), binary.LittleEndian.Uint32(buf.Bytes()))
+	_, err = WriteWithLength(&buf, b)
+	require.NoError(t, err)
+
+	got, err := ReadWithLength(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, a, got)
+	got, err = ReadWithLength(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, b, got)
+	assert.Zero(t, buf.Len())
+}
+
+func TestReadWithLength_Mismatch(t *testing.T) {
+	var quest bytes.Buffer
+	require.NoError(t, Write(&quest, namedQuest(7)))
+	raw := quest.Bytes()
+
+	withPrefix := func(n uint32, payload []byte) io.Reader {
+		b := binary.LittleEndian.AppendUint32(nil, n)
+		return bytes.NewReader(append(b, payload...))
+	}
+
+	// Prefix shorter than the quest: the quest runs past it.
+	_, err := ReadWithLength(withPrefix(uint32(len(raw)-1), raw))
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+
+	// Prefix longer than the quest: bytes are left over inside it.
+	_, err = ReadWithLength(withPrefix(uint32(len(raw)+1), append(bytes.Clone(raw), 0)))
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+
+	// Prefix outside the possible sizes is rejected before reading.
+	_, err = ReadWithLength(withPrefix(0xFFFFFFFF, nil))
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+	_, err = ReadWithLength(withPrefix(10, nil))
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestReadWithLength_Truncated(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := WriteWithLength(&buf, namedQuest(7))
+	require.NoError(t, err)
+	data := buf.Bytes()
+
+	for _, n := range []int{0, 2, 4, len(data) - 1} {
+		_, err := ReadWithLength(bytes.NewReader(data[:n]))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "%d bytes", n)
+		assert.NotErrorIs(t, err, ErrLengthMismatch, "%d bytes", n)
+	}
+}
+
+func TestReadWithLength_TrailingInsidePrefix(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := WriteWithLength(&buf, minimalValidQuestFile())
+	require.NoError(t, err)
+	b := buf.Bytes()
+	binary.LittleEndian.PutUint32(b, binary.LittleEndian.Uint32(b)+1)
+	b = append(b, 0x00)
+
+	_, err = ReadWithLength(bytes.NewReader(b))
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+}
+
+func TestSize_MatchesWrite(t *testing.T) {
+	for _, q := range []QuestFile{minimalValidQuestFile(), namedQuest(2), NewQuestFile()} {
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, q))
+		assert.Equal(t, buf.Len(), q.Size())
+	}
+	q := namedQuest(2)
+	assert.Equal(t, MinFileSize+len("Hidden Cave"), q.Size())
+}
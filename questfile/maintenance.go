@@ -0,0 +1,160 @@
+package questfile
+
+import (
+	"cmp"
+	"encoding/binary"
+	"slices"
+)
+
+// MaxNameLength is the longest objective name the format can describe: the
+// length is stored in the single byte at OffNameLen.
+const MaxNameLength = 0xFF
+
+// supportsName reports whether objectives of type t may carry a name.
+func supportsName(t ObjectiveType) bool {
+	return t == TypeDROP || t == TypeFIND
+}
+
+// Normalize brings every objective's name-length byte in line with its Name.
+// For DROP and FIND objectives Block[OffNameLen] is set to len(Name); for all
+// other types a stray non-zero length byte is cleared. It returns
+// ErrNameTooLong if a name exceeds MaxNameLength and ErrNameLengthForType if
+// an objective that cannot carry a name has one, leaving that objective
+// untouched in both cases.
+func (q *QuestFile) Normalize() error {
+	for i := range q.Objectives {
+		o := &q.Objectives[i]
+		if !supportsName(o.ObjectiveType()) {
+			if len(o.Name) > 0 {
+				return &ObjectiveError{Index: i, Err: ErrNameLengthForType}
+			}
+
+			o.Name = nil
+			o.Block[OffNameLen] = 0
+			continue
+		}
+
+		if len(o.Name) > MaxNameLength {
+			return &ObjectiveError{Index: i, Err: ErrNameTooLong}
+		}
+
+		o.Block[OffNameLen] = uint8(len(o.Name))
+	}
+
+	return nil
+}
+
+// Canonicalize rewrites every unused objective slot into the canonical form
+// real files use: all bytes 0xFF except the four bytes from OffNameLen, which
+// are zero, and no name. Active objectives are not modified.
+func (q *QuestFile) Canonicalize() {
+	for i := range q.Objectives {
+		if q.Objectives[i].IsUnused() {
+			q.Objectives[i] = unusedObjective()
+		}
+	}
+}
+
+// CompactObjectives moves active objectives to the front of the objective
+// array, keeping their relative order, so that unused slots only appear after
+// the last active one. Objectives are moved whole, name included.
+func (q *QuestFile) CompactObjectives() {
+	var compacted [NumObjectives]Objective
+	n := 0
+	for i := range q.Objectives {
+		if q.Objectives[i].IsActive() {
+			compacted[n] = q.Objectives[i]
+			n++
+		}
+	}
+
+	for i := range q.Objectives {
+		if q.Objectives[i].IsUnused() {
+			compacted[n] = q.Objectives[i]
+			n++
+		}
+	}
+
+	q.Objectives = compacted
+}
+
+// ValidateObjectiveOrdering checks that active objectives occupy slots
+// 0..k-1 with every unused slot after them, as the client expects. It returns
+// an *ObjectiveError wrapping ErrObjectiveGap for the first active objective
+// that follows an unused slot, or nil. CompactObjectives fixes any gap it
+// reports. Validate does not include this check.
+func (q *QuestFile) ValidateObjectiveOrdering() error {
+	seenUnused := false
+	for i := range q.Objectives {
+		if q.Objectives[i].IsUnused() {
+			seenUnused = true
+			continue
+		}
+
+		if seenUnused {
+			return &ObjectiveError{Index: i, Err: ErrObjectiveGap}
+		}
+	}
+
+	return nil
+}
+
+// SortRewards orders the three reward slots by item code, ascending. Since
+// UnusedRewardItemCode is the largest code, used slots end up in slots
+// 0..k-1 with unused slots after them. Each slot moves together with its
+// count and with the padding bytes of both, so the rewards themselves are
+// unchanged; slots with equal codes keep their relative order.
+func (h *QuestHeader) SortRewards() {
+	type slot struct {
+		item     [4]byte
+		count    uint8
+		countPad [3]byte
+	}
+
+	slots := [3]slot{
+		{h.RewardSlot1, h.Count1, h.Count1Pad},
+		{h.RewardSlot2, h.Count2, h.Count2Pad},
+		{h.RewardSlot3, h.Count3, h.Count3Pad},
+	}
+
+	slices.SortStableFunc(slots[:], func(a, b slot) int {
+		return cmp.Compare(binary.LittleEndian.Uint16(a.item[:2]), binary.LittleEndian.Uint16(b.item[:2]))
+	})
+
+	h.RewardSlot1, h.Count1, h.Count1Pad = slots[0].item, slots[0].count, slots[0].countPad
+	h.RewardSlot2, h.Count2, h.Count2Pad = slots[1].item, slots[1].count, slots[1].countPad
+	h.RewardSlot3, h.Count3, h.Count3Pad = slots[2].item, slots[2].count, slots[2].countPad
+}
+
+// PrepareForExport runs the maintenance passes a quest needs before it is
+// written, in this order:
+//
+//  1. Normalize – rewrites name-length bytes from Name (mutates block bytes).
+//  2. Canonicalize – rewrites unused slots to canonical 0xFF form (mutates
+//     block bytes of unused slots only).
+//  3. CompactObjectives – moves active objectives ahead of unused ones
+//     (reorders slots, no byte changes within a slot).
+//  4. Validate – read-only; reports any remaining problem.
+//
+// It returns the first blocking error. When Normalize fails q is left
+// partially normalized and the later steps are not run.
+func (q *QuestFile) PrepareForExport() error {
+	if err := q.Normalize(); err != nil {
+		return err
+	}
+
+	q.Canonicalize()
+	q.CompactObjectives()
+
+	return q.Validate()
+}
+
+// unusedObjective returns an objective in canonical unused form.
+func unusedObjective() Objective {
+	var o Objective
+	for i := range o.Block[:OffNameLen] {
+		o.Block[i] = byte(TypeUnused)
+	}
+
+	return o
+}
@@ -0,0 +1,165 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize_SetsNameLength(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block[0] = byte(TypeDROP)
+	q.Objectives[0].Name = []byte("Wolf Pelt")
+	q.Objectives[1].Block[92] = 3 // stray length on a KILL objective
+
+	require.NoError(t, q.Normalize())
+	assert.Equal(t, uint8(9), q.Objectives[0].NameLength())
+	assert.Equal(t, uint8(0), q.Objectives[1].NameLength())
+}
+
+func TestNormalize_NameOnKillErrors(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[2].Name = []byte("x")
+	err := q.Normalize()
+	assert.ErrorIs(t, err, ErrNameLengthForType)
+	var objErr *ObjectiveError
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, 2, objErr.Index)
+}
+
+func TestNormalize_NameTooLong(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block[0] = byte(TypeFIND)
+	q.Objectives[0].Name = make([]byte, MaxNameLength+1)
+	assert.ErrorIs(t, q.Normalize(), ErrNameTooLong)
+}
+
+func TestCanonicalize_UnusedSlots(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[4].Block = [96]byte{}
+	q.Objectives[4].Block[0] = byte(TypeUnused)
+	q.Canonicalize()
+
+	for i := 0; i < OffNameLen; i++ {
+		assert.Equal(t, byte(0xFF), q.Objectives[4].Block[i], "offset %d", i)
+	}
+	assert.Equal(t, []byte{0, 0, 0, 0}, q.Objectives[4].Block[OffNameLen:])
+	assert.Equal(t, minimalValidQuestFile().Objectives[0], q.Objectives[0])
+}
+
+func TestCompactObjectives(t *testing.T) {
+	q := minimalValidQuestFile()
+	for i := range q.Objectives {
+		q.Objectives[i] = unusedObjective()
+	}
+	q.Objectives[2].Block[0] = byte(TypeKILL)
+	q.Objectives[2].Block[OffMapID] = 1
+	q.Objectives[5].Block[0] = byte(TypeDROP)
+	q.Objectives[5].Block[OffNameLen] = 2
+	q.Objectives[5].Name = []byte("ab")
+
+	q.CompactObjectives()
+	assert.Equal(t, TypeKILL, q.Objectives[0].ObjectiveType())
+	assert.Equal(t, byte(1), q.Objectives[0].Block[OffMapID])
+	assert.Equal(t, TypeDROP, q.Objectives[1].ObjectiveType())
+	assert.Equal(t, []byte("ab"), q.Objectives[1].Name)
+	for i := 2; i < NumObjectives; i++ {
+		assert.True(t, q.Objectives[i].IsUnused(), "slot %d", i)
+	}
+}
+
+func TestValidateObjectiveOrdering(t *testing.T) {
+	q := minimalValidQuestFile()
+	assert.NoError(t, q.ValidateObjectiveOrdering(), "all slots active")
+
+	for i := 4; i < NumObjectives; i++ {
+		q.Objectives[i] = unusedObjective()
+	}
+	assert.NoError(t, q.ValidateObjectiveOrdering(), "unused slots only at the end")
+
+	for i := range q.Objectives {
+		q.Objectives[i] = unusedObjective()
+	}
+	assert.NoError(t, q.ValidateObjectiveOrdering(), "no active slots")
+
+	q.Objectives[3].Block[0] = byte(TypeKILL)
+	q.Objectives[5].Block[0] = byte(TypeKILL)
+	err := q.ValidateObjectiveOrdering()
+	require.ErrorIs(t, err, ErrObjectiveGap)
+	var objErr *ObjectiveError
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, 3, objErr.Index, "first active slot after a gap")
+
+	q.CompactObjectives()
+	assert.NoError(t, q.ValidateObjectiveOrdering())
+}
+
+func TestPrepareForExport(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block = [96]byte{}
+	q.Objectives[0].Block[0] = byte(TypeUnused)
+	q.Objectives[3].Block[0] = byte(TypeFIND)
+	q.Objectives[3].SetKillCount(0)
+	q.Objectives[3].Name = []byte("Cave")
+
+	require.NoError(t, q.PrepareForExport())
+	assert.Equal(t, TypeFIND, q.Objectives[2].ObjectiveType())
+	assert.Equal(t, uint8(4), q.Objectives[2].NameLength())
+	assert.True(t, q.Objectives[6].IsUnused())
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, q, read)
+}
+
+func TestPrepareForExport_InvalidType(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[1].Block[0] = 9
+	assert.ErrorIs(t, q.PrepareForExport(), ErrInvalidObjectiveType)
+}
+
+func TestSortRewards(t *testing.T) {
+	q := minimalValidQuestFile()
+	h := &q.Header
+	h.RewardSlot1 = [4]byte{0x2C, 0x01, 0xAA, 0xBB} // 300
+	h.Count1, h.Count1Pad = 3, [3]byte{1, 1, 1}
+	// RewardSlot2 stays unused.
+	h.Count2, h.Count2Pad = 9, [3]byte{2, 2, 2}
+	h.RewardSlot3 = [4]byte{0x64, 0x00, 0xCC, 0xDD} // 100
+	h.Count3, h.Count3Pad = 1, [3]byte{3, 3, 3}
+	unused := h.RewardSlot2
+
+	h.SortRewards()
+	assert.Equal(t, [4]byte{0x64, 0x00, 0xCC, 0xDD}, h.RewardSlot1)
+	assert.Equal(t, uint8(1), h.Count1)
+	assert.Equal(t, [3]byte{3, 3, 3}, h.Count1Pad)
+	assert.Equal(t, [4]byte{0x2C, 0x01, 0xAA, 0xBB}, h.RewardSlot2)
+	assert.Equal(t, uint8(3), h.Count2)
+	assert.Equal(t, [3]byte{1, 1, 1}, h.Count2Pad)
+	assert.Equal(t, unused, h.RewardSlot3)
+	assert.Equal(t, uint8(9), h.Count3)
+	assert.Equal(t, [3]byte{2, 2, 2}, h.Count3Pad)
+}
+
+func TestSortRewards_SlotOrderIndependent(t *testing.T) {
+	a := minimalValidQuestFile()
+	binary.LittleEndian.PutUint16(a.Header.RewardSlot1[:2], 700)
+	a.Header.Count1 = 2
+	binary.LittleEndian.PutUint16(a.Header.RewardSlot3[:2], 500)
+	a.Header.Count3 = 4
+
+	b := minimalValidQuestFile()
+	binary.LittleEndian.PutUint16(b.Header.RewardSlot2[:2], 500)
+	b.Header.Count2 = 4
+	binary.LittleEndian.PutUint16(b.Header.RewardSlot1[:2], 700)
+	b.Header.Count1 = 2
+
+	a.Header.SortRewards()
+	b.Header.SortRewards()
+	assert.Equal(t, a.Header, b.Header)
+}
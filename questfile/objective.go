@@ -0,0 +1,265 @@
+package questfile
+
+import (
+	"encoding/binary"
+
+	"golang.org/x/text/encoding"
+)
+
+// FindTarget returns the location a FIND objective points the player to: the
+// map at OffMapID, the location within that map at OffLocationID and the
+// radius around it at OffRadius. ok is false, and the other results zero, for
+// objectives of any other type.
+func (o *Objective) FindTarget() (mapID, locationID uint16, radius uint8, ok bool) {
+	if o.ObjectiveType() != TypeFIND {
+		return 0, 0, 0, false
+	}
+
+	mapID = binary.LittleEndian.Uint16(o.Block[OffMapID:])
+	locationID = binary.LittleEndian.Uint16(o.Block[OffLocationID:])
+	return mapID, locationID, o.Block[OffRadius], true
+}
+
+// SetFindTarget makes o a FIND objective for the given location. It sets the
+// type byte to TypeFIND, writes the map, location and radius at their offsets
+// and clears the kill count, which FIND objectives do not use; the padding
+// bytes next to each field and the objective name are left unchanged.
+func (o *Objective) SetFindTarget(mapID, locationID uint16, radius uint8) {
+	o.Block[OffType] = byte(TypeFIND)
+	binary.LittleEndian.PutUint16(o.Block[OffMapID:], mapID)
+	binary.LittleEndian.PutUint16(o.Block[OffLocationID:], locationID)
+	o.Block[OffRadius] = radius
+	binary.LittleEndian.PutUint16(o.Block[OffKillCount:], 0)
+}
+
+// KillTarget returns what a KILL objective asks the player to kill: the map
+// at OffMapID, the monster at OffMonsterID and the number of kills at
+// OffKillCount. ok is false, and the other results zero, for objectives of
+// any other type. The kill count is at offset 20, not 24 as the original
+// request for these accessors stated: 24 holds the quest item code
+// (OffQuestItemID), and TestRead_ObjectiveFieldsParsed writes the kill count
+// at 20.
+func (o *Objective) KillTarget() (mapID, monsterID uint16, count uint16, ok bool) {
+	if o.ObjectiveType() != TypeKILL {
+		return 0, 0, 0, false
+	}
+
+	mapID = binary.LittleEndian.Uint16(o.Block[OffMapID:])
+	monsterID = binary.LittleEndian.Uint16(o.Block[OffMonsterID:])
+	count = binary.LittleEndian.Uint16(o.Block[OffKillCount:])
+	return mapID, monsterID, count, true
+}
+
+// SetKillTarget makes o a KILL objective for count kills of monsterID on
+// mapID. It sets the type byte to TypeKILL and writes the three fields at
+// their offsets, leaving padding bytes unchanged. KILL objectives cannot carry
+// a name, so if o has one SetKillTarget returns ErrNameLengthForType and
+// leaves o untouched.
+func (o *Objective) SetKillTarget(mapID, monsterID, count uint16) error {
+	if len(o.Name) > 0 || o.NameLength() != 0 {
+		return ErrNameLengthForType
+	}
+
+	o.Block[OffType] = byte(TypeKILL)
+	binary.LittleEndian.PutUint16(o.Block[OffMapID:], mapID)
+	binary.LittleEndian.PutUint16(o.Block[OffMonsterID:], monsterID)
+	binary.LittleEndian.PutUint16(o.Block[OffKillCount:], count)
+	return nil
+}
+
+// KillInfo is the decoded target of a KILL objective, as returned by
+// Objective.KillInfo.
+type KillInfo struct {
+	MapID      uint16
+	LocationID uint16
+	Radius     uint8
+	MonsterID  uint16
+	KillCount  uint16
+}
+
+// KillInfo returns the map, location, radius, monster and kill count of a
+// KILL objective in one struct, for callers iterating over a quest's
+// objectives. ok is false, and the struct zero, for objectives of any other
+// type.
+func (o *Objective) KillInfo() (info KillInfo, ok bool) {
+	if o.ObjectiveType() != TypeKILL {
+		return KillInfo{}, false
+	}
+
+	return KillInfo{
+		MapID:      o.MapID(),
+		LocationID: o.LocationID(),
+		Radius:     o.Radius(),
+		MonsterID:  o.MonsterID(),
+		KillCount:  o.KillCount(),
+	}, true
+}
+
+// MapID returns the little-endian uint16 at OffMapID. Like the other field
+// accessors below it reads the field whatever the objective's type; use
+// KillInfo, KillTarget or FindTarget to read only objectives of one type.
+func (o *Objective) MapID() uint16 {
+	return binary.LittleEndian.Uint16(o.Block[OffMapID:])
+}
+
+// SetMapID writes id at OffMapID, leaving the padding bytes after it
+// unchanged. Like the other field setters below it does not change the type
+// byte.
+func (o *Objective) SetMapID(id uint16) {
+	binary.LittleEndian.PutUint16(o.Block[OffMapID:], id)
+}
+
+// LocationID returns the little-endian uint16 at OffLocationID.
+func (o *Objective) LocationID() uint16 {
+	return binary.LittleEndian.Uint16(o.Block[OffLocationID:])
+}
+
+// SetLocationID writes id at OffLocationID.
+func (o *Objective) SetLocationID(id uint16) {
+	binary.LittleEndian.PutUint16(o.Block[OffLocationID:], id)
+}
+
+// Radius returns the byte at OffRadius.
+func (o *Objective) Radius() uint8 {
+	return o.Block[OffRadius]
+}
+
+// SetRadius writes r at OffRadius.
+func (o *Objective) SetRadius(r uint8) {
+	o.Block[OffRadius] = r
+}
+
+// MonsterID returns the little-endian uint16 at OffMonsterID: the monster to
+// kill, or the NPC for BRINGNPC objectives.
+func (o *Objective) MonsterID() uint16 {
+	return binary.LittleEndian.Uint16(o.Block[OffMonsterID:])
+}
+
+// SetMonsterID writes id at OffMonsterID.
+func (o *Objective) SetMonsterID(id uint16) {
+	binary.LittleEndian.PutUint16(o.Block[OffMonsterID:], id)
+}
+
+// KillCount returns the little-endian uint16 at OffKillCount.
+func (o *Objective) KillCount() uint16 {
+	return binary.LittleEndian.Uint16(o.Block[OffKillCount:])
+}
+
+// SetKillCount writes n at OffKillCount.
+func (o *Objective) SetKillCount(n uint16) {
+	binary.LittleEndian.PutUint16(o.Block[OffKillCount:], n)
+}
+
+// SetName stores the bytes of s as o's name and sets the name-length byte at
+// OffNameLen to match, so the two cannot disagree. s is copied as is, with no
+// encoding; convert it to CP949 first for names the client displays. Only
+// DROP and FIND objectives carry a name: for other types SetName returns
+// ErrNameLengthForType, and for a name longer than MaxNameLength bytes
+// ErrNameTooLong, leaving o untouched in both cases. An empty s clears the
+// name.
+func (o *Objective) SetName(s string) error {
+	if !supportsName(o.ObjectiveType()) {
+		return ErrNameLengthForType
+	}
+
+	if len(s) > MaxNameLength {
+		return ErrNameTooLong
+	}
+
+	if s == "" {
+		o.ClearName()
+		return nil
+	}
+
+	o.Name = []byte(s)
+	o.Block[OffNameLen] = uint8(len(s))
+	return nil
+}
+
+// NameString returns o's name transcoded to UTF-8 with dec, for example
+// korean.EUCKR.NewDecoder() for the CP949 names of localized quests. A nil
+// dec returns the bytes unchanged. Name itself is not modified.
+func (o *Objective) NameString(dec *encoding.Decoder) (string, error) {
+	if dec == nil {
+		return string(o.Name), nil
+	}
+
+	b, err := dec.Bytes(o.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// SetNameString transcodes the UTF-8 string s with enc and stores the result
+// with SetName, so the same type and length rules apply and the name-length
+// byte stays in sync. A nil enc stores the bytes of s unchanged. o is left
+// untouched if s cannot be encoded.
+func (o *Objective) SetNameString(s string, enc *encoding.Encoder) error {
+	if enc == nil {
+		return o.SetName(s)
+	}
+
+	b, err := enc.Bytes([]byte(s))
+	if err != nil {
+		return err
+	}
+
+	return o.SetName(string(b))
+}
+
+// ClearName removes o's name and zeroes the name-length byte. It works for
+// every objective type, so it also repairs a stray length byte on a type that
+// cannot carry a name.
+func (o *Objective) ClearName() {
+	o.Name = nil
+	o.Block[OffNameLen] = 0
+}
+
+// TargetID returns the ID an active objective is aimed at, read from the
+// offset its type uses: the monster at OffMonsterID for KILL, the NPC at the
+// same offset for BRINGNPC, and the quest item at OffQuestItemID for
+// QUESTITEM and DROP. ok is false for FIND objectives, which target a
+// location (see FindTarget), and for unused or invalid slots.
+func (o *Objective) TargetID() (id uint16, ok bool) {
+	switch o.ObjectiveType() {
+	case TypeKILL, TypeBRINGNPC:
+		return binary.LittleEndian.Uint16(o.Block[OffMonsterID:]), true
+	case TypeQUESTITEM, TypeDROP:
+		return binary.LittleEndian.Uint16(o.Block[OffQuestItemID:]), true
+	default:
+		return 0, false
+	}
+}
+
+// ValidateSemantics checks the per-type completeness rules that tell a usable
+// objective from a stub: a KILL objective must ask for at least one kill, a
+// DROP or FIND objective must have a name, and a BRINGNPC objective must name
+// a non-zero NPC. Objectives other than KILL must also leave the kill count
+// at zero. It returns ErrZeroKillCount, ErrEmptyName, ErrZeroTargetID or
+// ErrKillCountForType for the rule o breaks, or nil; unused slots always
+// pass. Read does not apply these rules, so stubs still load.
+func (o *Objective) ValidateSemantics() error {
+	t := o.ObjectiveType()
+	if t != TypeKILL && t != TypeUnused && o.KillCount() != 0 {
+		return ErrKillCountForType
+	}
+
+	switch t {
+	case TypeKILL:
+		if binary.LittleEndian.Uint16(o.Block[OffKillCount:]) == 0 {
+			return ErrZeroKillCount
+		}
+	case TypeDROP, TypeFIND:
+		if len(o.Name) == 0 {
+			return ErrEmptyName
+		}
+	case TypeBRINGNPC:
+		if binary.LittleEndian.Uint16(o.Block[OffMonsterID:]) == 0 {
+			return ErrZeroTargetID
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,269 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/korean"
+)
+
+func TestObjective_FindTarget(t *testing.T) {
+	var o Objective
+	o.Block[OffType] = byte(TypeFIND)
+	o.Block[OffMapID], o.Block[OffMapID+1] = 0x34, 0x12
+	o.Block[OffLocationID] = 7
+	o.Block[OffRadius] = 15
+
+	mapID, locationID, radius, ok := o.FindTarget()
+	require.True(t, ok)
+	assert.Equal(t, uint16(0x1234), mapID)
+	assert.Equal(t, uint16(7), locationID)
+	assert.Equal(t, uint8(15), radius)
+}
+
+func TestObjective_FindTarget_NotFind(t *testing.T) {
+	for _, typ := range []ObjectiveType{TypeKILL, TypeQUESTITEM, TypeBRINGNPC, TypeDROP, TypeUnused} {
+		var o Objective
+		o.Block[OffType] = byte(typ)
+		o.Block[OffMapID] = 1
+		mapID, locationID, radius, ok := o.FindTarget()
+		assert.False(t, ok, "type %d", typ)
+		assert.Zero(t, mapID)
+		assert.Zero(t, locationID)
+		assert.Zero(t, radius)
+	}
+}
+
+func TestObjective_SetFindTarget(t *testing.T) {
+	o := unusedObjective()
+	o.SetFindTarget(3, 0x0102, 9)
+
+	assert.Equal(t, TypeFIND, o.ObjectiveType())
+	mapID, locationID, radius, ok := o.FindTarget()
+	require.True(t, ok)
+	assert.Equal(t, uint16(3), mapID)
+	assert.Equal(t, uint16(0x0102), locationID)
+	assert.Equal(t, uint8(9), radius)
+	assert.Zero(t, o.KillCount(), "FIND objectives do not use the kill count")
+
+	// Padding next to each field is preserved.
+	assert.Equal(t, []byte{0xFF, 0xFF}, o.Block[OffMapID+2:OffMapID+4])
+	assert.Equal(t, []byte{0xFF, 0xFF, 0xFF}, o.Block[OffRadius+1:OffRadius+4])
+}
+
+func TestObjective_SetFindTarget_RoundTrip(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[1].SetFindTarget(10, 20, 30)
+	q.Objectives[1].Name = []byte("Cave")
+	q.Objectives[1].Block[OffNameLen] = 4
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	read, err := Read(&buf)
+	require.NoError(t, err)
+
+	mapID, locationID, radius, ok := read.Objectives[1].FindTarget()
+	require.True(t, ok)
+	assert.Equal(t, []any{uint16(10), uint16(20), uint8(30)}, []any{mapID, locationID, radius})
+}
+
+func TestObjective_KillTarget(t *testing.T) {
+	q := minimalValidQuestFile()
+	binary.LittleEndian.PutUint16(q.Objectives[0].Block[4:6], 15)
+	binary.LittleEndian.PutUint16(q.Objectives[0].Block[16:18], 3001)
+	binary.LittleEndian.PutUint16(q.Objectives[0].Block[20:22], 20)
+
+	mapID, monsterID, count, ok := q.Objectives[0].KillTarget()
+	require.True(t, ok)
+	assert.Equal(t, uint16(15), mapID)
+	assert.Equal(t, uint16(3001), monsterID)
+	assert.Equal(t, uint16(20), count)
+}
+
+func TestObjective_KillTarget_NotKill(t *testing.T) {
+	for _, typ := range []ObjectiveType{TypeQUESTITEM, TypeBRINGNPC, TypeDROP, TypeFIND, TypeUnused} {
+		var o Objective
+		o.Block[OffType] = byte(typ)
+		_, _, _, ok := o.KillTarget()
+		assert.False(t, ok, "type %d", typ)
+	}
+}
+
+func TestObjective_SetKillTarget(t *testing.T) {
+	o := unusedObjective()
+	require.NoError(t, o.SetKillTarget(15, 3001, 20))
+
+	assert.Equal(t, TypeKILL, o.ObjectiveType())
+	mapID, monsterID, count, ok := o.KillTarget()
+	require.True(t, ok)
+	assert.Equal(t, []uint16{15, 3001, 20}, []uint16{mapID, monsterID, count})
+	assert.Equal(t, uint16(0xFFFF), binary.LittleEndian.Uint16(o.Block[OffQuestItemID:]), "quest item field untouched")
+}
+
+func TestObjective_SetKillTarget_RejectsNamedObjective(t *testing.T) {
+	var o Objective
+	o.Block[OffType] = byte(TypeDROP)
+	o.Block[OffNameLen] = 4
+	o.Name = []byte("Wolf")
+	before := o
+
+	assert.ErrorIs(t, o.SetKillTarget(1, 2, 3), ErrNameLengthForType)
+	assert.Equal(t, before, o)
+}
+
+func TestObjective_TargetID(t *testing.T) {
+	var o Objective
+	binary.LittleEndian.PutUint16(o.Block[OffMonsterID:], 0x1111)
+	binary.LittleEndian.PutUint16(o.Block[OffQuestItemID:], 0x2222)
+
+	tests := []struct {
+		typ  ObjectiveType
+		id   uint16
+		isOK bool
+	}{
+		{TypeKILL, 0x1111, true},
+		{TypeBRINGNPC, 0x1111, true},
+		{TypeQUESTITEM, 0x2222, true},
+		{TypeDROP, 0x2222, true},
+		{TypeFIND, 0, false},
+		{TypeUnused, 0, false},
+		{ObjectiveType(9), 0, false},
+	}
+
+	for _, tt := range tests {
+		o.Block[OffType] = byte(tt.typ)
+		id, ok := o.TargetID()
+		assert.Equal(t, tt.isOK, ok, tt.typ.String())
+		assert.Equal(t, tt.id, id, tt.typ.String())
+	}
+}
+
+func TestObjective_ValidateSemantics(t *testing.T) {
+	var kill Objective
+	require.NoError(t, kill.SetKillTarget(1, 2, 0))
+	assert.ErrorIs(t, kill.ValidateSemantics(), ErrZeroKillCount)
+	require.NoError(t, kill.SetKillTarget(1, 2, 5))
+	assert.NoError(t, kill.ValidateSemantics())
+
+	for _, typ := range []ObjectiveType{TypeDROP, TypeFIND} {
+		var o Objective
+		o.Block[OffType] = byte(typ)
+		assert.ErrorIs(t, o.ValidateSemantics(), ErrEmptyName, "type %d", typ)
+		o.Name = []byte("Cave")
+		assert.NoError(t, o.ValidateSemantics(), "type %d", typ)
+	}
+
+	var bring Objective
+	bring.Block[OffType] = byte(TypeBRINGNPC)
+	assert.ErrorIs(t, bring.ValidateSemantics(), ErrZeroTargetID)
+	binary.LittleEndian.PutUint16(bring.Block[OffMonsterID:], 300)
+	assert.NoError(t, bring.ValidateSemantics())
+
+	var item Objective
+	item.Block[OffType] = byte(TypeQUESTITEM)
+	assert.NoError(t, item.ValidateSemantics())
+	unused := unusedObjective()
+	assert.NoError(t, unused.ValidateSemantics())
+}
+
+func TestObjective_FieldAccessors(t *testing.T) {
+	var o Objective
+	o.Block[OffMapID+2] = 0xEE // padding must survive the setters
+	o.SetMapID(0x1234)
+	o.SetLocationID(7)
+	o.SetRadius(15)
+	o.SetMonsterID(300)
+	o.SetKillCount(25)
+
+	assert.Equal(t, []byte{0x34, 0x12, 0xEE}, o.Block[OffMapID:OffMapID+3])
+	assert.Equal(t, uint16(0x1234), o.MapID())
+	assert.Equal(t, uint16(7), o.LocationID())
+	assert.Equal(t, uint8(15), o.Radius())
+	assert.Equal(t, uint16(300), o.MonsterID())
+	assert.Equal(t, uint16(25), o.KillCount())
+	assert.Equal(t, []byte{0x2C, 0x01}, o.Block[OffMonsterID:OffMonsterID+2])
+}
+
+func TestObjective_KillInfo(t *testing.T) {
+	var o Objective
+	require.NoError(t, o.SetKillTarget(3, 300, 10))
+	o.SetLocationID(9)
+	o.SetRadius(4)
+
+	info, ok := o.KillInfo()
+	require.True(t, ok)
+	assert.Equal(t, KillInfo{MapID: 3, LocationID: 9, Radius: 4, MonsterID: 300, KillCount: 10}, info)
+
+	o.Block[OffType] = byte(TypeFIND)
+	info, ok = o.KillInfo()
+	assert.False(t, ok)
+	assert.Zero(t, info)
+}
+
+func TestObjective_SetName(t *testing.T) {
+	var o Objective
+	o.Block[OffType] = byte(TypeDROP)
+	require.NoError(t, o.SetName("Wolf Pelt"))
+	assert.Equal(t, []byte("Wolf Pelt"), o.Name)
+	assert.Equal(t, uint8(9), o.NameLength())
+
+	require.NoError(t, o.SetName(""))
+	assert.Nil(t, o.Name)
+	assert.Equal(t, uint8(0), o.NameLength())
+}
+
+func TestObjective_SetNameErrors(t *testing.T) {
+	var o Objective
+	o.Block[OffType] = byte(TypeKILL)
+	assert.ErrorIs(t, o.SetName("x"), ErrNameLengthForType)
+	assert.Nil(t, o.Name)
+
+	o.Block[OffType] = byte(TypeFIND)
+	require.NoError(t, o.SetName("Cave"))
+	assert.ErrorIs(t, o.SetName(strings.Repeat("a", MaxNameLength+1)), ErrNameTooLong)
+	assert.Equal(t, []byte("Cave"), o.Name)
+	assert.Equal(t, uint8(4), o.NameLength())
+
+	require.NoError(t, o.SetName(strings.Repeat("a", MaxNameLength)))
+	assert.Equal(t, uint8(MaxNameLength), o.NameLength())
+}
+
+func TestObjective_ClearName(t *testing.T) {
+	var o Objective
+	o.Block[OffType] = byte(TypeKILL)
+	o.Block[OffNameLen] = 3
+	o.ClearName()
+	assert.Nil(t, o.Name)
+	assert.Equal(t, uint8(0), o.NameLength())
+}
+
+func TestObjective_NameString(t *testing.T) {
+	var o Objective
+	o.Block[OffType] = byte(TypeDROP)
+	require.NoError(t, o.SetNameString("늑대 가죽", korean.EUCKR.NewEncoder()))
+	assert.Equal(t, []byte{0xb4, 0xc1, 0xb4, 0xeb, 0x20, 0xb0, 0xa1, 0xc1, 0xd7}, o.Name)
+	assert.Equal(t, uint8(len(o.Name)), o.NameLength())
+
+	s, err := o.NameString(korean.EUCKR.NewDecoder())
+	require.NoError(t, err)
+	assert.Equal(t, "늑대 가죽", s)
+
+	s, err = o.NameString(nil)
+	require.NoError(t, err)
+	assert.Equal(t, string(o.Name), s)
+}
+
+func TestObjective_SetNameStringErrors(t *testing.T) {
+	var o Objective
+	o.Block[OffType] = byte(TypeFIND)
+	require.NoError(t, o.SetNameString("Cave", nil))
+	assert.Error(t, o.SetNameString("\U0001F600", korean.EUCKR.NewEncoder()), "not representable in CP949")
+	assert.Equal(t, []byte("Cave"), o.Name)
+
+	o.Block[OffType] = byte(TypeKILL)
+	assert.ErrorIs(t, o.SetNameString("x", korean.EUCKR.NewEncoder()), ErrNameLengthForType)
+}
@@ -0,0 +1,241 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PatchVersion is the version byte written at the start of every patch.
+const PatchVersion = 1
+
+// ErrInvalidPatch is returned by ApplyPatch when a patch is malformed: an
+// unknown version, a header run or objective index out of range, or trailing
+// bytes after the last section. A patch that simply ends early is reported as
+// io.ErrUnexpectedEOF instead.
+var ErrInvalidPatch = errors.New("questfile: invalid patch")
+
+// MakePatch returns a compact binary delta that turns base into target. The
+// patch has three sections, in order:
+//
+//   - header: a run count (uint8) followed by runs of changed header bytes,
+//     each an offset (uint8), a length (uint8) and the new bytes;
+//   - objectives: a bitmask (uint8, bit i for slot i) of changed objectives,
+//     each followed by its full 96-byte block, a name length (uint8) and the
+//     name bytes;
+//   - continuation: a bitmask (uint8, bit i for slot i) of changed slots, each
+//     followed by its new value (uint32).
+//
+// The whole patch is prefixed with PatchVersion and all multi-byte values are
+// little-endian. Padding and unknown bytes are compared like any other byte,
+// so ApplyPatch(base, patch) reproduces target byte-for-byte. MakePatch
+// returns an *ObjectiveError wrapping ErrNameTooLong if a changed objective's
+// Name is longer than MaxNameLength.
+func MakePatch(base, target QuestFile) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(PatchVersion)
+
+	before, err := headerBytes(&base.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := headerBytes(&target.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := byteRuns(before, after)
+	buf.WriteByte(byte(len(runs)))
+	for _, run := range runs {
+		buf.WriteByte(byte(run[0]))
+		buf.WriteByte(byte(run[1] - run[0]))
+		buf.Write(after[run[0]:run[1]])
+	}
+
+	var mask byte
+	for i := range target.Objectives {
+		if !objectiveEqual(&base.Objectives[i], &target.Objectives[i]) {
+			mask |= 1 << i
+		}
+	}
+
+	buf.WriteByte(mask)
+	for i := range target.Objectives {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+
+		o := &target.Objectives[i]
+		if len(o.Name) > MaxNameLength {
+			return nil, &ObjectiveError{Index: i, Err: ErrNameTooLong}
+		}
+
+		buf.Write(o.Block[:])
+		buf.WriteByte(byte(len(o.Name)))
+		buf.Write(o.Name)
+	}
+
+	mask = 0
+	for i := range target.Continuation {
+		if base.Continuation[i] != target.Continuation[i] {
+			mask |= 1 << i
+		}
+	}
+
+	buf.WriteByte(mask)
+	for i := range target.Continuation {
+		if mask&(1<<i) != 0 {
+			buf.Write(binary.LittleEndian.AppendUint32(nil, target.Continuation[i]))
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ApplyPatch applies a patch produced by MakePatch to base and returns the
+// result. base must be the quest the patch was made against; ApplyPatch cannot
+// detect a different base and would produce a mix of both. base itself is not
+// modified. Malformed patches are reported as ErrInvalidPatch and truncated
+// ones as io.ErrUnexpectedEOF.
+func ApplyPatch(base QuestFile, patch []byte) (QuestFile, error) {
+	r := bytes.NewReader(patch)
+	version, err := r.ReadByte()
+	if err != nil {
+		return QuestFile{}, io.ErrUnexpectedEOF
+	}
+
+	if version != PatchVersion {
+		return QuestFile{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidPatch, version)
+	}
+
+	q := base
+	header, err := headerBytes(&q.Header)
+	if err != nil {
+		return QuestFile{}, err
+	}
+
+	var runCount [1]byte
+	if _, err := io.ReadFull(r, runCount[:]); err != nil {
+		return QuestFile{}, unexpectedEOF(err)
+	}
+
+	for range runCount[0] {
+		var run [2]byte
+		if _, err := io.ReadFull(r, run[:]); err != nil {
+			return QuestFile{}, unexpectedEOF(err)
+		}
+
+		start, end := int(run[0]), int(run[0])+int(run[1])
+		if end > HeaderSize {
+			return QuestFile{}, fmt.Errorf("%w: header run %d+%d out of range", ErrInvalidPatch, run[0], run[1])
+		}
+
+		if _, err := io.ReadFull(r, header[start:end]); err != nil {
+			return QuestFile{}, unexpectedEOF(err)
+		}
+	}
+
+	if err := binary.Read(bytes.NewReader(header), binary.LittleEndian, &q.Header); err != nil {
+		return QuestFile{}, err
+	}
+
+	var mask [1]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return QuestFile{}, unexpectedEOF(err)
+	}
+
+	if mask[0]>>NumObjectives != 0 {
+		return QuestFile{}, fmt.Errorf("%w: objective mask 0x%02X", ErrInvalidPatch, mask[0])
+	}
+
+	for i := range q.Objectives {
+		if mask[0]&(1<<i) == 0 {
+			continue
+		}
+
+		var o Objective
+		if _, err := io.ReadFull(r, o.Block[:]); err != nil {
+			return QuestFile{}, unexpectedEOF(err)
+		}
+
+		var nameLen [1]byte
+		if _, err := io.ReadFull(r, nameLen[:]); err != nil {
+			return QuestFile{}, unexpectedEOF(err)
+		}
+
+		if nameLen[0] > 0 {
+			o.Name = make([]byte, nameLen[0])
+			if _, err := io.ReadFull(r, o.Name); err != nil {
+				return QuestFile{}, unexpectedEOF(err)
+			}
+		}
+
+		q.Objectives[i] = o
+	}
+
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return QuestFile{}, unexpectedEOF(err)
+	}
+
+	if mask[0]>>len(q.Continuation) != 0 {
+		return QuestFile{}, fmt.Errorf("%w: continuation mask 0x%02X", ErrInvalidPatch, mask[0])
+	}
+
+	for i := range q.Continuation {
+		if mask[0]&(1<<i) == 0 {
+			continue
+		}
+
+		if err := binary.Read(r, binary.LittleEndian, &q.Continuation[i]); err != nil {
+			return QuestFile{}, unexpectedEOF(err)
+		}
+	}
+
+	if r.Len() > 0 {
+		return QuestFile{}, fmt.Errorf("%w: %d trailing bytes", ErrInvalidPatch, r.Len())
+	}
+
+	return q, nil
+}
+
+// headerBytes returns the 96-byte encoding of h.
+func headerBytes(h *QuestHeader) ([]byte, error) {
+	return binary.Append(make([]byte, 0, HeaderSize), binary.LittleEndian, h)
+}
+
+// byteRuns returns the [start, end) ranges where a and b differ.
+func byteRuns(a, b []byte) [][2]int {
+	var runs [][2]int
+	for i := 0; i < len(a); {
+		if a[i] == b[i] {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(a) && a[i] != b[i] {
+			i++
+		}
+
+		runs = append(runs, [2]int{start, i})
+	}
+
+	return runs
+}
+
+// objectiveEqual reports whether a and b encode to the same bytes.
+func objectiveEqual(a, b *Objective) bool {
+	return a.Block == b.Block && bytes.Equal(a.Name, b.Name)
+}
+
+// unexpectedEOF maps io.EOF to io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,100 @@
+package questfile
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeQuest(t *testing.T, q QuestFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	return buf.Bytes()
+}
+
+func TestPatch_RoundTrip(t *testing.T) {
+	base := minimalValidQuestFile()
+	target := base
+	target.Header.EXP = 5000
+	target.Header.MaxLevel = 60
+	target.Header.HeaderTail = [4]byte{1, 2, 3, 4}
+	target.Objectives[2] = unusedObjective()
+	target.Objectives[4].SetFindTarget(3, 9, 12)
+	target.Objectives[4].Name = []byte("Old Well")
+	target.Objectives[4].Block[OffNameLen] = 8
+	target.Continuation[1] = 77
+
+	patch, err := MakePatch(base, target)
+	require.NoError(t, err)
+
+	got, err := ApplyPatch(base, patch)
+	require.NoError(t, err)
+	assert.Equal(t, target, got)
+	assert.Equal(t, encodeQuest(t, target), encodeQuest(t, got))
+	assert.Less(t, len(patch), len(encodeQuest(t, target)))
+}
+
+func TestPatch_Identical(t *testing.T) {
+	base := minimalValidQuestFile()
+	patch, err := MakePatch(base, base)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{PatchVersion, 0, 0, 0}, patch)
+
+	got, err := ApplyPatch(base, patch)
+	require.NoError(t, err)
+	assert.Equal(t, base, got)
+}
+
+func TestPatch_DoesNotModifyBase(t *testing.T) {
+	base := minimalValidQuestFile()
+	target := base
+	target.Objectives[0].SetFindTarget(1, 2, 3)
+	target.Objectives[0].Name = []byte("x")
+
+	patch, err := MakePatch(base, target)
+	require.NoError(t, err)
+	_, err = ApplyPatch(base, patch)
+	require.NoError(t, err)
+	assert.Equal(t, minimalValidQuestFile(), base)
+}
+
+func TestMakePatch_NameTooLong(t *testing.T) {
+	base := minimalValidQuestFile()
+	target := base
+	target.Objectives[3].Name = make([]byte, MaxNameLength+1)
+
+	_, err := MakePatch(base, target)
+	var objErr *ObjectiveError
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, 3, objErr.Index)
+	assert.ErrorIs(t, err, ErrNameTooLong)
+}
+
+func TestApplyPatch_Invalid(t *testing.T) {
+	base := minimalValidQuestFile()
+	tests := []struct {
+		name  string
+		patch []byte
+		want  error
+	}{
+		{"empty", nil, io.ErrUnexpectedEOF},
+		{"bad version", []byte{9, 0, 0, 0}, ErrInvalidPatch},
+		{"header run out of range", []byte{PatchVersion, 1, 90, 10}, ErrInvalidPatch},
+		{"objective mask", []byte{PatchVersion, 0, 0x80, 0}, ErrInvalidPatch},
+		{"continuation mask", []byte{PatchVersion, 0, 0, 0x08}, ErrInvalidPatch},
+		{"trailing bytes", []byte{PatchVersion, 0, 0, 0, 0}, ErrInvalidPatch},
+		{"truncated run", []byte{PatchVersion, 1, 0, 4, 1}, io.ErrUnexpectedEOF},
+		{"truncated objective", []byte{PatchVersion, 0, 0x01, 0xFF}, io.ErrUnexpectedEOF},
+		{"missing continuation", []byte{PatchVersion, 0, 0}, io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ApplyPatch(base, tt.patch)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
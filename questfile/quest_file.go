@@ -6,7 +6,9 @@ package questfile
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
+	"strconv"
 )
 
 // Format constants.
@@ -18,20 +20,78 @@ const (
 	MinFileSize        = HeaderSize + NumObjectives*ObjectiveBlockSize + ContinuationSize // 780
 )
 
+// Objective block field offsets. Multi-byte fields are little-endian; byte
+// fields (Radius, drop rates, name length) occupy the low byte of a 4-byte slot.
+// The regions 40–55, 60–75 and 88–91 are not yet understood and are preserved
+// as-is.
+const (
+	OffType        = 0  // uint8:  objective type (TypeKILL..TypeFIND or TypeUnused)
+	OffMapID       = 4  // uint16: map the objective takes place on
+	OffLocationID  = 8  // uint16: location within the map
+	OffRadius      = 12 // uint8:  radius around the location
+	OffMonsterID   = 16 // uint16: monster to kill, or NPC for BRINGNPC
+	OffKillCount   = 20 // uint16: number of kills required
+	OffQuestItemID = 24 // uint16: quest item code
+	OffItemCount   = 56 // uint16: number of quest items required
+	OffDropRate1   = 76 // uint8:  drop probability for the first drop source
+	OffDropRate2   = 80 // uint8:  drop probability for the second drop source
+	OffDropRate3   = 84 // uint8:  drop probability for the third drop source
+	OffNameLen     = 92 // uint8:  length of the name that follows the block
+)
+
+// ObjectiveType is the value of the type byte at offset OffType in each
+// objective block.
+type ObjectiveType uint8
+
 // Objective type constants (value at offset 0 in each objective block).
 const (
-	TypeKILL      = 0
-	TypeQUESTITEM = 1
-	TypeBRINGNPC  = 2
-	TypeDROP      = 3
-	TypeFIND      = 4
+	TypeKILL      ObjectiveType = 0
+	TypeQUESTITEM ObjectiveType = 1
+	TypeBRINGNPC  ObjectiveType = 2
+	TypeDROP      ObjectiveType = 3
+	TypeFIND      ObjectiveType = 4
 
 	// TypeUnused is the sentinel value (0xFF) used to mark empty/unused objective
 	// slots. Real quest files always contain exactly 7 objective blocks; unused
 	// slots are filled with 0xFF bytes rather than a valid type code.
-	TypeUnused = 0xFF
+	TypeUnused ObjectiveType = 0xFF
 )
 
+// String returns the name of the objective type as used in quest tooling
+// ("KILL", "QUESTITEM", "BRINGNPC", "DROP", "FIND" or "UNUSED"). Any other
+// value is formatted as "ObjectiveType(n)".
+func (t ObjectiveType) String() string {
+	switch t {
+	case TypeKILL:
+		return "KILL"
+	case TypeQUESTITEM:
+		return "QUESTITEM"
+	case TypeBRINGNPC:
+		return "BRINGNPC"
+	case TypeDROP:
+		return "DROP"
+	case TypeFIND:
+		return "FIND"
+	case TypeUnused:
+		return "UNUSED"
+	default:
+		return "ObjectiveType(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
+// ObjectiveTypeName returns the name of the raw type byte t for logs and
+// diagnostics: the same names String gives for the known types, and
+// "UNKNOWN(n)" for any other value. The mapping is stable and meant to be
+// shared with tooling.
+func ObjectiveTypeName(t uint8) string {
+	switch ObjectiveType(t) {
+	case TypeKILL, TypeQUESTITEM, TypeBRINGNPC, TypeDROP, TypeFIND, TypeUnused:
+		return ObjectiveType(t).String()
+	default:
+		return "UNKNOWN(" + strconv.Itoa(int(t)) + ")"
+	}
+}
+
 // Sentinel values.
 const (
 	UnusedRewardItemCode = 0xFFFF
@@ -52,6 +112,52 @@ var (
 	// ErrTrailingBytes is returned when extra bytes are found after the
 	// continuation section.
 	ErrTrailingBytes = errors.New("questfile: trailing bytes after continuation")
+
+	// ErrNameTooLong is returned when an objective name is longer than
+	// MaxNameLength and so cannot be described by the name-length byte.
+	ErrNameTooLong = errors.New("questfile: objective name too long")
+
+	// ErrNameLengthMismatch is returned when an objective's name-length byte
+	// does not match the length of its Name, which would make Write produce a
+	// file that Read cannot parse.
+	ErrNameLengthMismatch = errors.New("questfile: name length byte does not match name")
+
+	// ErrObjectiveGap is returned when an active objective follows an unused
+	// slot. The client only shows objectives up to the first unused slot.
+	ErrObjectiveGap = errors.New("questfile: active objective after unused slot")
+
+	// ErrSelfContinuation is returned when a continuation slot names the
+	// quest's own ID, which makes the client loop on quest completion.
+	ErrSelfContinuation = errors.New("questfile: continuation points to the quest itself")
+
+	// ErrZeroKillCount is returned when a KILL objective asks for zero kills
+	// and so can never be completed.
+	ErrZeroKillCount = errors.New("questfile: KILL objective has zero kill count")
+
+	// ErrEmptyName is returned when a DROP or FIND objective has no name.
+	ErrEmptyName = errors.New("questfile: objective name is empty")
+
+	// ErrZeroTargetID is returned when a BRINGNPC objective names NPC 0.
+	ErrZeroTargetID = errors.New("questfile: BRINGNPC objective has zero target id")
+
+	// ErrKillCountForType is returned when an objective other than KILL has a
+	// non-zero kill count at OffKillCount.
+	ErrKillCountForType = errors.New("questfile: kill count set on non-KILL objective")
+
+	// ErrRewardCountUnused is returned when a reward slot holding
+	// UnusedRewardItemCode has a non-zero count.
+	ErrRewardCountUnused = errors.New("questfile: reward count set on unused reward slot")
+
+	// ErrLevelRange is returned by ValidateStrict when MinLevel is greater
+	// than MaxLevel.
+	ErrLevelRange = errors.New("questfile: MinLevel greater than MaxLevel")
+
+	// ErrRewardSlot is returned by SetReward for a slot other than 0, 1 or 2.
+	ErrRewardSlot = errors.New("questfile: reward slot out of range")
+
+	// ErrContinuationSlot is returned by SetContinuation and
+	// ClearContinuation for a slot other than 0, 1 or 2.
+	ErrContinuationSlot = errors.New("questfile: continuation slot out of range")
 )
 
 // QuestHeader is the fixed 96-byte quest file header.
@@ -59,7 +165,7 @@ var (
 type QuestHeader struct {
 	QuestIDRaw     [4]byte  // 0–3:   Quest ID (lower 16 bits) + 2 padding
 	GivenNPCRaw    [4]byte  // 4–7:   Given NPC ID (lower 16 bits) + 2 padding
-	TargetNPCBlock [24]byte // 8–31:  Target NPC (first 2 bytes = ID) + 22 bytes
+	TargetNPCBlock [24]byte // 8–31:  Target NPC ID, map, X/Y and flag; see TargetOffNPCID
 	MinLevel       uint8    // 32
 	MinLevelPad    [3]byte  // 33–35
 	MaxLevel       uint8    // 36
@@ -86,7 +192,7 @@ type QuestHeader struct {
 // bytes. Unused slots have type byte 0xFF and all remaining bytes set to 0xFF
 // (except the last four bytes which are 0x00, holding NameLength = 0).
 type Objective struct {
-	Block [96]byte // fixed block; NameLength at offset 92
+	Block [96]byte // fixed block; NameLength at offset OffNameLen (92)
 	Name  []byte   // exactly NameLength bytes after block (only DROP/FIND, when > 0)
 }
 
@@ -97,6 +203,38 @@ type QuestFile struct {
 	Continuation [3]uint32 // 0xFFFFFFFF = unused
 }
 
+// NewQuestFile returns an empty quest that Write and Read accept as is: every
+// reward slot holds UnusedRewardItemCode, every continuation is
+// UnusedContinuation and every objective is an unused slot in the canonical
+// form real files use. IDs, levels and rewards are zero. It is the starting
+// point for building a quest from scratch; the zero QuestFile fails Validate,
+// since its objectives and reward slots read as KILL and item 0.
+func NewQuestFile() QuestFile {
+	var q QuestFile
+	binary.LittleEndian.PutUint16(q.Header.RewardSlot1[:2], UnusedRewardItemCode)
+	binary.LittleEndian.PutUint16(q.Header.RewardSlot2[:2], UnusedRewardItemCode)
+	binary.LittleEndian.PutUint16(q.Header.RewardSlot3[:2], UnusedRewardItemCode)
+	for i := range q.Objectives {
+		q.Objectives[i] = unusedObjective()
+	}
+
+	for i := range q.Continuation {
+		q.Continuation[i] = UnusedContinuation
+	}
+
+	return q
+}
+
+// ReadOptions adjusts how ReadWithOptions decodes a quest.
+type ReadOptions struct {
+	// StrictTrailing fails the read with ErrTrailingBytes when r holds more
+	// data after the continuation section, as the mapbin and monsterbin
+	// options of the same name do. Read always sets it; leave it off to read
+	// a quest that is followed by other data in the same stream, in which
+	// case nothing after the continuation is consumed.
+	StrictTrailing bool
+}
+
 // Read reads a complete quest file from r.
 //
 // Error conditions:
@@ -105,15 +243,45 @@ type QuestFile struct {
 //   - ErrNameLengthForType    – KILL/QUESTITEM/BRINGNPC block has non-zero name length
 //   - ErrTrailingBytes        – extra data follows the continuation section
 func Read(r io.Reader) (QuestFile, error) {
+	return ReadWithOptions(r, ReadOptions{StrictTrailing: true})
+}
+
+// ReadWithOptions reads a quest file like Read, adjusted by opts. Unlike
+// Read, it only reports ErrTrailingBytes with opts.StrictTrailing set;
+// otherwise data after the continuation section is left unread in r.
+func ReadWithOptions(r io.Reader, opts ReadOptions) (QuestFile, error) {
+	q, _, err := read(r, false, !opts.StrictTrailing)
+	return q, err
+}
+
+// ReadIgnoreTypeErrors reads a quest file like Read but treats format
+// violations as warnings instead of failing, for recovering data from
+// corrupted files. Each objective whose type byte is invalid or whose type
+// does not allow a name is still read in full, including as many name bytes
+// as its name-length byte announces, and reported as an *ObjectiveError
+// wrapping ErrInvalidObjectiveType or ErrNameLengthForType. Trailing bytes
+// after the continuation are reported as ErrTrailingBytes. The returned error
+// is non-nil only when the file is truncated or r fails, in which case the
+// QuestFile is the zero value.
+func ReadIgnoreTypeErrors(r io.Reader) (QuestFile, []error, error) {
+	return read(r, true, false)
+}
+
+// read implements Read and ReadIgnoreTypeErrors. In strict mode the first
+// format violation is returned as the error; in lenient mode violations are
+// collected as warnings and reading continues. With ignoreTrailing set nothing
+// is read after the continuation section.
+func read(r io.Reader, lenient, ignoreTrailing bool) (QuestFile, []error, error) {
 	var q QuestFile
+	var warnings []error
 
 	// ── Header: 96 bytes ────────────────────────────────────────────────────
 	if err := binary.Read(r, binary.LittleEndian, &q.Header); err != nil {
 		if err == io.EOF {
-			return QuestFile{}, io.ErrUnexpectedEOF
+			return QuestFile{}, nil, io.ErrUnexpectedEOF
 		}
 
-		return QuestFile{}, err
+		return QuestFile{}, nil, err
 	}
 
 	// ── Exactly 7 objectives ────────────────────────────────────────────────
@@ -122,41 +290,47 @@ func Read(r io.Reader) (QuestFile, error) {
 			// io.ReadFull already converts EOF → ErrUnexpectedEOF when 0 bytes
 			// were read, but we normalise both cases for clarity.
 			if err == io.EOF {
-				return QuestFile{}, io.ErrUnexpectedEOF
+				return QuestFile{}, nil, io.ErrUnexpectedEOF
 			}
 
-			return QuestFile{}, err
+			return QuestFile{}, nil, err
 		}
 
-		objType := q.Objectives[i].Block[0]
-		nameLen := q.Objectives[i].Block[92]
+		objType := ObjectiveType(q.Objectives[i].Block[OffType])
+		nameLen := q.Objectives[i].Block[OffNameLen]
 
 		// ErrInvalidObjectiveType. Real files fill unused objective slots with
 		// 0xFF, so TypeUnused (0xFF) must be accepted as a valid no-op slot.
 		// Any other out-of-range value (5–254) is still an error.
 		if objType > TypeFIND && objType != TypeUnused {
-			return QuestFile{}, ErrInvalidObjectiveType
-		}
+			if !lenient {
+				return QuestFile{}, nil, ErrInvalidObjectiveType
+			}
 
-		// The name-length guard must also cover the unused (0xFF)
-		// slot. An unused slot should always have nameLen == 0; if it somehow
-		// does not, that is a malformed file. The original condition
-		// (objType <= TypeBRINGNPC) silently skipped unused slots, which
-		// could have caused a spurious name read on a junk byte at offset 92.
-		// We now require nameLen == 0 for every type that does not support
-		// names: KILL, QUESTITEM, BRINGNPC, and the unused sentinel.
-		if objType != TypeDROP && objType != TypeFIND && nameLen != 0 {
-			return QuestFile{}, ErrNameLengthForType
+			warnings = append(warnings, &ObjectiveError{Index: i, Err: ErrInvalidObjectiveType})
+		} else if objType != TypeDROP && objType != TypeFIND && nameLen != 0 {
+			// The name-length guard must also cover the unused (0xFF)
+			// slot. An unused slot should always have nameLen == 0; if it somehow
+			// does not, that is a malformed file. The original condition
+			// (objType <= TypeBRINGNPC) silently skipped unused slots, which
+			// could have caused a spurious name read on a junk byte at offset 92.
+			// We now require nameLen == 0 for every type that does not support
+			// names: KILL, QUESTITEM, BRINGNPC, and the unused sentinel.
+			if !lenient {
+				return QuestFile{}, nil, ErrNameLengthForType
+			}
+
+			warnings = append(warnings, &ObjectiveError{Index: i, Err: ErrNameLengthForType})
 		}
 
 		if nameLen > 0 {
 			q.Objectives[i].Name = make([]byte, nameLen)
 			if _, err := io.ReadFull(r, q.Objectives[i].Name); err != nil {
 				if err == io.EOF {
-					return QuestFile{}, io.ErrUnexpectedEOF
+					return QuestFile{}, nil, io.ErrUnexpectedEOF
 				}
 
-				return QuestFile{}, err
+				return QuestFile{}, nil, err
 			}
 		}
 	}
@@ -165,13 +339,17 @@ func Read(r io.Reader) (QuestFile, error) {
 	for i := range q.Continuation {
 		if err := binary.Read(r, binary.LittleEndian, &q.Continuation[i]); err != nil {
 			if err == io.EOF {
-				return QuestFile{}, io.ErrUnexpectedEOF
+				return QuestFile{}, nil, io.ErrUnexpectedEOF
 			}
 
-			return QuestFile{}, err
+			return QuestFile{}, nil, err
 		}
 	}
 
+	if ignoreTrailing {
+		return q, warnings, nil
+	}
+
 	// The second clause fires when err is non-nil AND not io.EOF, which would
 	// incorrectly return ErrTrailingBytes for legitimate read errors (e.g.
 	// a network timeout). A read error here means we successfully parsed the
@@ -180,10 +358,14 @@ func Read(r io.Reader) (QuestFile, error) {
 	var one [1]byte
 	n, _ := r.Read(one[:])
 	if n > 0 {
-		return QuestFile{}, ErrTrailingBytes
+		if !lenient {
+			return QuestFile{}, nil, ErrTrailingBytes
+		}
+
+		warnings = append(warnings, ErrTrailingBytes)
 	}
 
-	return q, nil
+	return q, warnings, nil
 }
 
 // Write writes q to w in A3 quest file binary format.
@@ -211,6 +393,37 @@ func Write(w io.Writer, q QuestFile) error {
 	return nil
 }
 
+// WriteOptions configures WriteChecked.
+type WriteOptions struct {
+	// MaxNameLen caps the length of objective names, for clients that cannot
+	// display the full MaxNameLength bytes. Zero, or any value above
+	// MaxNameLength, means the format maximum.
+	MaxNameLen int
+}
+
+// WriteChecked validates q and writes it to w like Write. Nothing is written
+// if q fails Validate or if an objective's Name is longer than
+// opts.MaxNameLen; the latter is reported as an *ObjectiveError wrapping
+// ErrNameTooLong that names the objective index and the name's length.
+func WriteChecked(w io.Writer, q QuestFile, opts WriteOptions) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+
+	limit := opts.MaxNameLen
+	if limit <= 0 || limit > MaxNameLength {
+		limit = MaxNameLength
+	}
+
+	for i := range q.Objectives {
+		if n := len(q.Objectives[i].Name); n > limit {
+			return &ObjectiveError{Index: i, Err: fmt.Errorf("%w: %d bytes, limit %d", ErrNameTooLong, n, limit)}
+		}
+	}
+
+	return Write(w, q)
+}
+
 // QuestID returns the quest ID (lower 16 bits of the first header field).
 func (h *QuestHeader) QuestID() uint16 {
 	return binary.LittleEndian.Uint16(h.QuestIDRaw[:2])
@@ -232,16 +445,64 @@ func (h *QuestHeader) SetGivenNPCID(id uint16) {
 }
 
 // ObjectiveType returns the objective type byte at offset 0 in the block.
-func (o *Objective) ObjectiveType() uint8 {
-	return o.Block[0]
+func (o *Objective) ObjectiveType() ObjectiveType {
+	return ObjectiveType(o.Block[OffType])
+}
+
+// TypeName returns the name of o's type byte, as ObjectiveTypeName does.
+func (o *Objective) TypeName() string {
+	return ObjectiveTypeName(o.Block[OffType])
 }
 
 // IsUnused reports whether this objective slot is an unused (0xFF-filled) slot.
 func (o *Objective) IsUnused() bool {
-	return o.Block[0] == TypeUnused
+	return o.ObjectiveType() == TypeUnused
+}
+
+// IsActive reports whether this objective slot is in use; it is the negation
+// of IsUnused.
+func (o *Objective) IsActive() bool {
+	return !o.IsUnused()
+}
+
+// HasObjectives reports whether any of the quest's objective slots is active.
+func (q *QuestFile) HasObjectives() bool {
+	for i := range q.Objectives {
+		if q.Objectives[i].IsActive() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ObjectiveCount returns the number of active objective slots.
+func (q *QuestFile) ObjectiveCount() int {
+	n := 0
+	for i := range q.Objectives {
+		if q.Objectives[i].IsActive() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// ActiveObjectives returns pointers to the active objective slots of q in
+// slot order, skipping unused ones, so callers can range over the meaningful
+// objectives and modify them in place. Its length is ObjectiveCount().
+func (q *QuestFile) ActiveObjectives() []*Objective {
+	var list []*Objective
+	for i := range q.Objectives {
+		if q.Objectives[i].IsActive() {
+			list = append(list, &q.Objectives[i])
+		}
+	}
+
+	return list
 }
 
 // NameLength returns the name length byte at offset 92 in the block.
 func (o *Objective) NameLength() uint8 {
-	return o.Block[92]
+	return o.Block[OffNameLen]
 }
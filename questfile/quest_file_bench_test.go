@@ -31,7 +31,7 @@ func BenchmarkWrite_Minimal(b *testing.B) {
 func BenchmarkRead_Maximal(b *testing.B) {
 	q := minimalValidQuestFile()
 	for i := range q.Objectives {
-		q.Objectives[i].Block[0] = TypeDROP
+		q.Objectives[i].Block[0] = byte(TypeDROP)
 		q.Objectives[i].Block[92] = 255
 		q.Objectives[i].Name = make([]byte, 255)
 	}
@@ -49,7 +49,7 @@ func BenchmarkRead_Maximal(b *testing.B) {
 func BenchmarkWrite_Maximal(b *testing.B) {
 	q := minimalValidQuestFile()
 	for i := range q.Objectives {
-		q.Objectives[i].Block[0] = TypeDROP
+		q.Objectives[i].Block[0] = byte(TypeDROP)
 		q.Objectives[i].Block[92] = 255
 		q.Objectives[i].Name = make([]byte, 255)
 	}
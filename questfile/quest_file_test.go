@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/project-agonyl/agonyl-utils-go/utils"
 )
 
 // --- Test helpers ---
@@ -27,7 +29,8 @@ func minimalValidQuestFile() QuestFile {
 	binary.LittleEndian.PutUint16(q.Header.RewardSlot2[:2], UnusedRewardItemCode)
 	binary.LittleEndian.PutUint16(q.Header.RewardSlot3[:2], UnusedRewardItemCode)
 	for i := range q.Objectives {
-		q.Objectives[i].Block[0] = TypeKILL
+		q.Objectives[i].Block[0] = byte(TypeKILL)
+		q.Objectives[i].Block[OffKillCount] = 1
 	}
 	q.Continuation[0] = UnusedContinuation
 	q.Continuation[1] = UnusedContinuation
@@ -211,22 +214,22 @@ func TestRead_IncompleteObjectiveBlock(t *testing.T) {
 
 func TestRead_ValidObjectiveTypes0to4(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeKILL
-	q.Objectives[1].Block[0] = TypeQUESTITEM
-	q.Objectives[2].Block[0] = TypeBRINGNPC
-	q.Objectives[3].Block[0] = TypeDROP
-	q.Objectives[4].Block[0] = TypeFIND
-	q.Objectives[5].Block[0] = TypeKILL
-	q.Objectives[6].Block[0] = TypeKILL
+	q.Objectives[0].Block[0] = byte(TypeKILL)
+	q.Objectives[1].Block[0] = byte(TypeQUESTITEM)
+	q.Objectives[2].Block[0] = byte(TypeBRINGNPC)
+	q.Objectives[3].Block[0] = byte(TypeDROP)
+	q.Objectives[4].Block[0] = byte(TypeFIND)
+	q.Objectives[5].Block[0] = byte(TypeKILL)
+	q.Objectives[6].Block[0] = byte(TypeKILL)
 	var buf bytes.Buffer
 	require.NoError(t, Write(&buf, q))
 	read, err := Read(&buf)
 	require.NoError(t, err)
-	assert.Equal(t, uint8(TypeKILL), read.Objectives[0].ObjectiveType())
-	assert.Equal(t, uint8(TypeQUESTITEM), read.Objectives[1].ObjectiveType())
-	assert.Equal(t, uint8(TypeBRINGNPC), read.Objectives[2].ObjectiveType())
-	assert.Equal(t, uint8(TypeDROP), read.Objectives[3].ObjectiveType())
-	assert.Equal(t, uint8(TypeFIND), read.Objectives[4].ObjectiveType())
+	assert.Equal(t, TypeKILL, read.Objectives[0].ObjectiveType())
+	assert.Equal(t, TypeQUESTITEM, read.Objectives[1].ObjectiveType())
+	assert.Equal(t, TypeBRINGNPC, read.Objectives[2].ObjectiveType())
+	assert.Equal(t, TypeDROP, read.Objectives[3].ObjectiveType())
+	assert.Equal(t, TypeFIND, read.Objectives[4].ObjectiveType())
 }
 
 func TestRead_InvalidObjectiveType(t *testing.T) {
@@ -254,14 +257,14 @@ func TestRead_TypeUnusedAccepted(t *testing.T) {
 	require.NoError(t, Write(&buf, q))
 	read, err := Read(&buf)
 	require.NoError(t, err)
-	assert.Equal(t, uint8(TypeUnused), read.Objectives[2].ObjectiveType())
+	assert.Equal(t, TypeUnused, read.Objectives[2].ObjectiveType())
 	assert.True(t, read.Objectives[2].IsUnused())
 	assert.False(t, read.Objectives[0].IsUnused())
 }
 
 func TestRead_TypeUnusedWithNameLengthError(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[1].Block[0] = TypeUnused
+	q.Objectives[1].Block[0] = byte(TypeUnused)
 	for i := 1; i < 92; i++ {
 		q.Objectives[1].Block[i] = 0xFF
 	}
@@ -276,25 +279,61 @@ func TestRead_TypeUnusedWithNameLengthError(t *testing.T) {
 
 func TestObjective_IsUnused(t *testing.T) {
 	var o Objective
-	o.Block[0] = TypeKILL
+	o.Block[0] = byte(TypeKILL)
 	assert.False(t, o.IsUnused())
-	o.Block[0] = TypeUnused
+	o.Block[0] = byte(TypeUnused)
 	assert.True(t, o.IsUnused())
-	o.Block[0] = TypeDROP
+	o.Block[0] = byte(TypeDROP)
 	assert.False(t, o.IsUnused())
 }
 
+func TestObjective_IsActive(t *testing.T) {
+	var o Objective
+	o.Block[0] = byte(TypeKILL)
+	assert.True(t, o.IsActive())
+	o.Block[0] = byte(TypeUnused)
+	assert.False(t, o.IsActive())
+}
+
+func TestQuestFile_ActiveObjectives(t *testing.T) {
+	q := NewQuestFile()
+	assert.Empty(t, q.ActiveObjectives())
+
+	require.NoError(t, q.Objectives[1].SetKillTarget(1, 2, 3))
+	q.Objectives[4].SetFindTarget(1, 2, 3)
+	list := q.ActiveObjectives()
+	require.Len(t, list, q.ObjectiveCount())
+	assert.Same(t, &q.Objectives[1], list[0])
+	assert.Same(t, &q.Objectives[4], list[1])
+
+	list[0].SetKillCount(9)
+	assert.Equal(t, uint16(9), q.Objectives[1].KillCount(), "pointers modify the quest in place")
+}
+
+func TestQuestFile_HasObjectives(t *testing.T) {
+	q := minimalValidQuestFile()
+	assert.True(t, q.HasObjectives())
+
+	for i := range q.Objectives {
+		q.Objectives[i] = unusedObjective()
+	}
+	assert.False(t, q.HasObjectives())
+
+	q.Objectives[NumObjectives-1].Block[0] = byte(TypeFIND)
+	assert.True(t, q.HasObjectives())
+}
+
 func TestRoundTrip_WithUnusedObjectiveSlots(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeKILL
-	q.Objectives[1].Block[0] = TypeUnused
+	q.Objectives[0].Block[0] = byte(TypeKILL)
+	q.Objectives[1].Block[0] = byte(TypeUnused)
 	for i := range q.Objectives[1].Block {
 		q.Objectives[1].Block[i] = 0xFF
 	}
 	q.Objectives[1].Block[92], q.Objectives[1].Block[93] = 0, 0
 	q.Objectives[1].Block[94], q.Objectives[1].Block[95] = 0, 0
 	q.Objectives[1].Name = nil
-	q.Objectives[2].Block[0] = TypeDROP
+	q.Objectives[2].Block[0] = byte(TypeDROP)
 	q.Objectives[2].Block[92] = 3
 	q.Objectives[2].Name = []byte("XYZ")
 	var buf bytes.Buffer
@@ -339,7 +378,7 @@ func TestRead_ObjectiveFieldsParsed(t *testing.T) {
 
 func TestRead_NameLengthZeroForKILL(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeKILL
+	q.Objectives[0].Block[0] = byte(TypeKILL)
 	q.Objectives[0].Block[92] = 0
 	var buf bytes.Buffer
 	require.NoError(t, Write(&buf, q))
@@ -349,7 +388,7 @@ func TestRead_NameLengthZeroForKILL(t *testing.T) {
 
 func TestRead_NameLengthGreaterThanZeroForKILLError(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeKILL
+	q.Objectives[0].Block[0] = byte(TypeKILL)
 	q.Objectives[0].Block[92] = 5
 	q.Objectives[0].Name = make([]byte, 5)
 	var buf bytes.Buffer
@@ -361,7 +400,7 @@ func TestRead_NameLengthGreaterThanZeroForKILLError(t *testing.T) {
 
 func TestRead_DROPWithNameLength10(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeDROP
+	q.Objectives[0].Block[0] = byte(TypeDROP)
 	q.Objectives[0].Block[92] = 10
 	q.Objectives[0].Name = make([]byte, 10)
 	for i := range q.Objectives[0].Name {
@@ -378,21 +417,21 @@ func TestRead_DROPWithNameLength10(t *testing.T) {
 
 func TestRead_Type3WithNameLengthZeroValid(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeDROP
+	q.Objectives[0].Block[0] = byte(TypeDROP)
 	q.Objectives[0].Block[92] = 0
 	q.Objectives[0].Name = nil
 	var buf bytes.Buffer
 	require.NoError(t, Write(&buf, q))
 	read, err := Read(&buf)
 	require.NoError(t, err)
-	assert.Equal(t, uint8(TypeDROP), read.Objectives[0].ObjectiveType())
+	assert.Equal(t, TypeDROP, read.Objectives[0].ObjectiveType())
 	assert.Len(t, read.Objectives[0].Name, 0)
 }
 
 func TestRead_TruncatedName(t *testing.T) {
 	// Build raw: header + obj1 with NameLength=20 but only 10 bytes after block
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeDROP
+	q.Objectives[0].Block[0] = byte(TypeDROP)
 	q.Objectives[0].Block[92] = 20
 	q.Objectives[0].Name = make([]byte, 20) // we write 20, then truncate the buffer
 	var buf bytes.Buffer
@@ -407,13 +446,13 @@ func TestRead_TruncatedName(t *testing.T) {
 
 func TestRead_MultipleNamedObjectives(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeDROP
+	q.Objectives[0].Block[0] = byte(TypeDROP)
 	q.Objectives[0].Block[92] = 5
 	q.Objectives[0].Name = []byte("AAAAA")
-	q.Objectives[1].Block[0] = TypeFIND
+	q.Objectives[1].Block[0] = byte(TypeFIND)
 	q.Objectives[1].Block[92] = 7
 	q.Objectives[1].Name = []byte("BBBBBBB")
-	q.Objectives[2].Block[0] = TypeKILL
+	q.Objectives[2].Block[0] = byte(TypeKILL)
 	q.Objectives[2].Block[92] = 0
 	var buf bytes.Buffer
 	require.NoError(t, Write(&buf, q))
@@ -426,7 +465,7 @@ func TestRead_MultipleNamedObjectives(t *testing.T) {
 
 func TestRead_NameLength255(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeFIND
+	q.Objectives[0].Block[0] = byte(TypeFIND)
 	q.Objectives[0].Block[92] = 255
 	q.Objectives[0].Name = make([]byte, 255)
 	for i := range q.Objectives[0].Name {
@@ -494,6 +533,52 @@ func TestRead_TrailingBytesError(t *testing.T) {
 	assert.ErrorIs(t, err, ErrTrailingBytes)
 }
 
+func TestReadIgnoreTypeErrors_CollectsWarnings(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[2].Block[0] = 9
+	q.Objectives[4].Block[OffNameLen] = 3
+	q.Objectives[4].Name = []byte("abc")
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	buf.WriteByte(0x00)
+
+	read, warnings, err := ReadIgnoreTypeErrors(&buf)
+	require.NoError(t, err)
+	require.Len(t, warnings, 3)
+	assert.ErrorIs(t, warnings[0], ErrInvalidObjectiveType)
+	assert.ErrorIs(t, warnings[1], ErrNameLengthForType)
+	assert.ErrorIs(t, warnings[2], ErrTrailingBytes)
+	var objErr *ObjectiveError
+	require.ErrorAs(t, warnings[1], &objErr)
+	assert.Equal(t, 4, objErr.Index)
+
+	assert.Equal(t, ObjectiveType(9), read.Objectives[2].ObjectiveType())
+	assert.Equal(t, []byte("abc"), read.Objectives[4].Name)
+	assert.Equal(t, q.Continuation, read.Continuation)
+}
+
+func TestReadIgnoreTypeErrors_ValidFile(t *testing.T) {
+	q := minimalValidQuestFile()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+
+	read, warnings, err := ReadIgnoreTypeErrors(&buf)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, q, read)
+}
+
+func TestReadIgnoreTypeErrors_TruncationIsFatal(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block[0] = 9
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+
+	_, warnings, err := ReadIgnoreTypeErrors(bytes.NewReader(buf.Bytes()[:MinFileSize-1]))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Nil(t, warnings)
+}
+
 // --- 4. Structure tests ---
 
 func TestRead_MinimalValidFile(t *testing.T) {
@@ -510,7 +595,7 @@ func TestRead_MinimalValidFile(t *testing.T) {
 func TestRead_MaximalFileSize(t *testing.T) {
 	q := minimalValidQuestFile()
 	for i := range q.Objectives {
-		q.Objectives[i].Block[0] = TypeDROP
+		q.Objectives[i].Block[0] = byte(TypeDROP)
 		q.Objectives[i].Block[92] = 255
 		q.Objectives[i].Name = make([]byte, 255)
 	}
@@ -518,6 +603,7 @@ func TestRead_MaximalFileSize(t *testing.T) {
 	require.NoError(t, Write(&buf, q))
 	expectedSize := MinFileSize + 7*255
 	assert.Equal(t, expectedSize, buf.Len())
+	assert.Equal(t, expectedSize, q.Size())
 	read, err := Read(&buf)
 	require.NoError(t, err)
 	for i := range read.Objectives {
@@ -542,10 +628,10 @@ func TestRoundTrip_BinaryIdentityMinimal(t *testing.T) {
 
 func TestRoundTrip_BinaryIdentityWithNames(t *testing.T) {
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeDROP
+	q.Objectives[0].Block[0] = byte(TypeDROP)
 	q.Objectives[0].Block[92] = 5
 	q.Objectives[0].Name = []byte("HELLO")
-	q.Objectives[3].Block[0] = TypeFIND
+	q.Objectives[3].Block[0] = byte(TypeFIND)
 	q.Objectives[3].Block[92] = 3
 	q.Objectives[3].Name = []byte("XYZ")
 	var buf bytes.Buffer
@@ -609,6 +695,33 @@ func TestRead_TruncatedAtVariousOffsets(t *testing.T) {
 	}
 }
 
+func TestRead_ReadErrorAtVariousOffsets(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[2].Block[OffType] = byte(TypeDROP)
+	q.Objectives[2].Block[OffNameLen] = 4
+	q.Objectives[2].Name = []byte("Wolf")
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	raw := buf.Bytes()
+	for n := 0; n < len(raw); n++ {
+		_, err := Read(utils.FailAfterReader(bytes.NewReader(raw), n))
+		require.ErrorIs(t, err, utils.ErrInjected, "read failure after %d bytes", n)
+	}
+}
+
+func TestWrite_WriteErrorAtVariousOffsets(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[2].Block[OffType] = byte(TypeDROP)
+	q.Objectives[2].Block[OffNameLen] = 4
+	q.Objectives[2].Name = []byte("Wolf")
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	for n := 0; n < buf.Len(); n++ {
+		err := Write(utils.FailAfterWriter(io.Discard, n), q)
+		require.ErrorIs(t, err, utils.ErrInjected, "write failure after %d bytes", n)
+	}
+}
+
 func TestRead_RandomGarbageErrors(t *testing.T) {
 	rng := bytes.NewReader(bytes.Repeat([]byte{0xDE, 0xAD, 0xBE, 0xEF}, 200))
 	_, err := Read(rng)
@@ -618,7 +731,7 @@ func TestRead_RandomGarbageErrors(t *testing.T) {
 func TestRead_MalformedNameOverflow(t *testing.T) {
 	// Valid header + 7 objectives; one objective claims NameLength=200 but we provide only 5 bytes
 	q := minimalValidQuestFile()
-	q.Objectives[0].Block[0] = TypeDROP
+	q.Objectives[0].Block[0] = byte(TypeDROP)
 	q.Objectives[0].Block[92] = 200
 	q.Objectives[0].Name = make([]byte, 5) // only 5 bytes
 	var buf bytes.Buffer
@@ -667,20 +780,154 @@ func TestRead_LittleEndianQuestID(t *testing.T) {
 }
 
 func TestWrite_InvalidWriter(t *testing.T) {
-	err := Write(&errWriter{}, minimalValidQuestFile())
-	assert.Error(t, err)
-}
-
-type errWriter struct{}
-
-func (errWriter) Write([]byte) (int, error) {
-	return 0, io.ErrShortWrite
+	err := Write(utils.FailAfterWriter(io.Discard, 0), minimalValidQuestFile())
+	assert.ErrorIs(t, err, utils.ErrInjected)
 }
 
 func TestHeader_Size(t *testing.T) {
 	assert.Equal(t, HeaderSize, binary.Size(QuestHeader{}))
 }
 
+func TestObjective_FieldOffsets(t *testing.T) {
+	// Documented objective block layout.
+	assert.Equal(t, 0, OffType)
+	assert.Equal(t, 4, OffMapID)
+	assert.Equal(t, 8, OffLocationID)
+	assert.Equal(t, 12, OffRadius)
+	assert.Equal(t, 16, OffMonsterID)
+	assert.Equal(t, 20, OffKillCount)
+	assert.Equal(t, 24, OffQuestItemID)
+	assert.Equal(t, 56, OffItemCount)
+	assert.Equal(t, 76, OffDropRate1)
+	assert.Equal(t, 80, OffDropRate2)
+	assert.Equal(t, 84, OffDropRate3)
+	assert.Equal(t, 92, OffNameLen)
+	assert.Less(t, OffNameLen, ObjectiveBlockSize)
+}
+
 func TestQuestFile_MinFileSizeConstant(t *testing.T) {
 	assert.Equal(t, 780, MinFileSize)
 }
+
+func TestObjectiveType_String(t *testing.T) {
+	tests := []struct {
+		typ  ObjectiveType
+		want string
+	}{
+		{TypeKILL, "KILL"},
+		{TypeQUESTITEM, "QUESTITEM"},
+		{TypeBRINGNPC, "BRINGNPC"},
+		{TypeDROP, "DROP"},
+		{TypeFIND, "FIND"},
+		{TypeUnused, "UNUSED"},
+		{ObjectiveType(9), "ObjectiveType(9)"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.typ.String())
+	}
+}
+
+func TestObjectiveTypeName(t *testing.T) {
+	tests := []struct {
+		typ  uint8
+		want string
+	}{
+		{0, "KILL"},
+		{1, "QUESTITEM"},
+		{2, "BRINGNPC"},
+		{3, "DROP"},
+		{4, "FIND"},
+		{0xFF, "UNUSED"},
+		{9, "UNKNOWN(9)"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ObjectiveTypeName(tt.typ))
+
+		var o Objective
+		o.Block[OffType] = tt.typ
+		assert.Equal(t, tt.want, o.TypeName())
+	}
+}
+
+func TestObjectiveType_ValuesMatchFormat(t *testing.T) {
+	// The typed constants must keep the on-disk byte values.
+	assert.Equal(t, []byte{0, 1, 2, 3, 4, 0xFF}, []byte{
+		byte(TypeKILL), byte(TypeQUESTITEM), byte(TypeBRINGNPC),
+		byte(TypeDROP), byte(TypeFIND), byte(TypeUnused),
+	})
+}
+
+func TestWriteChecked_DefaultMatchesWrite(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].SetFindTarget(1, 2, 3)
+	q.Objectives[0].Name = bytes.Repeat([]byte("a"), MaxNameLength)
+	q.Objectives[0].Block[OffNameLen] = MaxNameLength
+
+	var checked, plain bytes.Buffer
+	require.NoError(t, WriteChecked(&checked, q, WriteOptions{}))
+	require.NoError(t, Write(&plain, q))
+	assert.Equal(t, plain.Bytes(), checked.Bytes())
+}
+
+func TestWriteChecked_MaxNameLen(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[4].SetFindTarget(1, 2, 3)
+	q.Objectives[4].Name = bytes.Repeat([]byte("a"), 65)
+	q.Objectives[4].Block[OffNameLen] = 65
+
+	var buf bytes.Buffer
+	err := WriteChecked(&buf, q, WriteOptions{MaxNameLen: 64})
+	var objErr *ObjectiveError
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, 4, objErr.Index)
+	assert.ErrorIs(t, err, ErrNameTooLong)
+	assert.EqualError(t, err, "objective 4: questfile: objective name too long: 65 bytes, limit 64")
+	assert.Zero(t, buf.Len(), "nothing is written on error")
+
+	require.NoError(t, WriteChecked(&buf, q, WriteOptions{MaxNameLen: 65}))
+}
+
+func TestWriteChecked_RejectsInvalid(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block[OffType] = 9
+
+	var buf bytes.Buffer
+	assert.ErrorIs(t, WriteChecked(&buf, q, WriteOptions{}), ErrInvalidObjectiveType)
+	assert.Zero(t, buf.Len())
+}
+
+func TestReadWithOptions_StrictTrailing(t *testing.T) {
+	q := minimalValidQuestFile()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	buf.WriteString("next")
+	data := buf.Bytes()
+
+	_, err := ReadWithOptions(bytes.NewReader(data), ReadOptions{StrictTrailing: true})
+	assert.ErrorIs(t, err, ErrTrailingBytes)
+
+	r := bytes.NewBuffer(data)
+	got, err := ReadWithOptions(r, ReadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, q, got)
+	assert.Equal(t, "next", r.String(), "nothing after the continuation is consumed")
+}
+
+func TestNewQuestFile(t *testing.T) {
+	q := NewQuestFile()
+	require.NoError(t, q.Validate())
+	assert.False(t, q.HasObjectives())
+	for i := range q.Objectives {
+		assert.Equal(t, unusedObjective(), q.Objectives[i])
+	}
+	assert.Equal(t, [3]uint32{UnusedContinuation, UnusedContinuation, UnusedContinuation}, q.Continuation)
+	assert.Equal(t, uint16(UnusedRewardItemCode), binary.LittleEndian.Uint16(q.Header.RewardSlot1[:2]))
+	assert.Equal(t, uint16(UnusedRewardItemCode), binary.LittleEndian.Uint16(q.Header.RewardSlot2[:2]))
+	assert.Equal(t, uint16(UnusedRewardItemCode), binary.LittleEndian.Uint16(q.Header.RewardSlot3[:2]))
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	read, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, q, read)
+}
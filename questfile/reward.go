@@ -0,0 +1,39 @@
+package questfile
+
+import "encoding/binary"
+
+// Rewards returns the used reward slots of h in slot order, pairing each item
+// code with its count. Slots holding UnusedRewardItemCode are left out, so
+// the result has between zero and three items.
+func (h *QuestHeader) Rewards() []RewardItem {
+	var items []RewardItem
+	for _, r := range rewardSlots(h) {
+		if r.code != UnusedRewardItemCode {
+			items = append(items, RewardItem{Code: r.code, Count: r.count})
+		}
+	}
+
+	return items
+}
+
+// SetReward sets reward slot slot (0, 1 or 2) to count items of code. The
+// padding bytes after the code and after the count are left unchanged. To
+// empty a slot pass UnusedRewardItemCode with a count of zero, the form
+// Validate expects. It returns ErrRewardSlot, leaving h untouched, if slot is
+// out of range.
+func (h *QuestHeader) SetReward(slot int, code uint16, count uint8) error {
+	var item *[4]byte
+	switch slot {
+	case 0:
+		item, h.Count1 = &h.RewardSlot1, count
+	case 1:
+		item, h.Count2 = &h.RewardSlot2, count
+	case 2:
+		item, h.Count3 = &h.RewardSlot3, count
+	default:
+		return ErrRewardSlot
+	}
+
+	binary.LittleEndian.PutUint16(item[:2], code)
+	return nil
+}
@@ -0,0 +1,38 @@
+package questfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewards(t *testing.T) {
+	q := minimalValidQuestFile()
+	assert.Empty(t, q.Header.Rewards())
+
+	require.NoError(t, q.Header.SetReward(2, 501, 3))
+	require.NoError(t, q.Header.SetReward(0, 100, 1))
+	assert.Equal(t, []RewardItem{{Code: 100, Count: 1}, {Code: 501, Count: 3}}, q.Header.Rewards())
+	assert.NoError(t, q.Validate())
+
+	require.NoError(t, q.Header.SetReward(0, UnusedRewardItemCode, 0))
+	assert.Equal(t, []RewardItem{{Code: 501, Count: 3}}, q.Header.Rewards())
+}
+
+func TestSetReward_PreservesPadding(t *testing.T) {
+	var h QuestHeader
+	h.RewardSlot2 = [4]byte{0, 0, 0xAA, 0xBB}
+	h.Count2Pad = [3]byte{1, 2, 3}
+	require.NoError(t, h.SetReward(1, 0x1234, 9))
+	assert.Equal(t, [4]byte{0x34, 0x12, 0xAA, 0xBB}, h.RewardSlot2)
+	assert.Equal(t, uint8(9), h.Count2)
+	assert.Equal(t, [3]byte{1, 2, 3}, h.Count2Pad)
+}
+
+func TestSetReward_SlotOutOfRange(t *testing.T) {
+	var h QuestHeader
+	assert.ErrorIs(t, h.SetReward(3, 1, 1), ErrRewardSlot)
+	assert.ErrorIs(t, h.SetReward(-1, 1, 1), ErrRewardSlot)
+	assert.Equal(t, QuestHeader{}, h)
+}
@@ -0,0 +1,158 @@
+package questfile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrInvalidStringTable is returned by ReadJoined when a line of the string
+// table cannot be parsed.
+var ErrInvalidStringTable = errors.New("questfile: invalid string table")
+
+// nameEscaper escapes the bytes that would break the one-line-per-name string
+// table format. All other bytes, including CP949 text, are written verbatim.
+var nameEscaper = []struct{ raw, escaped []byte }{
+	{[]byte(`\`), []byte(`\\`)},
+	{[]byte("\n"), []byte(`\n`)},
+	{[]byte("\r"), []byte(`\r`)},
+}
+
+// WriteSplit writes q to w with every objective name removed (Name empty and
+// name-length byte zero) and appends the names to table, one line per named
+// objective:
+//
+//	<quest ID> <objective index> <name>\n
+//
+// The quest ID and index are decimal. The name is written byte for byte
+// except that backslash, LF and CR are escaped as \\, \n and \r, so names in
+// any single-byte-safe encoding such as CP949 stay readable for translators.
+// Because each line is keyed by quest ID, the string tables of many quests
+// can be written to the same table writer. q itself is not modified.
+func WriteSplit(w io.Writer, table io.Writer, q QuestFile) error {
+	var lines bytes.Buffer
+	for i := range q.Objectives {
+		o := &q.Objectives[i]
+		if len(o.Name) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&lines, "%d %d ", q.Header.QuestID(), i)
+		lines.Write(escapeName(o.Name))
+		lines.WriteByte('\n')
+		o.Name = nil
+		o.Block[OffNameLen] = 0
+	}
+
+	if err := Write(w, q); err != nil {
+		return err
+	}
+
+	_, err := table.Write(lines.Bytes())
+	return err
+}
+
+// ReadJoined reads a quest written by WriteSplit from r and restores its
+// objective names from table, the string table in the format WriteSplit
+// produces. Lines for other quest IDs are ignored, so one table can serve a
+// whole quest set; blank lines are skipped. Each restored name also sets the
+// objective's name-length byte. Malformed lines return ErrInvalidStringTable
+// with the line number; a name for an objective that cannot carry one, or one
+// longer than MaxNameLength, returns an *ObjectiveError wrapping
+// ErrNameLengthForType or ErrNameTooLong.
+func ReadJoined(r io.Reader, table io.Reader) (QuestFile, error) {
+	q, err := Read(r)
+	if err != nil {
+		return QuestFile{}, err
+	}
+
+	scanner := bufio.NewScanner(table)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Bytes()
+		if len(text) == 0 {
+			continue
+		}
+
+		fields := bytes.SplitN(text, []byte(" "), 3)
+		if len(fields) != 3 {
+			return QuestFile{}, fmt.Errorf("%w: line %d: expected quest ID, objective index and name", ErrInvalidStringTable, line)
+		}
+
+		questID, err := strconv.ParseUint(string(fields[0]), 10, 16)
+		if err != nil {
+			return QuestFile{}, fmt.Errorf("%w: line %d: quest ID: %v", ErrInvalidStringTable, line, err)
+		}
+
+		index, err := strconv.Atoi(string(fields[1]))
+		if err != nil || index < 0 || index >= NumObjectives {
+			return QuestFile{}, fmt.Errorf("%w: line %d: objective index %q", ErrInvalidStringTable, line, fields[1])
+		}
+
+		if uint16(questID) != q.Header.QuestID() {
+			continue
+		}
+
+		name, err := unescapeName(fields[2])
+		if err != nil {
+			return QuestFile{}, fmt.Errorf("%w: line %d: %v", ErrInvalidStringTable, line, err)
+		}
+
+		o := &q.Objectives[index]
+		if !supportsName(o.ObjectiveType()) {
+			return QuestFile{}, &ObjectiveError{Index: index, Err: ErrNameLengthForType}
+		}
+
+		if len(name) > MaxNameLength {
+			return QuestFile{}, &ObjectiveError{Index: index, Err: ErrNameTooLong}
+		}
+
+		o.Name = name
+		o.Block[OffNameLen] = uint8(len(name))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return QuestFile{}, err
+	}
+
+	return q, nil
+}
+
+func escapeName(name []byte) []byte {
+	out := name
+	for _, e := range nameEscaper {
+		out = bytes.ReplaceAll(out, e.raw, e.escaped)
+	}
+
+	return out
+}
+
+func unescapeName(escaped []byte) ([]byte, error) {
+	name := make([]byte, 0, len(escaped))
+	for i := 0; i < len(escaped); i++ {
+		if escaped[i] != '\\' {
+			name = append(name, escaped[i])
+			continue
+		}
+
+		i++
+		if i == len(escaped) {
+			return nil, errors.New("trailing backslash")
+		}
+
+		switch escaped[i] {
+		case '\\':
+			name = append(name, '\\')
+		case 'n':
+			name = append(name, '\n')
+		case 'r':
+			name = append(name, '\r')
+		default:
+			return nil, fmt.Errorf("unknown escape \\%c", escaped[i])
+		}
+	}
+
+	return name, nil
+}
@@ -0,0 +1,85 @@
+package questfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSplit_ReadJoined_RoundTrip(t *testing.T) {
+	q := namedQuest(42)
+	q.Objectives[3].SetFindTarget(4, 5, 6)
+	q.Objectives[3].Name = []byte("Line\\one\nline two\r")
+	q.Objectives[3].Block[OffNameLen] = byte(len(q.Objectives[3].Name))
+	original := encodeQuest(t, q)
+
+	var bin, table bytes.Buffer
+	require.NoError(t, WriteSplit(&bin, &table, q))
+	assert.Equal(t, "42 1 Hidden Cave\n42 3 Line\\\\one\\nline two\\r\n", table.String())
+	assert.Equal(t, original, encodeQuest(t, q), "q must not be modified")
+
+	stripped, err := Read(bytes.NewReader(bin.Bytes()))
+	require.NoError(t, err)
+	for i := range stripped.Objectives {
+		assert.Zero(t, stripped.Objectives[i].NameLength())
+		assert.Empty(t, stripped.Objectives[i].Name)
+	}
+
+	joined, err := ReadJoined(bytes.NewReader(bin.Bytes()), &table)
+	require.NoError(t, err)
+	assert.Equal(t, original, encodeQuest(t, joined))
+}
+
+func TestReadJoined_SharedTable(t *testing.T) {
+	var bin1, bin2, table bytes.Buffer
+	a, b := namedQuest(1), namedQuest(2)
+	b.Objectives[1].Name = []byte("Other Cave")
+	b.Objectives[1].Block[OffNameLen] = byte(len(b.Objectives[1].Name))
+	require.NoError(t, WriteSplit(&bin1, &table, a))
+	require.NoError(t, WriteSplit(&bin2, &table, b))
+	table.WriteString("\n")
+
+	got, err := ReadJoined(&bin2, bytes.NewReader(table.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, "Other Cave", string(got.Objectives[1].Name))
+
+	got, err = ReadJoined(&bin1, bytes.NewReader(table.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, "Hidden Cave", string(got.Objectives[1].Name))
+}
+
+func TestReadJoined_Errors(t *testing.T) {
+	var bin bytes.Buffer
+	require.NoError(t, WriteSplit(&bin, &bytes.Buffer{}, minimalValidQuestFile()))
+
+	tests := []struct {
+		name  string
+		table string
+		want  error
+	}{
+		{"missing name", "1 0\n", ErrInvalidStringTable},
+		{"bad quest id", "x 0 name\n", ErrInvalidStringTable},
+		{"bad index", "1 7 name\n", ErrInvalidStringTable},
+		{"bad escape", "1 0 a\\tb\n", ErrInvalidStringTable},
+		{"type without names", "1 0 name\n", ErrNameLengthForType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadJoined(bytes.NewReader(bin.Bytes()), strings.NewReader(tt.table))
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestReadJoined_NameTooLong(t *testing.T) {
+	var bin bytes.Buffer
+	q := minimalValidQuestFile()
+	q.Objectives[0].SetFindTarget(1, 1, 1)
+	require.NoError(t, WriteSplit(&bin, &bytes.Buffer{}, q))
+
+	_, err := ReadJoined(&bin, strings.NewReader("1 0 "+strings.Repeat("a", MaxNameLength+1)+"\n"))
+	assert.ErrorIs(t, err, ErrNameTooLong)
+}
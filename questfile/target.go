@@ -0,0 +1,62 @@
+package questfile
+
+import "encoding/binary"
+
+// Offsets of the identified fields within QuestHeader.TargetNPCBlock. They
+// are provisional: they match the quest data examined so far, but the meaning
+// of the other bytes (2–3, 6–7 and 13–23) is unknown, and those bytes are
+// preserved as-is by every setter.
+const (
+	TargetOffNPCID = 0  // uint16: target NPC ID
+	TargetOffMapID = 4  // uint16: map the target NPC stands on
+	TargetOffX     = 8  // uint16: target NPC X coordinate
+	TargetOffY     = 10 // uint16: target NPC Y coordinate
+	TargetOffFlag  = 12 // uint8:  target NPC flag, meaning not yet known
+)
+
+// TargetNPCID returns the target NPC ID stored at TargetOffNPCID.
+func (h *QuestHeader) TargetNPCID() uint16 {
+	return binary.LittleEndian.Uint16(h.TargetNPCBlock[TargetOffNPCID:])
+}
+
+// SetTargetNPCID sets the target NPC ID, leaving the rest of the block
+// unchanged.
+func (h *QuestHeader) SetTargetNPCID(id uint16) {
+	binary.LittleEndian.PutUint16(h.TargetNPCBlock[TargetOffNPCID:], id)
+}
+
+// TargetNPCMap returns the map ID stored at TargetOffMapID.
+func (h *QuestHeader) TargetNPCMap() uint16 {
+	return binary.LittleEndian.Uint16(h.TargetNPCBlock[TargetOffMapID:])
+}
+
+// SetTargetNPCMap sets the target NPC's map ID, leaving the rest of the block
+// unchanged.
+func (h *QuestHeader) SetTargetNPCMap(mapID uint16) {
+	binary.LittleEndian.PutUint16(h.TargetNPCBlock[TargetOffMapID:], mapID)
+}
+
+// TargetNPCXY returns the target NPC's coordinates stored at TargetOffX and
+// TargetOffY.
+func (h *QuestHeader) TargetNPCXY() (x, y uint16) {
+	return binary.LittleEndian.Uint16(h.TargetNPCBlock[TargetOffX:]),
+		binary.LittleEndian.Uint16(h.TargetNPCBlock[TargetOffY:])
+}
+
+// SetTargetNPCXY sets the target NPC's coordinates, leaving the rest of the
+// block unchanged.
+func (h *QuestHeader) SetTargetNPCXY(x, y uint16) {
+	binary.LittleEndian.PutUint16(h.TargetNPCBlock[TargetOffX:], x)
+	binary.LittleEndian.PutUint16(h.TargetNPCBlock[TargetOffY:], y)
+}
+
+// TargetNPCFlag returns the flag byte stored at TargetOffFlag.
+func (h *QuestHeader) TargetNPCFlag() uint8 {
+	return h.TargetNPCBlock[TargetOffFlag]
+}
+
+// SetTargetNPCFlag sets the flag byte, leaving the rest of the block
+// unchanged.
+func (h *QuestHeader) SetTargetNPCFlag(flag uint8) {
+	h.TargetNPCBlock[TargetOffFlag] = flag
+}
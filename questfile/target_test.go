@@ -0,0 +1,62 @@
+package questfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetNPCAccessors(t *testing.T) {
+	var h QuestHeader
+	for i := range h.TargetNPCBlock {
+		h.TargetNPCBlock[i] = byte(0xA0 + i)
+	}
+	original := h.TargetNPCBlock
+
+	h.SetTargetNPCID(0x1234)
+	h.SetTargetNPCMap(7)
+	h.SetTargetNPCXY(100, 200)
+	h.SetTargetNPCFlag(1)
+
+	assert.Equal(t, uint16(0x1234), h.TargetNPCID())
+	assert.Equal(t, uint16(7), h.TargetNPCMap())
+	x, y := h.TargetNPCXY()
+	assert.Equal(t, uint16(100), x)
+	assert.Equal(t, uint16(200), y)
+	assert.Equal(t, uint8(1), h.TargetNPCFlag())
+
+	assert.Equal(t, []byte{0x34, 0x12}, h.TargetNPCBlock[0:2])
+	assert.Equal(t, []byte{100, 0, 200, 0}, h.TargetNPCBlock[8:12])
+	for _, i := range []int{2, 3, 6, 7, 13, 23} {
+		assert.Equal(t, original[i], h.TargetNPCBlock[i], "unknown byte %d must be preserved", i)
+	}
+}
+
+func TestTargetNPCAccessors_RoundTrip(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Header.SetTargetNPCID(55)
+	q.Header.SetTargetNPCXY(3, 4)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	assert.Equal(t, []byte{55, 0}, buf.Bytes()[8:10], "block starts at header offset 8")
+	got, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(55), got.Header.TargetNPCID())
+	x, y := got.Header.TargetNPCXY()
+	assert.Equal(t, [2]uint16{3, 4}, [2]uint16{x, y})
+}
+
+func TestSetTargetNPCID_PreservesRestOfBlock(t *testing.T) {
+	var h QuestHeader
+	for i := range h.TargetNPCBlock {
+		h.TargetNPCBlock[i] = byte(0xA0 + i)
+	}
+	original := h.TargetNPCBlock
+
+	h.SetTargetNPCID(0xBEEF)
+	assert.Equal(t, uint16(0xBEEF), h.TargetNPCID())
+	assert.Equal(t, original[2:], h.TargetNPCBlock[2:])
+}
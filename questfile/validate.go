@@ -0,0 +1,199 @@
+package questfile
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/project-agonyl/agonyl-utils-go/utils"
+)
+
+// ObjectiveError annotates an error with the index of the objective slot it
+// applies to. Use errors.Is on it to test for the underlying sentinel error.
+type ObjectiveError struct {
+	Index int
+	Err   error
+}
+
+func (e *ObjectiveError) Error() string {
+	return fmt.Sprintf("objective %d: %v", e.Index, e.Err)
+}
+
+func (e *ObjectiveError) Unwrap() error {
+	return e.Err
+}
+
+// ContinuationError annotates an error with the continuation slot (0–2) it
+// applies to.
+type ContinuationError struct {
+	Slot int
+	Err  error
+}
+
+func (e *ContinuationError) Error() string {
+	return fmt.Sprintf("continuation %d: %v", e.Slot, e.Err)
+}
+
+func (e *ContinuationError) Unwrap() error {
+	return e.Err
+}
+
+// RewardError annotates an error with the reward slot (0–2) it applies to.
+type RewardError struct {
+	Slot int
+	Err  error
+}
+
+func (e *RewardError) Error() string {
+	return fmt.Sprintf("reward %d: %v", e.Slot, e.Err)
+}
+
+func (e *RewardError) Unwrap() error {
+	return e.Err
+}
+
+// problemCodes maps each validation sentinel to its stable Problem code.
+var problemCodes = map[error]string{
+	ErrInvalidObjectiveType: "questfile.invalid_objective_type",
+	ErrNameLengthForType:    "questfile.name_length_for_type",
+	ErrNameTooLong:          "questfile.name_too_long",
+	ErrNameLengthMismatch:   "questfile.name_length_mismatch",
+	ErrSelfContinuation:     "questfile.self_continuation",
+	ErrZeroKillCount:        "questfile.zero_kill_count",
+	ErrEmptyName:            "questfile.empty_name",
+	ErrZeroTargetID:         "questfile.zero_target_id",
+	ErrKillCountForType:     "questfile.kill_count_for_type",
+	ErrRewardCountUnused:    "questfile.reward_count_unused",
+}
+
+// Validate reports every structural problem in q that would make Write produce
+// a file Read rejects, or that Read would parse differently than q describes.
+// It also reports continuations that would make the client loop, unused
+// reward slots with a count and, for objectives without a structural problem,
+// the rules of Objective.ValidateSemantics. All problems are returned joined
+// with errors.Join; each is an *ObjectiveError wrapping
+// ErrInvalidObjectiveType, ErrNameLengthForType, ErrNameTooLong,
+// ErrNameLengthMismatch, ErrZeroKillCount, ErrEmptyName, ErrZeroTargetID or
+// ErrKillCountForType, a *RewardError wrapping ErrRewardCountUnused, or a
+// *ContinuationError wrapping ErrSelfContinuation. Validate does not modify
+// q.
+func (q *QuestFile) Validate() error {
+	var errs []error
+	for _, e := range q.objectiveErrors() {
+		errs = append(errs, e)
+	}
+
+	for _, e := range q.rewardErrors() {
+		errs = append(errs, e)
+	}
+
+	for _, e := range q.continuationErrors() {
+		errs = append(errs, e)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Problems reports the same findings as Validate in machine-readable form:
+// one Problem per objective error, in objective order, then one per reward
+// error and one per continuation error, each in slot order. Codes are
+// "questfile.invalid_objective_type", "questfile.name_length_for_type",
+// "questfile.name_too_long", "questfile.name_length_mismatch",
+// "questfile.zero_kill_count", "questfile.empty_name",
+// "questfile.zero_target_id", "questfile.kill_count_for_type",
+// "questfile.reward_count_unused" and "questfile.self_continuation". It
+// returns nil when q is valid.
+func (q *QuestFile) Problems() []utils.Problem {
+	var problems []utils.Problem
+	for _, e := range q.objectiveErrors() {
+		problems = append(problems, utils.Problem{
+			Code:    problemCodes[e.Err],
+			Field:   "Objectives",
+			Index:   e.Index,
+			Message: e.Error(),
+		})
+	}
+
+	for _, e := range q.rewardErrors() {
+		problems = append(problems, utils.Problem{
+			Code:    problemCodes[e.Err],
+			Field:   "Rewards",
+			Index:   e.Slot,
+			Message: e.Error(),
+		})
+	}
+
+	for _, e := range q.continuationErrors() {
+		problems = append(problems, utils.Problem{
+			Code:    problemCodes[e.Err],
+			Field:   "Continuation",
+			Index:   e.Slot,
+			Message: e.Error(),
+		})
+	}
+
+	return problems
+}
+
+// objectiveErrors runs the objective checks shared by Validate and Problems.
+func (q *QuestFile) objectiveErrors() []*ObjectiveError {
+	var errs []*ObjectiveError
+	for i := range q.Objectives {
+		o := &q.Objectives[i]
+		objType := o.ObjectiveType()
+		if objType > TypeFIND && objType != TypeUnused {
+			errs = append(errs, &ObjectiveError{Index: i, Err: ErrInvalidObjectiveType})
+			continue
+		}
+
+		switch {
+		case !supportsName(objType) && (o.NameLength() != 0 || len(o.Name) != 0):
+			errs = append(errs, &ObjectiveError{Index: i, Err: ErrNameLengthForType})
+		case len(o.Name) > MaxNameLength:
+			errs = append(errs, &ObjectiveError{Index: i, Err: ErrNameTooLong})
+		case int(o.NameLength()) != len(o.Name):
+			errs = append(errs, &ObjectiveError{Index: i, Err: ErrNameLengthMismatch})
+		default:
+			if err := o.ValidateSemantics(); err != nil {
+				errs = append(errs, &ObjectiveError{Index: i, Err: err})
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateStrict reports everything Validate does plus checks that are too
+// strict for every file in circulation: currently that MinLevel is not
+// greater than MaxLevel, reported as an error wrapping ErrLevelRange. All
+// problems are returned joined with errors.Join.
+func (q *QuestFile) ValidateStrict() error {
+	var levelErr error
+	if q.Header.MinLevel > q.Header.MaxLevel {
+		levelErr = fmt.Errorf("%w: %d > %d", ErrLevelRange, q.Header.MinLevel, q.Header.MaxLevel)
+	}
+
+	return errors.Join(q.Validate(), levelErr)
+}
+
+// rewardErrors runs the reward slot checks shared by Validate and Problems.
+func (q *QuestFile) rewardErrors() []*RewardError {
+	var errs []*RewardError
+	for i, r := range rewardSlots(&q.Header) {
+		if r.code == UnusedRewardItemCode && r.count != 0 {
+			errs = append(errs, &RewardError{Slot: i, Err: ErrRewardCountUnused})
+		}
+	}
+
+	return errs
+}
+
+// continuationErrors runs the continuation checks shared by Validate and
+// Problems.
+func (q *QuestFile) continuationErrors() []*ContinuationError {
+	var errs []*ContinuationError
+	for _, slot := range q.selfContinuationSlots() {
+		errs = append(errs, &ContinuationError{Slot: slot, Err: ErrSelfContinuation})
+	}
+
+	return errs
+}
@@ -0,0 +1,167 @@
+package questfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	q := minimalValidQuestFile()
+	assert.NoError(t, q.Validate())
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block[0] = 7
+	q.Objectives[1].Block[OffNameLen] = 1
+	q.Objectives[2].Block[0] = byte(TypeDROP)
+	q.Objectives[2].Block[OffNameLen] = 5
+	q.Objectives[2].Name = []byte("abc")
+
+	err := q.Validate()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidObjectiveType)
+	assert.ErrorIs(t, err, ErrNameLengthForType)
+	assert.ErrorIs(t, err, ErrNameLengthMismatch)
+	assert.Contains(t, err.Error(), "objective 2")
+}
+
+func TestValidate_NameTooLong(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block[0] = byte(TypeDROP)
+	q.Objectives[0].Name = make([]byte, MaxNameLength+1)
+	assert.ErrorIs(t, q.Validate(), ErrNameTooLong)
+}
+
+func TestProblems_Valid(t *testing.T) {
+	q := minimalValidQuestFile()
+	assert.Empty(t, q.Problems())
+}
+
+func TestProblems_MatchValidate(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[0].Block[0] = 7
+	q.Objectives[2].Block[0] = byte(TypeDROP)
+	q.Objectives[2].Block[OffNameLen] = 5
+	q.Objectives[2].Name = []byte("abc")
+
+	problems := q.Problems()
+	require.Len(t, problems, 2)
+	assert.Equal(t, "questfile.invalid_objective_type", problems[0].Code)
+	assert.Equal(t, 0, problems[0].Index)
+	assert.Equal(t, "Objectives", problems[0].Field)
+	assert.Equal(t, "questfile.name_length_mismatch", problems[1].Code)
+	assert.Equal(t, 2, problems[1].Index)
+	assert.Contains(t, q.Validate().Error(), problems[1].Message)
+}
+
+func TestValidate_SelfContinuation(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Header.SetQuestID(42)
+	q.Continuation[0] = 43
+	q.Continuation[2] = 42
+	assert.True(t, q.HasSelfContinuation())
+
+	err := q.Validate()
+	require.ErrorIs(t, err, ErrSelfContinuation)
+	var contErr *ContinuationError
+	require.ErrorAs(t, err, &contErr)
+	assert.Equal(t, 2, contErr.Slot)
+
+	problems := q.Problems()
+	require.Len(t, problems, 1)
+	assert.Equal(t, "questfile.self_continuation", problems[0].Code)
+	assert.Equal(t, "Continuation", problems[0].Field)
+	assert.Equal(t, 2, problems[0].Index)
+}
+
+func TestHasSelfContinuation_IgnoresUnusedSlots(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Header.SetQuestID(0xFFFF) // lower 16 bits of UnusedContinuation
+	assert.False(t, q.HasSelfContinuation())
+	assert.NoError(t, q.Validate())
+}
+
+func TestValidate_Semantics(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[1].Block[OffKillCount] = 0
+	q.Objectives[4].Block[OffType] = byte(TypeBRINGNPC)
+	q.Objectives[4].SetKillCount(0)
+
+	err := q.Validate()
+	require.ErrorIs(t, err, ErrZeroKillCount)
+	require.ErrorIs(t, err, ErrZeroTargetID)
+	var objErr *ObjectiveError
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, 1, objErr.Index)
+
+	problems := q.Problems()
+	require.Len(t, problems, 2)
+	assert.Equal(t, "questfile.zero_kill_count", problems[0].Code)
+	assert.Equal(t, 1, problems[0].Index)
+	assert.Equal(t, "questfile.zero_target_id", problems[1].Code)
+	assert.Equal(t, 4, problems[1].Index)
+
+	// Read stays permissive: the stub objectives still load.
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, q))
+	got, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, q, got)
+}
+
+func TestValidate_KillCountForType(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Objectives[2].Block[OffType] = byte(TypeDROP)
+	q.Objectives[2].Name = []byte("Cave")
+	q.Objectives[2].Block[OffNameLen] = 4
+
+	err := q.Validate()
+	require.ErrorIs(t, err, ErrKillCountForType)
+	var objErr *ObjectiveError
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, 2, objErr.Index)
+
+	q.Objectives[2].SetKillCount(0)
+	assert.NoError(t, q.Validate())
+}
+
+func TestValidate_RewardCountUnused(t *testing.T) {
+	q := minimalValidQuestFile()
+	q.Header.Count2 = 3
+
+	err := q.Validate()
+	require.ErrorIs(t, err, ErrRewardCountUnused)
+	var rewardErr *RewardError
+	require.ErrorAs(t, err, &rewardErr)
+	assert.Equal(t, 1, rewardErr.Slot)
+
+	problems := q.Problems()
+	require.Len(t, problems, 1)
+	assert.Equal(t, "questfile.reward_count_unused", problems[0].Code)
+	assert.Equal(t, "Rewards", problems[0].Field)
+	assert.Equal(t, 1, problems[0].Index)
+
+	binary.LittleEndian.PutUint16(q.Header.RewardSlot2[:2], 500)
+	assert.NoError(t, q.Validate())
+}
+
+func TestValidateStrict_LevelRange(t *testing.T) {
+	q := minimalValidQuestFile()
+	require.NoError(t, q.ValidateStrict())
+
+	q.Header.MinLevel, q.Header.MaxLevel = 60, 50
+	assert.NoError(t, q.Validate(), "Validate does not check the level range")
+	err := q.ValidateStrict()
+	assert.ErrorIs(t, err, ErrLevelRange)
+	assert.EqualError(t, err, "questfile: MinLevel greater than MaxLevel: 60 > 50")
+
+	q.Header.Count1 = 1
+	err = q.ValidateStrict()
+	assert.ErrorIs(t, err, ErrLevelRange)
+	assert.ErrorIs(t, err, ErrRewardCountUnused)
+}
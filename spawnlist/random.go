@@ -0,0 +1,24 @@
+package spawnlist
+
+import agutils "github.com/project-agonyl/agonyl-utils-go/utils"
+
+// RandomN returns n spawn entries generated deterministically from seed, for
+// tests and fuzz corpora: the same seed and n always give the same list. Ids
+// run from 1 to n, so entries are distinct and the list passes Validate; the
+// other fields are random.
+func RandomN(seed int64, n int) SpawnList {
+	rng := agutils.NewRand(seed)
+	data := make(SpawnList, n)
+	for i := range data {
+		data[i] = SpawnListItem{
+			Id:          uint16(i + 1),
+			X:           byte(rng.UintN(256)),
+			Y:           byte(rng.UintN(256)),
+			Unknown1:    uint16(rng.UintN(1 << 16)),
+			Orientation: byte(rng.UintN(8)),
+			SpwanStep:   byte(rng.UintN(256)),
+		}
+	}
+
+	return data
+}
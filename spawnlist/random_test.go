@@ -0,0 +1,25 @@
+package spawnlist
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomN_Deterministic(t *testing.T) {
+	assert.Equal(t, RandomN(42, 20), RandomN(42, 20))
+	assert.NotEqual(t, RandomN(42, 20), RandomN(43, 20))
+	assert.Empty(t, RandomN(1, 0))
+}
+
+func TestRandomN_RoundTrip(t *testing.T) {
+	data := RandomN(7, 50)
+	require.NoError(t, data.Validate())
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, data))
+	got, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
@@ -5,9 +5,24 @@ package spawnlist
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"io"
+	"unsafe"
 )
 
+// ItemSize is the encoded size of a SpawnListItem in bytes.
+const ItemSize = 8
+
+// ExtendedItemSize is the entry size of the A3 fork that extends every entry
+// with two extra one-byte fields after the standard eight bytes. Spawn lists
+// have no header or version byte, so the entry size is the only thing that
+// tells this variant from the standard one, and the caller has to supply it:
+// pass ExtendedItemSize to ReadSized or ReadSizedWithExtra for files from
+// that fork. The meaning of the two extra fields is not known, so they are
+// not decoded; ReadSizedWithExtra returns them as raw bytes.
+const ExtendedItemSize = 10
+
 // SpawnListItem is a single spawn entry as stored in the spawn list file.
 type SpawnListItem struct {
 	Id          uint16 // Spawn/npc identifier
@@ -18,9 +33,25 @@ type SpawnListItem struct {
 	SpwanStep   byte   // Spawn step
 }
 
+// ErrNegativeCount is returned by ReadN when asked for a negative number of
+// items.
+var ErrNegativeCount = errors.New("spawnlist: negative item count")
+
+// ErrItemSize is returned by ReadSized and ReadSizedWithExtra when the
+// requested entry size is smaller than ItemSize.
+var ErrItemSize = errors.New("spawnlist: item size smaller than standard entry")
+
 // SpawnList is a slice of spawn entries as stored in the spawn list file.
 type SpawnList []SpawnListItem
 
+// MemSize returns the approximate number of bytes s occupies in memory:
+// the slice header plus cap(s) SpawnListItem values. Summed over a loaded
+// dataset it gives a figure for capacity planning; allocator overhead is not
+// included.
+func (s SpawnList) MemSize() int {
+	return int(unsafe.Sizeof(s)) + cap(s)*int(unsafe.Sizeof(SpawnListItem{}))
+}
+
 // Read reads a spawn list from r.
 // The entire stream is decoded as a contiguous sequence of SpawnListItem values until EOF.
 // Returns the decoded list or an error if the stream is truncated or invalid.
@@ -48,6 +79,153 @@ func Read(r io.Reader) (SpawnList, error) {
 	return data, nil
 }
 
+// ReadTolerateSentinel reads a spawn list like Read, except that a trailing
+// partial entry made only of 0xFF bytes (1 to ItemSize-1 of them) is treated
+// as an end marker and dropped. Some spawn files end with such a marker row.
+// Any other incomplete trailing entry still returns io.ErrUnexpectedEOF.
+func ReadTolerateSentinel(r io.Reader) (SpawnList, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if tail := b[len(b)-len(b)%ItemSize:]; len(tail) > 0 {
+		if bytes.Count(tail, []byte{0xFF}) != len(tail) {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		b = b[:len(b)-len(tail)]
+	}
+
+	data := make(SpawnList, len(b)/ItemSize)
+	for i := range data {
+		getItem(b[i*ItemSize:], &data[i])
+	}
+
+	return data, nil
+}
+
+// ReadN reads exactly n spawn entries from r and stops, leaving r positioned
+// immediately after the last entry read. Unlike Read it does not consume the
+// rest of the stream, so it can be used to preview the start of a large file.
+// It returns io.ErrUnexpectedEOF if fewer than n complete entries are
+// available and ErrNegativeCount if n is negative.
+func ReadN(r io.Reader, n int) (SpawnList, error) {
+	if n < 0 {
+		return nil, ErrNegativeCount
+	}
+
+	buf := make([]byte, n*ItemSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	data := make(SpawnList, n)
+	for i := range data {
+		getItem(buf[i*ItemSize:], &data[i])
+	}
+
+	return data, nil
+}
+
+// ReadSized reads a spawn list whose entries are itemSize bytes long instead of
+// ItemSize, such as the ExtendedItemSize variant. The first ItemSize bytes of
+// each entry are decoded as a standard SpawnListItem and the rest are
+// discarded; use ReadSizedWithExtra to keep them. Like Read it consumes the
+// whole stream and returns io.ErrUnexpectedEOF if its length is not a multiple
+// of itemSize. It returns ErrItemSize if itemSize is smaller than ItemSize.
+func ReadSized(r io.Reader, itemSize int) (SpawnList, error) {
+	data, _, err := readSized(r, itemSize, false)
+	return data, err
+}
+
+// ReadSizedWithExtra is ReadSized that also returns the bytes past ItemSize in
+// each entry: extra[i] holds the itemSize-ItemSize trailing bytes of entry i.
+// extra is nil when itemSize equals ItemSize.
+func ReadSizedWithExtra(r io.Reader, itemSize int) (SpawnList, [][]byte, error) {
+	return readSized(r, itemSize, true)
+}
+
+func readSized(r io.Reader, itemSize int, keepExtra bool) (SpawnList, [][]byte, error) {
+	if itemSize < ItemSize {
+		return nil, nil, ErrItemSize
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(b)%itemSize != 0 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+
+	data := make(SpawnList, len(b)/itemSize)
+	var extra [][]byte
+	if keepExtra && itemSize > ItemSize {
+		extra = make([][]byte, len(data))
+	}
+
+	// Every variant starts with the standard entry; whatever itemSize adds
+	// (the fork's two fields for ExtendedItemSize) follows it.
+	for i := range data {
+		entry := b[i*itemSize : (i+1)*itemSize]
+		getItem(entry, &data[i])
+		if extra != nil {
+			extra[i] = entry[ItemSize:]
+		}
+	}
+
+	return data, extra, nil
+}
+
+// CopyConcat streams the spawn lists in readers to w one after another and
+// returns the total number of bytes written. Spawn lists have no header, so
+// the output is a valid spawn list holding every source's entries in order.
+// Sources are copied in fixed-size chunks without being decoded, and only
+// whole entries are written: if a source ends part-way through an entry,
+// CopyConcat stops and returns an error wrapping io.ErrUnexpectedEOF that
+// names the source's index, with everything before that entry already
+// written.
+func CopyConcat(w io.Writer, readers ...io.Reader) (int64, error) {
+	buf := make([]byte, 512*ItemSize)
+	var total int64
+	for i, r := range readers {
+		pending := 0
+		for {
+			n, err := r.Read(buf[pending:])
+			pending += n
+			if whole := pending - pending%ItemSize; whole > 0 && (err != nil || pending == len(buf)) {
+				written, werr := w.Write(buf[:whole])
+				total += int64(written)
+				if werr != nil {
+					return total, werr
+				}
+
+				pending = copy(buf, buf[whole:pending])
+			}
+
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				return total, fmt.Errorf("spawnlist: source %d: %w", i, err)
+			}
+		}
+
+		if pending != 0 {
+			return total, fmt.Errorf("spawnlist: source %d: %w", i, io.ErrUnexpectedEOF)
+		}
+	}
+
+	return total, nil
+}
+
 // Write writes data to w in spawn list binary format.
 func Write(w io.Writer, data SpawnList) error {
 	if err := binary.Write(w, binary.LittleEndian, data); err != nil {
@@ -56,3 +234,38 @@ func Write(w io.Writer, data SpawnList) error {
 
 	return nil
 }
+
+// WriteTo writes s to w in spawn list binary format. Unlike Write it encodes
+// every item by hand into a single pre-sized buffer and issues one Write call,
+// avoiding the reflection and per-element allocations of binary.Write.
+// It implements io.WriterTo and returns the number of bytes written.
+func (s SpawnList) WriteTo(w io.Writer) (int64, error) {
+	buf := make([]byte, len(s)*ItemSize)
+	for i := range s {
+		putItem(buf[i*ItemSize:], &s[i])
+	}
+
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// putItem encodes item into the first ItemSize bytes of b.
+func putItem(b []byte, item *SpawnListItem) {
+	binary.LittleEndian.PutUint16(b[0:], item.Id)
+	b[2] = item.X
+	b[3] = item.Y
+	binary.LittleEndian.PutUint16(b[4:], item.Unknown1)
+	b[6] = item.Orientation
+	b[7] = item.SpwanStep
+}
+
+// getItem decodes the first ItemSize bytes of b into item. It is the inverse
+// of putItem.
+func getItem(b []byte, item *SpawnListItem) {
+	item.Id = binary.LittleEndian.Uint16(b[0:])
+	item.X = b[2]
+	item.Y = b[3]
+	item.Unknown1 = binary.LittleEndian.Uint16(b[4:])
+	item.Orientation = b[6]
+	item.SpwanStep = b[7]
+}
@@ -0,0 +1,33 @@
+package spawnlist
+
+import (
+	"io"
+	"testing"
+)
+
+func benchmarkList(n int) SpawnList {
+	s := make(SpawnList, n)
+	for i := range s {
+		s[i] = SpawnListItem{Id: uint16(i), X: byte(i), Y: byte(i >> 8), Orientation: byte(i % 8), SpwanStep: 1}
+	}
+
+	return s
+}
+
+func BenchmarkWrite_10000(b *testing.B) {
+	s := benchmarkList(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Write(io.Discard, s)
+	}
+}
+
+func BenchmarkWriteTo_10000(b *testing.B) {
+	s := benchmarkList(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = s.WriteTo(io.Discard)
+	}
+}
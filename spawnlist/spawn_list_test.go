@@ -5,9 +5,12 @@ import (
 	"encoding/binary"
 	"io"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/project-agonyl/agonyl-utils-go/utils"
 )
 
 func TestRead_EmptyStream(t *testing.T) {
@@ -197,3 +200,184 @@ func TestSpawnListItem_Size(t *testing.T) {
 	size := binary.Size(SpawnListItem{})
 	assert.Equal(t, 8, size, "SpawnListItem must be 8 bytes for binary format")
 }
+
+func TestWriteTo_MatchesWrite(t *testing.T) {
+	items := SpawnList{
+		{Id: 0x0102, X: 0x0A, Y: 0x14, Unknown1: 0x0304, Orientation: 5, SpwanStep: 6},
+		{Id: 0xFFFF, X: 0xFF, Y: 0, Unknown1: 0xABCD, Orientation: 15, SpwanStep: 255},
+	}
+	var want bytes.Buffer
+	require.NoError(t, Write(&want, items))
+
+	var got bytes.Buffer
+	n, err := items.WriteTo(&got)
+	require.NoError(t, err)
+	assert.Equal(t, int64(want.Len()), n)
+	assert.Equal(t, want.Bytes(), got.Bytes())
+	assert.Equal(t, ItemSize, binary.Size(SpawnListItem{}))
+}
+
+func TestWriteTo_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := SpawnList{}.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Empty(t, buf.Bytes())
+}
+
+func TestWriteTo_InvalidWriter(t *testing.T) {
+	_, err := SpawnList{{Id: 1}}.WriteTo(errWriter{})
+	assert.Error(t, err)
+}
+
+func TestReadN_StopsAfterN(t *testing.T) {
+	items := SpawnList{
+		{Id: 1, X: 1, Y: 1, Unknown1: 0x0102, Orientation: 0, SpwanStep: 0},
+		{Id: 2, X: 2, Y: 2, Orientation: 1, SpwanStep: 1},
+		{Id: 3, X: 3, Y: 3, Orientation: 2, SpwanStep: 2},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, items))
+
+	data, err := ReadN(&buf, 2)
+	require.NoError(t, err)
+	assert.Equal(t, items[:2], data)
+	assert.Equal(t, ItemSize, buf.Len(), "reader must be left positioned after the items read")
+
+	rest, err := Read(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, items[2:], rest)
+}
+
+func TestReadN_Zero(t *testing.T) {
+	data, err := ReadN(bytes.NewReader([]byte{1, 2, 3}), 0)
+	require.NoError(t, err)
+	assert.NotNil(t, data)
+	assert.Len(t, data, 0)
+}
+
+func TestReadN_NotEnoughItems(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, SpawnList{{Id: 1}}))
+	_, err := ReadN(bytes.NewReader(buf.Bytes()), 2)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = ReadN(bytes.NewReader(nil), 1)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReadN_Negative(t *testing.T) {
+	_, err := ReadN(bytes.NewReader(nil), -1)
+	assert.ErrorIs(t, err, ErrNegativeCount)
+}
+
+func TestReadSized_Extended(t *testing.T) {
+	data := []byte{
+		0x01, 0x00, 10, 20, 0x00, 0x00, 2, 1, 0xAA, 0xBB,
+		0x02, 0x00, 30, 40, 0x00, 0x00, 3, 0, 0xCC, 0xDD,
+	}
+	list, err := ReadSized(bytes.NewReader(data), ExtendedItemSize)
+	require.NoError(t, err)
+	assert.Equal(t, SpawnList{
+		{Id: 1, X: 10, Y: 20, Orientation: 2, SpwanStep: 1},
+		{Id: 2, X: 30, Y: 40, Orientation: 3},
+	}, list)
+
+	list2, extra, err := ReadSizedWithExtra(bytes.NewReader(data), ExtendedItemSize)
+	require.NoError(t, err)
+	assert.Equal(t, list, list2)
+	assert.Equal(t, [][]byte{{0xAA, 0xBB}, {0xCC, 0xDD}}, extra)
+}
+
+func TestReadSized_StandardMatchesRead(t *testing.T) {
+	var buf bytes.Buffer
+	want := SpawnList{{Id: 7, X: 1, Y: 2}, {Id: 8, Unknown1: 0x0102}}
+	require.NoError(t, Write(&buf, want))
+
+	list, extra, err := ReadSizedWithExtra(bytes.NewReader(buf.Bytes()), ItemSize)
+	require.NoError(t, err)
+	assert.Equal(t, want, list)
+	assert.Nil(t, extra)
+}
+
+func TestReadSized_Errors(t *testing.T) {
+	_, err := ReadSized(bytes.NewReader(make([]byte, 15)), ExtendedItemSize)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = ReadSized(bytes.NewReader(nil), ItemSize-1)
+	assert.ErrorIs(t, err, ErrItemSize)
+}
+
+func TestReadTolerateSentinel(t *testing.T) {
+	items := SpawnList{{Id: 1, X: 10, Y: 20}, {Id: 2, Orientation: 3}}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, items))
+	raw := buf.Bytes()
+
+	for n := 0; n < ItemSize; n++ {
+		data := append(bytes.Clone(raw), bytes.Repeat([]byte{0xFF}, n)...)
+		got, err := ReadTolerateSentinel(bytes.NewReader(data))
+		require.NoError(t, err, "%d marker bytes", n)
+		assert.Equal(t, items, got)
+	}
+
+	_, err := Read(bytes.NewReader(append(bytes.Clone(raw), 0xFF, 0xFF)))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF, "strict Read must still reject the marker")
+}
+
+func TestReadTolerateSentinel_RejectsOtherTails(t *testing.T) {
+	_, err := ReadTolerateSentinel(bytes.NewReader([]byte{0xFF, 0xFF, 0x00}))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// A full row of 0xFF is a real entry, not a marker.
+	got, err := ReadTolerateSentinel(bytes.NewReader(bytes.Repeat([]byte{0xFF}, ItemSize)))
+	require.NoError(t, err)
+	assert.Equal(t, SpawnList{{Id: 0xFFFF, X: 0xFF, Y: 0xFF, Unknown1: 0xFFFF, Orientation: 0xFF, SpwanStep: 0xFF}}, got)
+}
+
+func TestCopyConcat(t *testing.T) {
+	a, b := RandomN(1, 3), RandomN(2, 600) // b is larger than one copy chunk
+	var bufA, bufB bytes.Buffer
+	require.NoError(t, Write(&bufA, a))
+	require.NoError(t, Write(&bufB, b))
+
+	var out bytes.Buffer
+	n, err := CopyConcat(&out, &bufA, bytes.NewReader(nil), iotest.OneByteReader(&bufB))
+	require.NoError(t, err)
+	assert.Equal(t, int64((len(a)+len(b))*ItemSize), n)
+
+	got, err := Read(&out)
+	require.NoError(t, err)
+	assert.Equal(t, append(a, b...), got)
+}
+
+func TestCopyConcat_MisalignedSource(t *testing.T) {
+	var good bytes.Buffer
+	require.NoError(t, Write(&good, RandomN(1, 2)))
+	bad := append(bytes.Clone(good.Bytes()), 0x01, 0x02, 0x03)
+
+	var out bytes.Buffer
+	n, err := CopyConcat(&out, &good, bytes.NewReader(bad))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Contains(t, err.Error(), "source 1")
+	assert.Equal(t, int64(4*ItemSize), n, "whole entries before the partial one are written")
+	assert.Equal(t, 4*ItemSize, out.Len())
+}
+
+func TestCopyConcat_Errors(t *testing.T) {
+	var src bytes.Buffer
+	require.NoError(t, Write(&src, RandomN(1, 2)))
+	_, err := CopyConcat(utils.FailAfterWriter(io.Discard, 0), &src)
+	assert.ErrorIs(t, err, utils.ErrInjected)
+
+	_, err = CopyConcat(io.Discard, utils.FailAfterReader(bytes.NewReader(make([]byte, 16)), 3))
+	assert.ErrorIs(t, err, utils.ErrInjected)
+}
+
+func TestMemSize(t *testing.T) {
+	var empty SpawnList
+	assert.Equal(t, empty.MemSize(), make(SpawnList, 0).MemSize())
+	// Capacity, not length, is what the backing array occupies.
+	assert.Equal(t, make(SpawnList, 10).MemSize(), make(SpawnList, 0, 10).MemSize())
+	assert.Equal(t, 10*ItemSize, make(SpawnList, 0, 10).MemSize()-empty.MemSize())
+}
@@ -0,0 +1,73 @@
+package spawnlist
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/project-agonyl/agonyl-utils-go/utils"
+)
+
+// ErrDuplicateItem is reported for a spawn entry that is byte-for-byte
+// identical to an earlier entry in the same list, which is almost always a
+// copy-paste mistake that spawns the same NPC twice on the same cell.
+var ErrDuplicateItem = errors.New("spawnlist: duplicate spawn entry")
+
+// ItemError annotates an error with the index of the spawn entry it applies
+// to. Use errors.Is on it to test for the underlying sentinel error.
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// Validate reports every problem found in s, joined with errors.Join. Each
+// problem is an *ItemError wrapping ErrDuplicateItem. Validate does not
+// modify s.
+func (s SpawnList) Validate() error {
+	var errs []error
+	for _, e := range s.itemErrors() {
+		errs = append(errs, e)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Problems reports the same findings as Validate in machine-readable form, in
+// item order. The only code is "spawnlist.duplicate_item". It returns nil
+// when s is valid.
+func (s SpawnList) Problems() []utils.Problem {
+	var problems []utils.Problem
+	for _, e := range s.itemErrors() {
+		problems = append(problems, utils.Problem{
+			Code:    "spawnlist.duplicate_item",
+			Field:   "SpawnList",
+			Index:   e.Index,
+			Message: e.Error(),
+		})
+	}
+
+	return problems
+}
+
+// itemErrors runs the checks shared by Validate and Problems.
+func (s SpawnList) itemErrors() []*ItemError {
+	var errs []*ItemError
+	seen := make(map[SpawnListItem]struct{}, len(s))
+	for i, item := range s {
+		if _, ok := seen[item]; ok {
+			errs = append(errs, &ItemError{Index: i, Err: ErrDuplicateItem})
+			continue
+		}
+
+		seen[item] = struct{}{}
+	}
+
+	return errs
+}
@@ -0,0 +1,33 @@
+package spawnlist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	s := SpawnList{
+		{Id: 1, X: 10, Y: 20},
+		{Id: 1, X: 11, Y: 20},
+		{Id: 2, X: 10, Y: 20},
+	}
+	assert.NoError(t, s.Validate())
+	assert.Empty(t, s.Problems())
+}
+
+func TestValidate_DuplicateItem(t *testing.T) {
+	item := SpawnListItem{Id: 7, X: 1, Y: 2, Orientation: 3}
+	s := SpawnList{item, {Id: 8}, item}
+
+	err := s.Validate()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrDuplicateItem)
+	assert.Contains(t, err.Error(), "item 2")
+
+	problems := s.Problems()
+	require.Len(t, problems, 1)
+	assert.Equal(t, "spawnlist.duplicate_item", problems[0].Code)
+	assert.Equal(t, 2, problems[0].Index)
+}
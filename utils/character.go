@@ -14,3 +14,19 @@ func GetClassName(class byte) string {
 		return "Warrior"
 	}
 }
+
+// ResolveClasses maps each class ID in ids to its display name, as
+// GetClassName does, and reports the indices of IDs that are not a known
+// class (0–3) and so fell through to the Warrior default. names always has the
+// same length as ids; unknown is nil when every ID is known.
+func ResolveClasses(ids []byte) (names []string, unknown []int) {
+	names = make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = GetClassName(id)
+		if id > 3 {
+			unknown = append(unknown, i)
+		}
+	}
+
+	return names, unknown
+}
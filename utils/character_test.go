@@ -26,3 +26,15 @@ func TestGetClassName(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveClasses(t *testing.T) {
+	names, unknown := ResolveClasses([]byte{0, 1, 7, 2, 3, 255})
+	assert.Equal(t, []string{"Warrior", "Holy Knight", "Warrior", "Mage", "Archer", "Warrior"}, names)
+	assert.Equal(t, []int{2, 5}, unknown)
+}
+
+func TestResolveClasses_AllKnown(t *testing.T) {
+	names, unknown := ResolveClasses([]byte{3, 0})
+	assert.Equal(t, []string{"Archer", "Warrior"}, names)
+	assert.Nil(t, unknown)
+}
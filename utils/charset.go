@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/korean"
+)
+
+// EncodeCP949 converts s from UTF-8 to CP949, the code page the A3 client
+// uses for names. It returns an error if s contains a character CP949 cannot
+// represent.
+func EncodeCP949(s string) ([]byte, error) {
+	return korean.EUCKR.NewEncoder().Bytes([]byte(s))
+}
+
+// DecodeCP949 converts CP949 bytes to a UTF-8 string.
+func DecodeCP949(b []byte) (string, error) {
+	out, err := korean.EUCKR.NewDecoder().Bytes(b)
+	return string(out), err
+}
+
+// NormalizeName rewrites a fixed-size, null-padded name field in canonical
+// form and reports whether any byte changed. The name is the bytes before the
+// first null. Trailing ASCII whitespace is trimmed; a name that is valid UTF-8
+// and contains non-ASCII characters is treated as UTF-8 and re-encoded to
+// CP949, while anything else is assumed to be CP949 already and kept as is
+// (as is a UTF-8 name with characters CP949 cannot represent). The name is
+// truncated to at most len(field)-1 bytes, at the last whole CP949 character,
+// so the field always ends in a null and a double-byte character is never
+// split. Every byte after the name is zeroed.
+func NormalizeName(field []byte) bool {
+	name := field
+	if i := bytes.IndexByte(name, 0); i >= 0 {
+		name = name[:i]
+	}
+
+	name = bytes.TrimRight(name, " \t\r\n\v\f")
+	if utf8.Valid(name) && !isASCII(name) {
+		if encoded, err := EncodeCP949(string(name)); err == nil {
+			name = encoded
+		}
+	}
+
+	name = truncateCP949(name, len(field)-1)
+	canonical := make([]byte, len(field))
+	copy(canonical, name)
+	if bytes.Equal(canonical, field) {
+		return false
+	}
+
+	copy(field, canonical)
+	return true
+}
+
+// truncateCP949 shortens b to at most n bytes without splitting a double-byte
+// character (lead byte 0x81–0xFE followed by a trail byte).
+func truncateCP949(b []byte, n int) []byte {
+	end := 0
+	for end < len(b) {
+		size := 1
+		if b[end] >= 0x81 && b[end] <= 0xFE {
+			size = 2
+		}
+
+		if end+size > n {
+			break
+		}
+
+		end += size
+	}
+
+	return b[:min(end, len(b))]
+}
+
+func isASCII(b []byte) bool {
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// "전사" (warrior) in CP949.
+var warriorCP949 = []byte{0xC0, 0xFC, 0xBB, 0xE7}
+
+func TestCP949_RoundTrip(t *testing.T) {
+	encoded, err := EncodeCP949("전사")
+	require.NoError(t, err)
+	assert.Equal(t, warriorCP949, encoded)
+
+	decoded, err := DecodeCP949(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, "전사", decoded)
+}
+
+func TestEncodeCP949_Unsupported(t *testing.T) {
+	_, err := EncodeCP949("🙂")
+	assert.Error(t, err)
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   []byte
+		want    []byte
+		changed bool
+	}{
+		{"canonical ASCII", []byte("Goblin\x00\x00"), []byte("Goblin\x00\x00"), false},
+		{"trailing spaces", []byte("Goblin  "), []byte("Goblin\x00\x00"), true},
+		{"padding garbage", []byte("Orc\x00\xFF\xFF\x00\x01"), []byte("Orc\x00\x00\x00\x00\x00"), true},
+		{"UTF-8 to CP949", append([]byte("전사"), 0, 0), append(append([]byte{}, warriorCP949...), 0, 0, 0, 0), true},
+		{"CP949 kept", append(append([]byte{}, warriorCP949...), 0), append(append([]byte{}, warriorCP949...), 0), false},
+		{"UTF-8 filling the field", []byte("전사"), []byte{0xC0, 0xFC, 0xBB, 0xE7, 0, 0}, true},
+		{"name filling the field", []byte("Goblin"), []byte("Gobli\x00"), true},
+		{"empty field", []byte{}, []byte{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := append([]byte{}, tt.field...)
+			assert.Equal(t, tt.changed, NormalizeName(field))
+			assert.Equal(t, tt.want, field)
+		})
+	}
+}
+
+func TestNormalizeName_NeverSplitsCharacter(t *testing.T) {
+	// The last byte is reserved for the null terminator, so the third
+	// character's trail byte does not fit and the whole character is dropped
+	// rather than leaving half of it in the field.
+	field := append(append([]byte{}, warriorCP949...), 0xC0, 0xFC)
+	assert.True(t, NormalizeName(field))
+	assert.Equal(t, []byte{0xC0, 0xFC, 0xBB, 0xE7, 0, 0}, field)
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrCursorOverflow is recorded by a Cursor when a read or write would run
+// past the end of its buffer.
+var ErrCursorOverflow = errors.New("utils: cursor overflow")
+
+// Cursor reads and writes sequential little-endian values in a fixed byte
+// slice, advancing an internal offset after each call. The first out-of-bounds
+// access records ErrCursorOverflow; from then on reads return zero values and
+// writes are ignored, so a codec can run a whole sequence of calls and check
+// Err once at the end.
+type Cursor struct {
+	buf []byte
+	off int
+	err error
+}
+
+// NewCursor returns a Cursor positioned at the start of b. Writes modify b in
+// place; the cursor never grows it.
+func NewCursor(b []byte) *Cursor {
+	return &Cursor{buf: b}
+}
+
+// Err returns ErrCursorOverflow if any call ran past the end of the buffer,
+// or nil.
+func (c *Cursor) Err() error {
+	return c.err
+}
+
+// Offset returns the number of bytes consumed so far.
+func (c *Cursor) Offset() int {
+	return c.off
+}
+
+// Remaining returns the number of bytes left after the current offset.
+func (c *Cursor) Remaining() int {
+	return len(c.buf) - c.off
+}
+
+// Skip advances the offset by n bytes without reading them.
+func (c *Cursor) Skip(n int) {
+	c.next(n)
+}
+
+// Byte reads one byte.
+func (c *Cursor) Byte() byte {
+	b := c.next(1)
+	if b == nil {
+		return 0
+	}
+
+	return b[0]
+}
+
+// Uint16 reads a little-endian uint16.
+func (c *Cursor) Uint16() uint16 {
+	b := c.next(2)
+	if b == nil {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint16(b)
+}
+
+// Uint32 reads a little-endian uint32.
+func (c *Cursor) Uint32() uint32 {
+	b := c.next(4)
+	if b == nil {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint32(b)
+}
+
+// Bytes returns the next n bytes. The result aliases the underlying buffer;
+// copy it if it must outlive later writes.
+func (c *Cursor) Bytes(n int) []byte {
+	return c.next(n)
+}
+
+// PutByte writes one byte.
+func (c *Cursor) PutByte(v byte) {
+	if b := c.next(1); b != nil {
+		b[0] = v
+	}
+}
+
+// PutUint16 writes v as a little-endian uint16.
+func (c *Cursor) PutUint16(v uint16) {
+	if b := c.next(2); b != nil {
+		binary.LittleEndian.PutUint16(b, v)
+	}
+}
+
+// PutUint32 writes v as a little-endian uint32.
+func (c *Cursor) PutUint32(v uint32) {
+	if b := c.next(4); b != nil {
+		binary.LittleEndian.PutUint32(b, v)
+	}
+}
+
+// PutBytes writes p verbatim.
+func (c *Cursor) PutBytes(p []byte) {
+	if b := c.next(len(p)); b != nil {
+		copy(b, p)
+	}
+}
+
+// next returns the next n bytes and advances past them, or records
+// ErrCursorOverflow and returns nil if fewer than n bytes remain.
+func (c *Cursor) next(n int) []byte {
+	if c.err != nil {
+		return nil
+	}
+
+	if n < 0 || n > len(c.buf)-c.off {
+		c.err = ErrCursorOverflow
+		return nil
+	}
+
+	b := c.buf[c.off : c.off+n : c.off+n]
+	c.off += n
+	return b
+}
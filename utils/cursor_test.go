@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_WriteThenRead(t *testing.T) {
+	buf := make([]byte, 12)
+	w := NewCursor(buf)
+	w.PutByte(0xAB)
+	w.PutUint16(0x1234)
+	w.PutUint32(0xDEADBEEF)
+	w.PutBytes([]byte("hello"))
+	require.NoError(t, w.Err())
+	assert.Equal(t, 12, w.Offset())
+	assert.Equal(t, 0, w.Remaining())
+	assert.Equal(t, []byte{0xAB, 0x34, 0x12, 0xEF, 0xBE, 0xAD, 0xDE, 'h', 'e', 'l', 'l', 'o'}, buf)
+
+	r := NewCursor(buf)
+	assert.Equal(t, byte(0xAB), r.Byte())
+	assert.Equal(t, uint16(0x1234), r.Uint16())
+	assert.Equal(t, uint32(0xDEADBEEF), r.Uint32())
+	assert.Equal(t, []byte("hello"), r.Bytes(5))
+	assert.NoError(t, r.Err())
+}
+
+func TestCursor_Skip(t *testing.T) {
+	r := NewCursor([]byte{1, 2, 3, 4})
+	r.Skip(2)
+	assert.Equal(t, uint16(0x0403), r.Uint16())
+	assert.NoError(t, r.Err())
+}
+
+func TestCursor_ReadOverflowIsSticky(t *testing.T) {
+	r := NewCursor([]byte{1, 2, 3})
+	assert.Equal(t, uint16(0x0201), r.Uint16())
+	assert.Equal(t, uint32(0), r.Uint32())
+	assert.ErrorIs(t, r.Err(), ErrCursorOverflow)
+
+	// The remaining byte is not consumed once the cursor has failed.
+	assert.Equal(t, byte(0), r.Byte())
+	assert.Equal(t, 2, r.Offset())
+}
+
+func TestCursor_WriteOverflow(t *testing.T) {
+	buf := make([]byte, 3)
+	w := NewCursor(buf)
+	w.PutUint32(0xFFFFFFFF)
+	assert.ErrorIs(t, w.Err(), ErrCursorOverflow)
+	assert.Equal(t, []byte{0, 0, 0}, buf, "a failed write must not modify the buffer")
+}
+
+func TestCursor_NegativeLength(t *testing.T) {
+	r := NewCursor([]byte{1})
+	assert.Nil(t, r.Bytes(-1))
+	assert.ErrorIs(t, r.Err(), ErrCursorOverflow)
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrInjected is the error returned by the readers and writers from
+// FailAfterReader and FailAfterWriter once their byte budget is spent.
+var ErrInjected = errors.New("utils: injected failure")
+
+// FailAfterReader returns a reader that reads from r until n bytes have been
+// returned and then fails every call with ErrInjected. If r ends first its
+// own io.EOF is returned. It is meant for tests that check error handling at
+// every offset of a stream; unlike a truncated input the failure is a read
+// error, not an end of data.
+func FailAfterReader(r io.Reader, n int) io.Reader {
+	return &failAfterReader{r: r, remaining: n}
+}
+
+type failAfterReader struct {
+	r         io.Reader
+	remaining int
+}
+
+func (f *failAfterReader) Read(p []byte) (int, error) {
+	if f.remaining <= 0 {
+		return 0, ErrInjected
+	}
+
+	if len(p) > f.remaining {
+		p = p[:f.remaining]
+	}
+
+	n, err := f.r.Read(p)
+	f.remaining -= n
+	return n, err
+}
+
+// FailAfterWriter returns a writer that passes the first n bytes through to w
+// and then fails with ErrInjected. A write that crosses the limit writes the
+// bytes that fit and returns their count with ErrInjected, as a short write
+// would.
+func FailAfterWriter(w io.Writer, n int) io.Writer {
+	return &failAfterWriter{w: w, remaining: n}
+}
+
+type failAfterWriter struct {
+	w         io.Writer
+	remaining int
+}
+
+func (f *failAfterWriter) Write(p []byte) (int, error) {
+	if len(p) <= f.remaining {
+		n, err := f.w.Write(p)
+		f.remaining -= n
+		return n, err
+	}
+
+	n, err := f.w.Write(p[:max(f.remaining, 0)])
+	f.remaining -= n
+	if err != nil {
+		return n, err
+	}
+
+	return n, ErrInjected
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailAfterReader(t *testing.T) {
+	data := []byte("0123456789")
+	for n := range len(data) {
+		got, err := io.ReadAll(FailAfterReader(bytes.NewReader(data), n))
+		assert.ErrorIs(t, err, ErrInjected, "n=%d", n)
+		assert.Equal(t, data[:n], got, "n=%d", n)
+	}
+}
+
+func TestFailAfterReader_SourceEndsFirst(t *testing.T) {
+	got, err := io.ReadAll(FailAfterReader(bytes.NewReader([]byte("abc")), 10))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abc"), got)
+}
+
+func TestFailAfterWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := FailAfterWriter(&buf, 5)
+
+	n, err := w.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = w.Write([]byte("defg"))
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "abcde", buf.String())
+
+	n, err = w.Write([]byte("h"))
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.Zero(t, n)
+	assert.Equal(t, "abcde", buf.String())
+}
+
+func TestFailAfterWriter_ZeroBudget(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := FailAfterWriter(&buf, 0).Write([]byte("x"))
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.Zero(t, n)
+	assert.Zero(t, buf.Len())
+}
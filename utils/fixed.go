@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrNotFixedSize is returned by ReadFixed when T has no fixed binary size
+// (for example because it contains a slice or string).
+var ErrNotFixedSize = errors.New("utils: type has no fixed binary size")
+
+// ReadFixed reads exactly binary.Size(T) bytes from r and decodes them into a
+// T. If *T implements encoding.BinaryUnmarshaler its UnmarshalBinary is used,
+// otherwise the bytes are decoded little-endian with encoding/binary. A
+// stream that is empty or ends part-way through the value returns
+// io.ErrUnexpectedEOF, never a bare io.EOF, so callers that need to tell "no
+// more records" apart from "corrupt record" must check for end of stream
+// themselves.
+func ReadFixed[T any](r io.Reader) (T, error) {
+	var v T
+	size := binary.Size(v)
+	if size < 0 {
+		return v, ErrNotFixedSize
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			return v, io.ErrUnexpectedEOF
+		}
+
+		return v, err
+	}
+
+	if u, ok := any(&v).(encoding.BinaryUnmarshaler); ok {
+		if err := u.UnmarshalBinary(buf); err != nil {
+			var zero T
+			return zero, err
+		}
+
+		return v, nil
+	}
+
+	if _, err := binary.Decode(buf, binary.LittleEndian, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return v, nil
+}
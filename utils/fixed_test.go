@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedRecord struct {
+	ID    uint16
+	Level byte
+	HP    uint32
+}
+
+// swappedRecord decodes its two bytes in reverse order so tests can tell
+// whether UnmarshalBinary was used.
+type swappedRecord struct {
+	A, B byte
+}
+
+func (s *swappedRecord) UnmarshalBinary(b []byte) error {
+	if b[0] == 0xEE {
+		return errors.New("bad record")
+	}
+
+	s.A, s.B = b[1], b[0]
+	return nil
+}
+
+func TestReadFixed(t *testing.T) {
+	r := bytes.NewReader([]byte{0x34, 0x12, 7, 0x10, 0x27, 0, 0, 0xAA})
+	v, err := ReadFixed[fixedRecord](r)
+	require.NoError(t, err)
+	assert.Equal(t, fixedRecord{ID: 0x1234, Level: 7, HP: 10000}, v)
+	assert.Equal(t, 1, r.Len(), "only the value's bytes are consumed")
+}
+
+func TestReadFixed_Truncation(t *testing.T) {
+	_, err := ReadFixed[fixedRecord](bytes.NewReader(nil))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	_, err = ReadFixed[fixedRecord](bytes.NewReader([]byte{1, 2, 3}))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestReadFixed_UsesUnmarshalBinary(t *testing.T) {
+	v, err := ReadFixed[swappedRecord](bytes.NewReader([]byte{1, 2}))
+	require.NoError(t, err)
+	assert.Equal(t, swappedRecord{A: 2, B: 1}, v)
+
+	v, err = ReadFixed[swappedRecord](bytes.NewReader([]byte{0xEE, 2}))
+	assert.EqualError(t, err, "bad record")
+	assert.Zero(t, v)
+}
+
+func TestReadFixed_NotFixedSize(t *testing.T) {
+	_, err := ReadFixed[struct{ Name string }](bytes.NewReader([]byte{1}))
+	assert.ErrorIs(t, err, ErrNotFixedSize)
+}
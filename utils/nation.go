@@ -10,3 +10,19 @@ func GetNationName(nation byte) string {
 		return "Temoz"
 	}
 }
+
+// ResolveNations maps each nation ID in ids to its display name, as
+// GetNationName does, and reports the indices of IDs that are not a known
+// nation (0 or 1) and so fell through to the Temoz default. names always has
+// the same length as ids; unknown is nil when every ID is known.
+func ResolveNations(ids []byte) (names []string, unknown []int) {
+	names = make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = GetNationName(id)
+		if id > 1 {
+			unknown = append(unknown, i)
+		}
+	}
+
+	return names, unknown
+}
@@ -24,3 +24,13 @@ func TestGetNationName(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveNations(t *testing.T) {
+	names, unknown := ResolveNations([]byte{1, 0, 2, 1})
+	assert.Equal(t, []string{"Quanato", "Temoz", "Temoz", "Quanato"}, names)
+	assert.Equal(t, []int{2}, unknown)
+
+	names, unknown = ResolveNations(nil)
+	assert.Empty(t, names)
+	assert.Nil(t, unknown)
+}
@@ -0,0 +1,20 @@
+package utils
+
+// Problem is a machine-readable validation finding. Code is stable across
+// releases and identifies the kind of problem (for example
+// "questfile.name_length_mismatch"), so tooling can allow-list known-benign
+// findings. Field names the offending field, Index is the position of the
+// offending element within a list (-1 when the problem is not tied to one),
+// and Message is the human-readable description.
+type Problem struct {
+	Code    string `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// Error returns the human-readable message, so a Problem can also be used as
+// an error.
+func (p Problem) Error() string {
+	return p.Message
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblem_JSON(t *testing.T) {
+	p := Problem{Code: "pkg.code", Field: "Name", Index: 2, Message: "bad name"}
+	b, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"code":"pkg.code","field":"Name","index":2,"message":"bad name"}`, string(b))
+	assert.Equal(t, "bad name", p.Error())
+}
+
+func TestProblem_JSONOmitsEmptyField(t *testing.T) {
+	b, err := json.Marshal(Problem{Code: "pkg.code", Index: -1, Message: "m"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"code":"pkg.code","index":-1,"message":"m"}`, string(b))
+}
@@ -0,0 +1,22 @@
+package utils
+
+import "math/rand/v2"
+
+// NewRand returns a generator whose output is fully determined by seed, for
+// the RandomN content generators of the file-format packages.
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), 0))
+}
+
+// RandomName fills field with a random capitalized ASCII name followed by
+// null padding. The name is 1 to len(field)-1 bytes long, so it is always
+// null-terminated; field must be at least 2 bytes.
+func RandomName(rng *rand.Rand, field []byte) {
+	n := 1 + rng.IntN(min(len(field)-1, 12))
+	field[0] = byte('A' + rng.IntN(26))
+	for i := 1; i < n; i++ {
+		field[i] = byte('a' + rng.IntN(26))
+	}
+
+	clear(field[n:])
+}
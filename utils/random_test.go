@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomName(t *testing.T) {
+	a, b := make([]byte, 0x14), make([]byte, 0x14)
+	RandomName(NewRand(7), a)
+	RandomName(NewRand(7), b)
+	assert.Equal(t, a, b, "same seed must give the same name")
+
+	rng := NewRand(1)
+	for range 100 {
+		field := make([]byte, 4)
+		RandomName(rng, field)
+		name, terminated := ReadStringChecked(field)
+		assert.True(t, terminated)
+		assert.NotEmpty(t, name)
+	}
+}
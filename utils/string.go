@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+)
+
+// ReadStringChecked returns the string stored in a fixed-size, null-padded
+// field: the bytes before the first null, or the whole field if it has none.
+// terminated reports whether a null was found. A field filled to the end with
+// no terminator decodes the same as with ReadStringFromBytes, but is often a
+// sign of corruption, so validators can use terminated to flag it.
+func ReadStringChecked(b []byte) (s string, terminated bool) {
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return string(b), false
+	}
+
+	return string(b[:i]), true
+}
+
+// NameEncoding selects how GetFixedString and SetFixedString convert between
+// a fixed-size name field and a Go string.
+type NameEncoding uint8
+
+// Name encodings.
+const (
+	// EncodingRaw copies bytes unchanged, as ReadStringFromBytes and
+	// MakeFixedLengthStringBytes do. Use it for ASCII names such as those in
+	// server configuration bins.
+	EncodingRaw NameEncoding = iota
+
+	// EncodingCP949 converts between UTF-8 strings and CP949 field bytes, the
+	// code page of client-facing names.
+	EncodingCP949
+)
+
+// String returns "raw", "cp949" or "NameEncoding(n)".
+func (e NameEncoding) String() string {
+	switch e {
+	case EncodingRaw:
+		return "raw"
+	case EncodingCP949:
+		return "cp949"
+	default:
+		return "NameEncoding(" + strconv.Itoa(int(e)) + ")"
+	}
+}
+
+// ErrUnknownEncoding is returned by GetFixedString and SetFixedString for a
+// NameEncoding they do not support.
+var ErrUnknownEncoding = errors.New("utils: unknown name encoding")
+
+// GetFixedString decodes the null-padded name in field using enc. The name is
+// the bytes before the first null, or the whole field if it has none. With
+// EncodingRaw the bytes are returned as-is; with EncodingCP949 they are
+// converted to UTF-8.
+func GetFixedString(field []byte, enc NameEncoding) (string, error) {
+	name, _ := ReadStringChecked(field)
+	switch enc {
+	case EncodingRaw:
+		return name, nil
+	case EncodingCP949:
+		return DecodeCP949([]byte(name))
+	default:
+		return "", ErrUnknownEncoding
+	}
+}
+
+// SetFixedString stores s in field using enc and zeroes the rest of the
+// field. A name longer than the field is truncated; with EncodingCP949 it is
+// cut at the last whole character so a double-byte character is never split.
+// With EncodingCP949 a string CP949 cannot represent returns an error and
+// field is left unchanged.
+func SetFixedString(field []byte, s string, enc NameEncoding) error {
+	var name []byte
+	switch enc {
+	case EncodingRaw:
+		name = []byte(s)
+		name = name[:min(len(name), len(field))]
+	case EncodingCP949:
+		encoded, err := EncodeCP949(s)
+		if err != nil {
+			return err
+		}
+
+		name = truncateCP949(encoded, len(field))
+	default:
+		return ErrUnknownEncoding
+	}
+
+	n := copy(field, name)
+	clear(field[n:])
+	return nil
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadStringChecked(t *testing.T) {
+	tests := []struct {
+		name           string
+		in             []byte
+		want           string
+		wantTerminated bool
+	}{
+		{"padded", []byte{'W', 'o', 'l', 'f', 0, 0, 0}, "Wolf", true},
+		{"stops at first null", []byte{'a', 0, 'b', 0}, "a", true},
+		{"empty name", []byte{0, 0, 0}, "", true},
+		{"terminator in last byte", []byte{'a', 'b', 0}, "ab", true},
+		{"no terminator", []byte{'a', 'b', 'c'}, "abc", false},
+		{"empty field", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, terminated := ReadStringChecked(tt.in)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantTerminated, terminated)
+		})
+	}
+}
+
+func TestFixedString_Raw(t *testing.T) {
+	field := []byte{'o', 'l', 'd', 'n', 'a', 'm', 'e', 0}
+	require.NoError(t, SetFixedString(field, "Guard", EncodingRaw))
+	assert.Equal(t, []byte{'G', 'u', 'a', 'r', 'd', 0, 0, 0}, field)
+
+	got, err := GetFixedString(field, EncodingRaw)
+	require.NoError(t, err)
+	assert.Equal(t, "Guard", got)
+
+	require.NoError(t, SetFixedString(field, "LongerThanField", EncodingRaw))
+	assert.Equal(t, []byte("LongerTh"), field)
+}
+
+func TestFixedString_CP949(t *testing.T) {
+	field := make([]byte, 8)
+	require.NoError(t, SetFixedString(field, "경비병", EncodingCP949))
+	want, err := EncodeCP949("경비병")
+	require.NoError(t, err)
+	assert.Equal(t, append(want, 0, 0), field)
+
+	got, err := GetFixedString(field, EncodingCP949)
+	require.NoError(t, err)
+	assert.Equal(t, "경비병", got)
+
+	// Raw decoding of the same field returns the CP949 bytes untouched.
+	raw, err := GetFixedString(field, EncodingRaw)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), raw)
+}
+
+func TestFixedString_CP949TruncatesAtCharacter(t *testing.T) {
+	field := make([]byte, 5)
+	require.NoError(t, SetFixedString(field, "경비병", EncodingCP949))
+	got, err := GetFixedString(field, EncodingCP949)
+	require.NoError(t, err)
+	assert.Equal(t, "경비", got)
+	assert.Equal(t, byte(0), field[4])
+}
+
+func TestFixedString_Errors(t *testing.T) {
+	field := []byte("keep")
+	assert.Error(t, SetFixedString(field, "😀", EncodingCP949))
+	assert.Equal(t, []byte("keep"), field)
+
+	assert.ErrorIs(t, SetFixedString(field, "x", NameEncoding(9)), ErrUnknownEncoding)
+	_, err := GetFixedString(field, NameEncoding(9))
+	assert.ErrorIs(t, err, ErrUnknownEncoding)
+	assert.Equal(t, "NameEncoding(9)", NameEncoding(9).String())
+	assert.Equal(t, "cp949", EncodingCP949.String())
+}